@@ -5,12 +5,15 @@ import (
 	"crypto/sha256"
 	"encoding/gob"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/go-logr/stdr"
@@ -23,10 +26,14 @@ const (
 	updateEvent     = "update"
 	addEvent        = "add"
 	deleteEvent     = "delete"
+	registerEvent   = "register"
 	bufferSize      = 65536
 	columnDelimiter = ","
 )
 
+// ErrNotFound is returned when a lookup does not match any row in the cache
+var ErrNotFound = errors.New("not found")
+
 // ErrCacheInconsistent is an error that can occur when an operation
 // would cause the cache to be inconsistent
 type ErrCacheInconsistent struct {
@@ -67,6 +74,19 @@ func NewIndexExistsError(table string, value interface{}, index string, new, exi
 	}
 }
 
+// ErrMissingReference is returned when a reference column holds the UUID of a
+// row missing from the table it references
+type ErrMissingReference struct {
+	Table    string
+	Column   string
+	RefTable string
+	UUID     string
+}
+
+func (e *ErrMissingReference) Error() string {
+	return fmt.Sprintf("column %s of the %s table references row %s missing from the %s table", e.Column, e.Table, e.UUID, e.RefTable)
+}
+
 // map of unique values to uuids
 type valueToUUID map[interface{}]string
 
@@ -348,6 +368,12 @@ func (r *RowCache) RowsByCondition(conditions []ovsdb.Condition) (map[string]mod
 				panic(fmt.Sprintf("%+v is not an ovsdb uuid", ovsdbUUID))
 			}
 			uuid := ovsdbUUID.GoUUID
+			if condition.Function == ovsdb.ConditionEqual {
+				if row := r.Row(uuid); row != nil {
+					results[uuid] = row
+				}
+				continue
+			}
 			for rowUUID, row := range r.Rows() {
 				ok, err := condition.Function.Evaluate(rowUUID, uuid)
 				if err != nil {
@@ -357,13 +383,23 @@ func (r *RowCache) RowsByCondition(conditions []ovsdb.Condition) (map[string]mod
 					results[rowUUID] = row
 				}
 			}
-		} else if index, err := r.Index(condition.Column); err != nil {
-			for k, rowUUID := range index {
-				tSchema := schema.Columns[condition.Column]
-				nativeValue, err := ovsdb.OvsToNative(tSchema, condition.Value)
-				if err != nil {
-					return nil, err
+		} else if index, err := r.Index(condition.Column); err == nil {
+			tSchema := schema.Columns[condition.Column]
+			nativeValue, err := ovsdb.OvsToNative(tSchema, condition.Value)
+			if err != nil {
+				return nil, err
+			}
+			// the indexed value identifies the only row that can be equal to
+			// it, unless it is a pointer which is not comparable by value
+			if condition.Function == ovsdb.ConditionEqual && isIndexKey(nativeValue) {
+				if rowUUID, ok := index[nativeValue]; ok {
+					if row := r.Row(rowUUID); row != nil {
+						results[rowUUID] = row
+					}
 				}
+				continue
+			}
+			for k, rowUUID := range index {
 				ok, err := condition.Function.Evaluate(k, nativeValue)
 				if err != nil {
 					return nil, err
@@ -374,6 +410,14 @@ func (r *RowCache) RowsByCondition(conditions []ovsdb.Condition) (map[string]mod
 				}
 			}
 		} else {
+			column := schema.Column(condition.Column)
+			if column == nil {
+				return nil, fmt.Errorf("column %s not found in table %s", condition.Column, r.name)
+			}
+			nativeValue, err := ovsdb.OvsToNative(column, condition.Value)
+			if err != nil {
+				return nil, err
+			}
 			for uuid, row := range r.Rows() {
 				info, err := r.dbModel.NewModelInfo(row)
 				if err != nil {
@@ -383,7 +427,7 @@ func (r *RowCache) RowsByCondition(conditions []ovsdb.Condition) (map[string]mod
 				if err != nil {
 					return nil, err
 				}
-				ok, err := condition.Function.Evaluate(value, condition.Value)
+				ok, err := condition.Function.Evaluate(value, nativeValue)
 				if err != nil {
 					return nil, err
 				}
@@ -396,6 +440,12 @@ func (r *RowCache) RowsByCondition(conditions []ovsdb.Condition) (map[string]mod
 	return results, nil
 }
 
+// isIndexKey returns whether a native value can be looked up in an index
+func isIndexKey(value interface{}) bool {
+	vType := reflect.TypeOf(value)
+	return vType != nil && vType.Comparable() && vType.Kind() != reflect.Ptr
+}
+
 // Len returns the length of the cache
 func (r *RowCache) Len() int {
 	r.mutex.RLock()
@@ -420,6 +470,30 @@ type EventHandler interface {
 	OnDelete(table string, model model.Model)
 }
 
+// EventMetadata describes the monitor notification a cache event originates
+// from
+type EventMetadata struct {
+	// Database is the name of the database of the cache
+	Database string
+	// TransactionID is the id of the last transaction reflected by the
+	// notification, if it holds one, as the update3 notifications and the
+	// replies to monitor_cond_since do
+	TransactionID string
+	// Initial is whether the event is part of the initial dump of a monitor
+	Initial bool
+}
+
+// EventHandlerWithMetadata is an EventHandler that is also passed the
+// EventMetadata of the events. The cache calls its methods taking the metadata
+// in place of the ones of EventHandler. The events that AddEventHandlerWithReplay
+// replays carry the name of the database only
+type EventHandlerWithMetadata interface {
+	EventHandler
+	OnAddWithMetadata(table string, model model.Model, metadata EventMetadata)
+	OnUpdateWithMetadata(table string, old model.Model, new model.Model, metadata EventMetadata)
+	OnDeleteWithMetadata(table string, model model.Model, metadata EventMetadata)
+}
+
 // EventHandlerFuncs is a wrapper for the EventHandler interface
 // It allows a caller to only implement the functions they need
 type EventHandlerFuncs struct {
@@ -461,6 +535,74 @@ type TableCache struct {
 	ovsdb.NotificationHandler
 	mutex  sync.RWMutex
 	logger *logr.Logger
+	// filters holds the Filter of each table
+	filters map[string]Filter
+	// filtered holds the uuids of the rows excluded by the filters, per table
+	filtered map[string]map[string]struct{}
+}
+
+// Filter decides whether a row received in a monitor update belongs in the
+// cache. Rows for which it returns false are discarded, and no event is
+// generated for them
+type Filter func(model.Model) bool
+
+// GetByIndex returns the model of type T whose index matches the provided
+// values. The index is identified by the comma separated list of its columns,
+// as declared in the schema, and values must be given in the same order as
+// those columns. ErrNotFound is returned if no row matches.
+func GetByIndex[T model.Model](c *TableCache, indexName string, values ...interface{}) (T, error) {
+	var result T
+	mType := reflect.TypeOf(result)
+	if mType == nil || mType.Kind() != reflect.Ptr || mType.Elem().Kind() != reflect.Struct {
+		return result, fmt.Errorf("model type %v is not a pointer to a struct", mType)
+	}
+	dbModel := c.DatabaseModel()
+	tableName := dbModel.FindTable(mType)
+	if tableName == "" {
+		return result, fmt.Errorf("model type %v is not part of the database model", mType)
+	}
+	r := c.Table(tableName)
+	if r == nil {
+		return result, fmt.Errorf("table %s is not in the cache", tableName)
+	}
+	idx := index(indexName)
+	columns := idx.columns()
+	if len(columns) != len(values) {
+		return result, fmt.Errorf("index %s has %d columns but %d values were provided", indexName, len(columns), len(values))
+	}
+
+	// build a model holding the index values so that the index value is
+	// computed the same way the cache computes it
+	obj := reflect.New(mType.Elem()).Interface()
+	info, err := dbModel.NewModelInfo(obj)
+	if err != nil {
+		return result, err
+	}
+	for i, column := range columns {
+		if err := info.SetField(column, values[i]); err != nil {
+			return result, err
+		}
+	}
+	val, err := valueFromIndex(info, idx)
+	if err != nil {
+		return result, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	vals, ok := r.indexes[idx]
+	if !ok {
+		return result, fmt.Errorf("%s is not an index of table %s", indexName, tableName)
+	}
+	uuid, ok := vals[val]
+	if !ok {
+		return result, ErrNotFound
+	}
+	row := r.rowByUUID(uuid)
+	if row == nil {
+		return result, ErrNotFound
+	}
+	return row.(T), nil
 }
 
 // Data is the type for data that can be prepopulated in the cache
@@ -502,16 +644,58 @@ func NewTableCache(dbModel model.DatabaseModel, data Data, logger *logr.Logger)
 		mutex:          sync.RWMutex{},
 		errorChan:      make(chan error),
 		logger:         logger,
+		filters:        make(map[string]Filter),
+		filtered:       make(map[string]map[string]struct{}),
 	}, nil
 }
 
+// SetFilter sets the Filter applied to the rows of the given table before they
+// are added to the cache. With update notifications, whose modifications only
+// hold the columns that changed, a row excluded when inserted remains excluded
+// until it is deleted. A cached row that an update makes excluded is removed
+// from the cache, generating a delete event
+func (t *TableCache) SetFilter(table string, filter Filter) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if filter == nil {
+		delete(t.filters, table)
+		return
+	}
+	t.filters[table] = filter
+}
+
+// exclude returns whether the filter of the table excludes the row, keeping
+// track of the rows it excludes
+func (t *TableCache) exclude(table, uuid string, m model.Model) bool {
+	filter, ok := t.filters[table]
+	if !ok || filter(m) {
+		delete(t.filtered[table], uuid)
+		return false
+	}
+	if _, ok := t.filtered[table]; !ok {
+		t.filtered[table] = make(map[string]struct{})
+	}
+	t.filtered[table][uuid] = struct{}{}
+	return true
+}
+
+// excluded returns whether the row was excluded by the filter of the table
+func (t *TableCache) excluded(table, uuid string) bool {
+	_, ok := t.filtered[table][uuid]
+	return ok
+}
+
 // Mapper returns the mapper
 func (t *TableCache) Mapper() mapper.Mapper {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
 	return t.dbModel.Mapper
 }
 
 // DatabaseModel returns the DatabaseModelRequest
 func (t *TableCache) DatabaseModel() model.DatabaseModel {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
 	return t.dbModel
 }
 
@@ -525,6 +709,24 @@ func (t *TableCache) Table(name string) *RowCache {
 	return nil
 }
 
+// CheckReference returns an *ErrMissingReference if uuid, held by the given
+// column of table, is not the UUID of a row of refTable in the cache. The empty
+// and the named UUIDs, which reference the rows inserted by the same
+// transaction, are not checked
+func (t *TableCache) CheckReference(table, column, refTable, uuid string) error {
+	if uuid == "" || ovsdb.IsNamedUUID(uuid) {
+		return nil
+	}
+	rows := t.Table(refTable)
+	if rows == nil {
+		return fmt.Errorf("cannot check column %s of the %s table: table %s is not in the cache", column, table, refTable)
+	}
+	if rows.Row(uuid) == nil {
+		return &ErrMissingReference{Table: table, Column: column, RefTable: refTable, UUID: uuid}
+	}
+	return nil
+}
+
 // Tables returns a list of table names that are in the cache
 func (t *TableCache) Tables() []string {
 	t.mutex.RLock()
@@ -555,10 +757,16 @@ func (t *TableCache) Update(context interface{}, tableUpdates ovsdb.TableUpdates
 // this populates a channel with updates so they can be processed after the initial
 // state has been Populated
 func (t *TableCache) Update2(context interface{}, tableUpdates ovsdb.TableUpdates2) error {
+	return t.Update3(context, "", tableUpdates)
+}
+
+// Update3 is like Update2, for the update3 notifications of ovsdb-server.7,
+// whose events carry the id of the last transaction they reflect
+func (t *TableCache) Update3(context interface{}, lastTransactionID string, tableUpdates ovsdb.TableUpdates2) error {
 	if len(tableUpdates) == 0 {
 		return nil
 	}
-	if err := t.Populate2(tableUpdates); err != nil {
+	if err := t.Populate2WithMetadata(tableUpdates, EventMetadata{TransactionID: lastTransactionID}); err != nil {
 		t.logger.Error(err, "during libovsdb cache populate2")
 		t.errorChan <- NewErrCacheInconsistent(err.Error())
 		return err
@@ -584,8 +792,18 @@ func (t *TableCache) Disconnected() {
 
 // Populate adds data to the cache and places an event on the channel
 func (t *TableCache) Populate(tableUpdates ovsdb.TableUpdates) error {
+	return t.PopulateWithMetadata(tableUpdates, EventMetadata{})
+}
+
+// PopulateWithMetadata is like Populate, except that the events carry the
+// given metadata. The name of the database of the cache is used if the
+// metadata has none
+func (t *TableCache) PopulateWithMetadata(tableUpdates ovsdb.TableUpdates, metadata EventMetadata) error {
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
+	if metadata.Database == "" {
+		metadata.Database = t.dbModel.Schema.Name
+	}
 
 	for table := range t.dbModel.Types() {
 		updates, ok := tableUpdates[table]
@@ -601,13 +819,23 @@ func (t *TableCache) Populate(tableUpdates ovsdb.TableUpdates) error {
 				if err != nil {
 					return err
 				}
+				if t.exclude(table, uuid, newModel) {
+					logger.V(5).Info("filtering row", "model", fmt.Sprintf("%+v", newModel))
+					if existing := tCache.Row(uuid); existing != nil {
+						if err := tCache.Delete(uuid); err != nil {
+							return err
+						}
+						t.eventProcessor.addEvent(deleteEvent, table, existing, nil, metadata)
+					}
+					continue
+				}
 				if existing := tCache.Row(uuid); existing != nil {
 					if !model.Equal(newModel, existing) {
 						logger.V(5).Info("updating row", "old:", fmt.Sprintf("%+v", existing), "new", fmt.Sprintf("%+v", newModel))
 						if err := tCache.Update(uuid, newModel, false); err != nil {
 							return err
 						}
-						t.eventProcessor.AddEvent(updateEvent, table, existing, newModel)
+						t.eventProcessor.addEvent(updateEvent, table, existing, newModel, metadata)
 					}
 					// no diff
 					continue
@@ -616,9 +844,13 @@ func (t *TableCache) Populate(tableUpdates ovsdb.TableUpdates) error {
 				if err := tCache.Create(uuid, newModel, false); err != nil {
 					return err
 				}
-				t.eventProcessor.AddEvent(addEvent, table, nil, newModel)
+				t.eventProcessor.addEvent(addEvent, table, nil, newModel, metadata)
 				continue
 			} else {
+				if t.excluded(table, uuid) {
+					delete(t.filtered[table], uuid)
+					continue
+				}
 				oldModel, err := t.CreateModel(table, row.Old, uuid)
 				if err != nil {
 					return err
@@ -627,7 +859,7 @@ func (t *TableCache) Populate(tableUpdates ovsdb.TableUpdates) error {
 				if err := tCache.Delete(uuid); err != nil {
 					return err
 				}
-				t.eventProcessor.AddEvent(deleteEvent, table, oldModel, nil)
+				t.eventProcessor.addEvent(deleteEvent, table, oldModel, nil, metadata)
 				continue
 			}
 		}
@@ -637,8 +869,18 @@ func (t *TableCache) Populate(tableUpdates ovsdb.TableUpdates) error {
 
 // Populate2 adds data to the cache and places an event on the channel
 func (t *TableCache) Populate2(tableUpdates ovsdb.TableUpdates2) error {
+	return t.Populate2WithMetadata(tableUpdates, EventMetadata{})
+}
+
+// Populate2WithMetadata is like Populate2, except that the events carry the
+// given metadata. The name of the database of the cache is used if the
+// metadata has none
+func (t *TableCache) Populate2WithMetadata(tableUpdates ovsdb.TableUpdates2, metadata EventMetadata) error {
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
+	if metadata.Database == "" {
+		metadata.Database = t.dbModel.Schema.Name
+	}
 	for table := range t.dbModel.Types() {
 		updates, ok := tableUpdates[table]
 		if !ok {
@@ -654,22 +896,33 @@ func (t *TableCache) Populate2(tableUpdates ovsdb.TableUpdates2) error {
 				if err != nil {
 					return err
 				}
+				if t.exclude(table, uuid, m) {
+					logger.V(5).Info("filtering row", "model", fmt.Sprintf("%+v", m))
+					continue
+				}
 				logger.V(5).Info("creating row", "model", fmt.Sprintf("%+v", m))
 				if err := tCache.Create(uuid, m, false); err != nil {
 					return err
 				}
-				t.eventProcessor.AddEvent(addEvent, table, nil, m)
+				t.eventProcessor.addEvent(addEvent, table, nil, m, metadata)
 			case row.Insert != nil:
 				m, err := t.CreateModel(table, row.Insert, uuid)
 				if err != nil {
 					return err
 				}
+				if t.exclude(table, uuid, m) {
+					logger.V(5).Info("filtering row", "model", fmt.Sprintf("%+v", m))
+					continue
+				}
 				logger.V(5).Info("inserting row", "model", fmt.Sprintf("%+v", m))
 				if err := tCache.Create(uuid, m, false); err != nil {
 					return err
 				}
-				t.eventProcessor.AddEvent(addEvent, table, nil, m)
+				t.eventProcessor.addEvent(addEvent, table, nil, m, metadata)
 			case row.Modify != nil:
+				if t.excluded(table, uuid) {
+					continue
+				}
 				existing := tCache.Row(uuid)
 				if existing == nil {
 					return NewErrCacheInconsistent(fmt.Sprintf("row with uuid %s does not exist", uuid))
@@ -679,18 +932,30 @@ func (t *TableCache) Populate2(tableUpdates ovsdb.TableUpdates2) error {
 				if err != nil {
 					return fmt.Errorf("unable to apply row modifications: %v", err)
 				}
+				if t.exclude(table, uuid, modified) {
+					logger.V(5).Info("filtering row", "model", fmt.Sprintf("%+v", modified))
+					if err := tCache.Delete(uuid); err != nil {
+						return err
+					}
+					t.eventProcessor.addEvent(deleteEvent, table, existing, nil, metadata)
+					continue
+				}
 				if !model.Equal(modified, existing) {
 					logger.V(5).Info("updating row", "old", fmt.Sprintf("%+v", existing), "new", fmt.Sprintf("%+v", modified))
 					if err := tCache.Update(uuid, modified, false); err != nil {
 						return err
 					}
-					t.eventProcessor.AddEvent(updateEvent, table, existing, modified)
+					t.eventProcessor.addEvent(updateEvent, table, existing, modified, metadata)
 				}
 			case row.Delete != nil:
 				fallthrough
 			default:
 				// If everything else is nil (including Delete because it's a key with
 				// no value on the wire), then process a delete
+				if t.excluded(table, uuid) {
+					delete(t.filtered[table], uuid)
+					continue
+				}
 				m := tCache.Row(uuid)
 				if m == nil {
 					return NewErrCacheInconsistent(fmt.Sprintf("row with uuid %s does not exist", uuid))
@@ -699,7 +964,7 @@ func (t *TableCache) Populate2(tableUpdates ovsdb.TableUpdates2) error {
 				if err := tCache.Delete(uuid); err != nil {
 					return err
 				}
-				t.eventProcessor.AddEvent(deleteEvent, table, m, nil)
+				t.eventProcessor.addEvent(deleteEvent, table, m, nil, metadata)
 			}
 		}
 	}
@@ -712,6 +977,7 @@ func (t *TableCache) Purge(dbModel model.DatabaseModel) {
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
 	t.dbModel = dbModel
+	t.filtered = make(map[string]map[string]struct{})
 	tableTypes := t.dbModel.Types()
 	for name := range t.dbModel.Schema.Tables {
 		t.cache[name] = newRowCache(name, t.dbModel, tableTypes[name])
@@ -723,6 +989,36 @@ func (t *TableCache) AddEventHandler(handler EventHandler) {
 	t.eventProcessor.AddEventHandler(handler)
 }
 
+// AddEventHandlerWithReplay registers the supplied EventHandler to receive
+// cache events, after replaying the current content of the cache to it as add
+// events. The replay happens in the event processing loop, between the events
+// that are already reflected in the cache and the following ones, so the
+// handler gets a list-then-watch view of the cache that neither misses nor
+// duplicates rows
+func (t *TableCache) AddEventHandlerWithReplay(handler EventHandler) {
+	for {
+		t.mutex.RLock()
+		tables := make([]string, 0, len(t.cache))
+		for table := range t.cache {
+			tables = append(tables, table)
+		}
+		sort.Strings(tables)
+		var replay []event
+		for _, table := range tables {
+			for _, m := range t.cache[table].RowsShallow() {
+				replay = append(replay, event{eventType: addEvent, table: table, new: m, metadata: EventMetadata{Database: t.dbModel.Schema.Name}})
+			}
+		}
+		registered := t.eventProcessor.addRegisterEvent(handler, replay)
+		t.mutex.RUnlock()
+		if registered {
+			return
+		}
+		// the event buffer is full, let the processing loop make room
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
 // Run starts the event processing and update processing loops.
 // It blocks until the stop channel is closed.
 // Once closed, it clears the updates/updates2 channels to ensure we don't process stale updates on a new connection
@@ -777,6 +1073,11 @@ type event struct {
 	table     string
 	old       model.Model
 	new       model.Model
+	metadata  EventMetadata
+	// the handler to register, and the add events to replay to it first,
+	// for register events
+	handler EventHandler
+	replay  []event
 }
 
 // eventProcessor handles the queueing and processing of cache events
@@ -810,6 +1111,11 @@ func (e *eventProcessor) AddEventHandler(handler EventHandler) {
 
 // AddEvent writes an event to the channel
 func (e *eventProcessor) AddEvent(eventType string, table string, old model.Model, new model.Model) {
+	e.addEvent(eventType, table, old, new, EventMetadata{})
+}
+
+// addEvent writes an event carrying metadata to the channel
+func (e *eventProcessor) addEvent(eventType string, table string, old model.Model, new model.Model, metadata EventMetadata) {
 	// We don't need to check for error here since there
 	// is only a single writer. RPC is run in blocking mode
 	event := event{
@@ -817,6 +1123,7 @@ func (e *eventProcessor) AddEvent(eventType string, table string, old model.Mode
 		table:     table,
 		old:       old,
 		new:       new,
+		metadata:  metadata,
 	}
 	select {
 	case e.events <- event:
@@ -827,6 +1134,18 @@ func (e *eventProcessor) AddEvent(eventType string, table string, old model.Mode
 	}
 }
 
+// addRegisterEvent writes an event registering handler to the channel, once
+// the events of replay have been dispatched to it. It returns false if the
+// channel is full
+func (e *eventProcessor) addRegisterEvent(handler EventHandler, replay []event) bool {
+	select {
+	case e.events <- event{eventType: registerEvent, handler: handler, replay: replay}:
+		return true
+	default:
+		return false
+	}
+}
+
 // Run runs the eventProcessor loop.
 // It will block until the stopCh has been closed
 // Otherwise it will wait for events to arrive on the event channel
@@ -838,21 +1157,46 @@ func (e *eventProcessor) Run(stopCh <-chan struct{}) {
 			return
 		case event := <-e.events:
 			e.handlersMutex.Lock()
-			for _, handler := range e.handlers {
-				switch event.eventType {
-				case addEvent:
-					handler.OnAdd(event.table, event.new)
-				case updateEvent:
-					handler.OnUpdate(event.table, event.old, event.new)
-				case deleteEvent:
-					handler.OnDelete(event.table, event.old)
+			if event.eventType == registerEvent {
+				for _, add := range event.replay {
+					dispatch(event.handler, add)
 				}
+				e.handlers = append(e.handlers, event.handler)
+				e.handlersMutex.Unlock()
+				continue
+			}
+			for _, handler := range e.handlers {
+				dispatch(handler, event)
 			}
 			e.handlersMutex.Unlock()
 		}
 	}
 }
 
+// dispatch calls the method of handler handling the event, passing it the
+// metadata of the event if it is an EventHandlerWithMetadata
+func dispatch(handler EventHandler, event event) {
+	if withMetadata, ok := handler.(EventHandlerWithMetadata); ok {
+		switch event.eventType {
+		case addEvent:
+			withMetadata.OnAddWithMetadata(event.table, event.new, event.metadata)
+		case updateEvent:
+			withMetadata.OnUpdateWithMetadata(event.table, event.old, event.new, event.metadata)
+		case deleteEvent:
+			withMetadata.OnDeleteWithMetadata(event.table, event.old, event.metadata)
+		}
+		return
+	}
+	switch event.eventType {
+	case addEvent:
+		handler.OnAdd(event.table, event.new)
+	case updateEvent:
+		handler.OnUpdate(event.table, event.old, event.new)
+	case deleteEvent:
+		handler.OnDelete(event.table, event.old)
+	}
+}
+
 // CreateModel creates a new Model instance based on the Row information
 func (t *TableCache) CreateModel(tableName string, row *ovsdb.Row, uuid string) (model.Model, error) {
 	if !t.dbModel.Valid() {
@@ -910,6 +1254,10 @@ func (t *TableCache) ApplyModifications(tableName string, base model.Model, upda
 
 		current, err := info.FieldByColumn(k)
 		if err != nil {
+			if k == "_version" {
+				// models are not required to map the _version column
+				continue
+			}
 			return err
 		}
 
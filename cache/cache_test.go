@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/ovn-org/libovsdb/model"
@@ -496,6 +497,97 @@ func TestRowCacheDelete(t *testing.T) {
 	}
 }
 
+func TestRowCacheRowsByCondition(t *testing.T) {
+	var schema ovsdb.DatabaseSchema
+	db, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{"Open_vSwitch": &testModel{}})
+	require.Nil(t, err)
+	err = json.Unmarshal([]byte(`
+		 {"name": "Open_vSwitch",
+		  "tables": {
+		    "Open_vSwitch": {
+			  "indexes": [["foo"]],
+		      "columns": {
+		        "foo": {
+			  "type": "string"
+			},
+			"bar": {
+				"type": "string"
+			  }
+		      }
+		    }
+		 }
+	     }
+	`), &schema)
+	require.Nil(t, err)
+	testData := Data{
+		"Open_vSwitch": map[string]model.Model{
+			"uuid1": &testModel{UUID: "uuid1", Foo: "foo1", Bar: "bar"},
+			"uuid2": &testModel{UUID: "uuid2", Foo: "foo2", Bar: "bar"},
+			"uuid3": &testModel{UUID: "uuid3", Foo: "foo3", Bar: "baz"},
+		},
+	}
+	dbModel, errs := model.NewDatabaseModel(schema, db)
+	require.Empty(t, errs)
+	tc, err := NewTableCache(dbModel, testData, nil)
+	require.Nil(t, err)
+
+	tests := []struct {
+		name      string
+		condition ovsdb.Condition
+		expected  []string
+	}{
+		{
+			"indexed column",
+			ovsdb.NewCondition("foo", ovsdb.ConditionEqual, "foo2"),
+			[]string{"uuid2"},
+		},
+		{
+			"indexed column, no match",
+			ovsdb.NewCondition("foo", ovsdb.ConditionEqual, "quux"),
+			[]string{},
+		},
+		{
+			"indexed column, not equal",
+			ovsdb.NewCondition("foo", ovsdb.ConditionNotEqual, "foo2"),
+			[]string{"uuid1", "uuid3"},
+		},
+		{
+			"uuid",
+			ovsdb.NewCondition("_uuid", ovsdb.ConditionEqual, ovsdb.UUID{GoUUID: "uuid3"}),
+			[]string{"uuid3"},
+		},
+		{
+			"non indexed column",
+			ovsdb.NewCondition("bar", ovsdb.ConditionEqual, "bar"),
+			[]string{"uuid1", "uuid2"},
+		},
+		{
+			"non indexed column, not equal",
+			ovsdb.NewCondition("bar", ovsdb.ConditionNotEqual, "bar"),
+			[]string{"uuid3"},
+		},
+		{
+			"no match",
+			ovsdb.NewCondition("bar", ovsdb.ConditionEqual, "quux"),
+			[]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := tc.Table("Open_vSwitch").RowsByCondition([]ovsdb.Condition{tt.condition})
+			require.NoError(t, err)
+			uuids := []string{}
+			for uuid := range rows {
+				uuids = append(uuids, uuid)
+			}
+			assert.ElementsMatch(t, tt.expected, uuids)
+		})
+	}
+
+	_, err = tc.Table("Open_vSwitch").RowsByCondition([]ovsdb.Condition{ovsdb.NewCondition("quux", ovsdb.ConditionEqual, "bar")})
+	assert.Error(t, err)
+}
+
 func TestEventHandlerFuncs_OnAdd(t *testing.T) {
 	calls := 0
 	type fields struct {
@@ -683,6 +775,46 @@ func TestTableCacheTable(t *testing.T) {
 	}
 }
 
+func TestTableCacheCheckReference(t *testing.T) {
+	db, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{"Open_vSwitch": &testModel{}})
+	require.NoError(t, err)
+	var schema ovsdb.DatabaseSchema
+	err = json.Unmarshal([]byte(`
+		 {"name": "Open_vSwitch",
+		  "tables": {
+		    "Open_vSwitch": {
+		      "columns": {
+		        "foo": {
+			  "type": "string"
+			},
+			"bar": {
+				"type": "string"
+			  }
+		      }
+		    }
+		 }
+	     }
+	`), &schema)
+	require.NoError(t, err)
+	dbModel, errs := model.NewDatabaseModel(schema, db)
+	require.Empty(t, errs)
+	tc, err := NewTableCache(dbModel, nil, nil)
+	require.NoError(t, err)
+	existing := "8f5cd1a0-7d44-4b94-9e4b-3e6e8ca5d3d1"
+	missing := "1d8b0a2e-35bd-4dc4-8fd6-2a3e1c8b5a11"
+	err = tc.Table("Open_vSwitch").Create(existing, &testModel{UUID: existing, Foo: "foo"}, false)
+	require.NoError(t, err)
+
+	for _, uuid := range []string{existing, "", "named"} {
+		assert.NoError(t, tc.CheckReference("Bridge", "ovs", "Open_vSwitch", uuid), uuid)
+	}
+	err = tc.CheckReference("Bridge", "ovs", "Open_vSwitch", missing)
+	assert.Equal(t, &ErrMissingReference{Table: "Bridge", Column: "ovs", RefTable: "Open_vSwitch", UUID: missing}, err)
+	assert.EqualError(t, err, "column ovs of the Bridge table references row "+missing+" missing from the Open_vSwitch table")
+	err = tc.CheckReference("Bridge", "ports", "Port", missing)
+	assert.EqualError(t, err, "cannot check column ports of the Bridge table: table Port is not in the cache")
+}
+
 func TestTableCacheTables(t *testing.T) {
 	db, err := model.NewClientDBModel("TestDB",
 		map[string]model.Model{
@@ -1068,6 +1200,197 @@ func TestTableCachePopulate2BrokenIndexes(t *testing.T) {
 	require.NotNil(t, result)
 }
 
+func TestTableCacheAddEventHandlerWithReplay(t *testing.T) {
+	db, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{"Open_vSwitch": &testModel{}})
+	assert.Nil(t, err)
+	var schema ovsdb.DatabaseSchema
+	err = json.Unmarshal([]byte(`
+		 {"name": "Open_vSwitch",
+		  "tables": {
+		    "Open_vSwitch": {
+		      "columns": {
+		        "foo": {
+			  "type": "string"
+			},
+			"bar": {
+				"type": "string"
+			  }
+		      }
+		    }
+		 }
+	     }
+	`), &schema)
+	assert.Nil(t, err)
+	dbModel, errs := model.NewDatabaseModel(schema, db)
+	require.Empty(t, errs)
+	tc, err := NewTableCache(dbModel, nil, nil)
+	assert.Nil(t, err)
+
+	// the add events of the initial rows are still queued when the handler
+	// is registered
+	foo := ovsdb.Row(map[string]interface{}{"_uuid": "foo", "foo": "foo"})
+	bar := ovsdb.Row(map[string]interface{}{"_uuid": "bar", "foo": "bar"})
+	err = tc.Populate2(ovsdb.TableUpdates2{
+		"Open_vSwitch": {
+			"foo": &ovsdb.RowUpdate2{Initial: &foo},
+			"bar": &ovsdb.RowUpdate2{Initial: &bar},
+		},
+	})
+	require.NoError(t, err)
+
+	received := make(chan string, 10)
+	tc.AddEventHandlerWithReplay(&EventHandlerFuncs{
+		AddFunc: func(table string, m model.Model) {
+			received <- "add " + m.(*testModel).UUID
+		},
+		UpdateFunc: func(table string, old, new model.Model) {
+			received <- "update " + new.(*testModel).Foo
+		},
+	})
+
+	modify := ovsdb.Row(map[string]interface{}{"foo": "modified"})
+	err = tc.Populate2(ovsdb.TableUpdates2{
+		"Open_vSwitch": {
+			"foo": &ovsdb.RowUpdate2{Modify: &modify},
+		},
+	})
+	require.NoError(t, err)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go tc.Run(stopCh)
+
+	var events []string
+	for len(events) < 3 {
+		select {
+		case e := <-received:
+			events = append(events, e)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("missing events, got %v", events)
+		}
+	}
+	// every row is replayed once, before the live events
+	assert.ElementsMatch(t, []string{"add foo", "add bar"}, events[:2])
+	assert.Equal(t, "update modified", events[2])
+	select {
+	case e := <-received:
+		t.Fatalf("unexpected event %s", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestTableCacheFilter(t *testing.T) {
+	db, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{"Open_vSwitch": &testModel{}})
+	assert.Nil(t, err)
+	var schema ovsdb.DatabaseSchema
+	err = json.Unmarshal([]byte(`
+		 {"name": "Open_vSwitch",
+		  "tables": {
+		    "Open_vSwitch": {
+		      "columns": {
+		        "foo": {
+			  "type": "string"
+			},
+			"bar": {
+				"type": "string"
+			  }
+		      }
+		    }
+		 }
+	     }
+	`), &schema)
+	assert.Nil(t, err)
+	dbModel, errs := model.NewDatabaseModel(schema, db)
+	require.Empty(t, errs)
+	tc, err := NewTableCache(dbModel, nil, nil)
+	assert.Nil(t, err)
+	tc.SetFilter("Open_vSwitch", func(m model.Model) bool {
+		return m.(*testModel).Bar != "ignored"
+	})
+
+	// drain returns the events generated so far
+	drain := func() []event {
+		var events []event
+		for len(tc.eventProcessor.events) > 0 {
+			events = append(events, <-tc.eventProcessor.events)
+		}
+		return events
+	}
+
+	t.Log("Initial")
+	kept := ovsdb.Row(map[string]interface{}{"_uuid": "kept", "foo": "kept"})
+	filtered := ovsdb.Row(map[string]interface{}{"_uuid": "filtered", "foo": "filtered", "bar": "ignored"})
+	err = tc.Populate2(ovsdb.TableUpdates2{
+		"Open_vSwitch": {
+			"kept":     &ovsdb.RowUpdate2{Initial: &kept},
+			"filtered": &ovsdb.RowUpdate2{Initial: &filtered},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &testModel{UUID: "kept", Foo: "kept"}, tc.Table("Open_vSwitch").Row("kept"))
+	assert.Nil(t, tc.Table("Open_vSwitch").Row("filtered"))
+	events := drain()
+	require.Len(t, events, 1)
+	assert.Equal(t, addEvent, events[0].eventType)
+	assert.Equal(t, &testModel{UUID: "kept", Foo: "kept"}, events[0].new)
+
+	t.Log("Modify filtered row")
+	modify := ovsdb.Row(map[string]interface{}{"foo": "modified"})
+	err = tc.Populate2(ovsdb.TableUpdates2{
+		"Open_vSwitch": {
+			"filtered": &ovsdb.RowUpdate2{Modify: &modify},
+		},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, tc.Table("Open_vSwitch").Row("filtered"))
+	assert.Empty(t, drain())
+
+	t.Log("Modify kept row into a filtered one")
+	modify = ovsdb.Row(map[string]interface{}{"bar": "ignored"})
+	err = tc.Populate2(ovsdb.TableUpdates2{
+		"Open_vSwitch": {
+			"kept": &ovsdb.RowUpdate2{Modify: &modify},
+		},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, tc.Table("Open_vSwitch").Row("kept"))
+	events = drain()
+	require.Len(t, events, 1)
+	assert.Equal(t, deleteEvent, events[0].eventType)
+	assert.Equal(t, &testModel{UUID: "kept", Foo: "kept"}, events[0].old)
+
+	t.Log("Delete filtered rows")
+	err = tc.Populate2(ovsdb.TableUpdates2{
+		"Open_vSwitch": {
+			"kept":     &ovsdb.RowUpdate2{Delete: &ovsdb.Row{}},
+			"filtered": &ovsdb.RowUpdate2{Delete: &ovsdb.Row{}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, drain())
+	assert.Empty(t, tc.filtered["Open_vSwitch"])
+
+	t.Log("Populate")
+	inserted := ovsdb.Row(map[string]interface{}{"_uuid": "inserted", "foo": "inserted", "bar": "ignored"})
+	err = tc.Populate(ovsdb.TableUpdates{
+		"Open_vSwitch": {
+			"inserted": &ovsdb.RowUpdate{New: &inserted},
+		},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, tc.Table("Open_vSwitch").Row("inserted"))
+	assert.Empty(t, drain())
+
+	err = tc.Populate(ovsdb.TableUpdates{
+		"Open_vSwitch": {
+			"inserted": &ovsdb.RowUpdate{Old: &inserted},
+		},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, drain())
+	assert.Equal(t, 0, tc.Table("Open_vSwitch").Len())
+}
+
 func TestEventProcessor_AddEvent(t *testing.T) {
 	logger := logr.Discard()
 	ep := newEventProcessor(16, &logger)
@@ -1099,6 +1422,81 @@ func TestEventProcessor_AddEvent(t *testing.T) {
 	assert.Equal(t, 0, len(ep.events))
 }
 
+type testMetadataHandler struct {
+	EventHandlerFuncs
+	events chan string
+}
+
+func (h *testMetadataHandler) OnAddWithMetadata(table string, m model.Model, metadata EventMetadata) {
+	h.events <- fmt.Sprintf("add %s %+v", m.(*testModel).UUID, metadata)
+}
+
+func (h *testMetadataHandler) OnUpdateWithMetadata(table string, old, new model.Model, metadata EventMetadata) {
+	h.events <- fmt.Sprintf("update %s %+v", new.(*testModel).UUID, metadata)
+}
+
+func (h *testMetadataHandler) OnDeleteWithMetadata(table string, m model.Model, metadata EventMetadata) {
+	h.events <- fmt.Sprintf("delete %s %+v", m.(*testModel).UUID, metadata)
+}
+
+func TestEventProcessorMetadata(t *testing.T) {
+	logger := logr.Discard()
+	ep := newEventProcessor(16, &logger)
+	plain := make(chan string, 16)
+	ep.AddEventHandler(&EventHandlerFuncs{
+		AddFunc: func(table string, m model.Model) {
+			plain <- "add " + m.(*testModel).UUID
+		},
+		UpdateFunc: func(table string, old, new model.Model) {
+			plain <- "update " + new.(*testModel).UUID
+		},
+		DeleteFunc: func(table string, m model.Model) {
+			plain <- "delete " + m.(*testModel).UUID
+		},
+	})
+	// the methods of EventHandler are not called for the handlers taking
+	// the metadata
+	withMetadata := &testMetadataHandler{
+		EventHandlerFuncs: EventHandlerFuncs{
+			AddFunc: func(string, model.Model) {
+				t.Error("unexpected call of OnAdd")
+			},
+		},
+		events: make(chan string, 16),
+	}
+	ep.AddEventHandler(withMetadata)
+
+	initial := EventMetadata{Database: "Open_vSwitch", TransactionID: "txn0", Initial: true}
+	update := EventMetadata{Database: "Open_vSwitch", TransactionID: "txn1"}
+	ep.addEvent(addEvent, "bridge", nil, &testModel{UUID: "foo"}, initial)
+	ep.addEvent(updateEvent, "bridge", &testModel{UUID: "foo"}, &testModel{UUID: "foo", Foo: "bar"}, update)
+	ep.addEvent(deleteEvent, "bridge", &testModel{UUID: "foo"}, nil, update)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go ep.Run(stopCh)
+
+	for _, expected := range []string{"add foo", "update foo", "delete foo"} {
+		select {
+		case e := <-plain:
+			assert.Equal(t, expected, e)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s", expected)
+		}
+	}
+	for _, expected := range []string{
+		"add foo {Database:Open_vSwitch TransactionID:txn0 Initial:true}",
+		"update foo {Database:Open_vSwitch TransactionID:txn1 Initial:false}",
+		"delete foo {Database:Open_vSwitch TransactionID:txn1 Initial:false}",
+	} {
+		select {
+		case e := <-withMetadata.events:
+			assert.Equal(t, expected, e)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s", expected)
+		}
+	}
+}
+
 func TestIndex(t *testing.T) {
 	type indexTestModel struct {
 		UUID string `ovsdb:"_uuid"`
@@ -1319,6 +1717,33 @@ func BenchmarkIndexExists(b *testing.B) {
 	}
 }
 
+func benchmarkRowsByCondition(b *testing.B, column string) {
+	_, tc := setupRowByModelSingleIndex(b)
+	rc := tc.Table("Open_vSwitch")
+	for i := 0; i < numRows; i++ {
+		uuid := fmt.Sprintf("%d", i)
+		err := rc.Create(uuid, &testModel{UUID: uuid, Foo: uuid, Bar: uuid}, true)
+		require.NoError(b, err)
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		value := fmt.Sprintf("%d", n%numRows)
+		rows, err := rc.RowsByCondition([]ovsdb.Condition{ovsdb.NewCondition(column, ovsdb.ConditionEqual, value)})
+		require.NoError(b, err)
+		require.Len(b, rows, 1)
+	}
+}
+
+// foo is indexed and bar is not, so this compares the index lookup to the scan
+func BenchmarkRowsByConditionIndexed(b *testing.B) {
+	benchmarkRowsByCondition(b, "foo")
+}
+
+func BenchmarkRowsByConditionScan(b *testing.B) {
+	benchmarkRowsByCondition(b, "bar")
+}
+
 func TestTableCacheRowByModelTwoIndexes(t *testing.T) {
 	var schema ovsdb.DatabaseSchema
 	db, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{"Open_vSwitch": &testModel{}})
@@ -1420,6 +1845,76 @@ func TestTableCacheRowByModelMultiIndex(t *testing.T) {
 	})
 }
 
+func TestGetByIndex(t *testing.T) {
+	myFoo, tc := setupRowByModelSingleIndex(t)
+
+	t.Run("get foo by index", func(t *testing.T) {
+		foo, err := GetByIndex[*testModel](tc, "foo", "foo")
+		require.NoError(t, err)
+		assert.Equal(t, myFoo, foo)
+	})
+
+	t.Run("get non-existent item by index", func(t *testing.T) {
+		baz, err := GetByIndex[*testModel](tc, "foo", "baz")
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Nil(t, baz)
+	})
+
+	t.Run("not an index", func(t *testing.T) {
+		_, err := GetByIndex[*testModel](tc, "bar", "foo")
+		assert.Error(t, err)
+	})
+
+	t.Run("wrong number of values", func(t *testing.T) {
+		_, err := GetByIndex[*testModel](tc, "foo", "foo", "bar")
+		assert.Error(t, err)
+	})
+
+	t.Run("wrong value type", func(t *testing.T) {
+		_, err := GetByIndex[*testModel](tc, "foo", 1)
+		assert.Error(t, err)
+	})
+}
+
+func TestGetByIndexMultiIndex(t *testing.T) {
+	var schema ovsdb.DatabaseSchema
+	db, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{"Open_vSwitch": &testModel{}})
+	require.Nil(t, err)
+	err = json.Unmarshal([]byte(`
+		 {"name": "Open_vSwitch",
+		  "tables": {
+		    "Open_vSwitch": {
+			  "indexes": [["foo", "bar"]],
+		      "columns": {
+		        "foo": {
+			  "type": "string"
+			},
+			"bar": {
+			  "type": "string"
+			}
+		      }
+		    }
+		 }
+	     }
+	`), &schema)
+	require.NoError(t, err)
+	myFoo := &testModel{Foo: "foo", Bar: "bar"}
+	testData := Data{
+		"Open_vSwitch": map[string]model.Model{"foo": myFoo, "bar": &testModel{Foo: "bar", Bar: "bar"}},
+	}
+	dbModel, errs := model.NewDatabaseModel(schema, db)
+	require.Empty(t, errs)
+	tc, err := NewTableCache(dbModel, testData, nil)
+	require.NoError(t, err)
+
+	foo, err := GetByIndex[*testModel](tc, "foo,bar", "foo", "bar")
+	require.NoError(t, err)
+	assert.Equal(t, myFoo, foo)
+
+	_, err = GetByIndex[*testModel](tc, "foo,bar", "bar", "foo")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
 func TestTableCacheApplyModifications(t *testing.T) {
 	type testDBModel struct {
 		UUID  string            `ovsdb:"_uuid"`
@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/ovn-org/libovsdb/cache"
+	"github.com/ovn-org/libovsdb/mapper"
 	"github.com/ovn-org/libovsdb/model"
 	"github.com/ovn-org/libovsdb/ovsdb"
 )
@@ -42,11 +43,20 @@ type API interface {
 	// preferred way is Where({condition}).List()
 	Get(context.Context, model.Model) error
 
+	// GetSelectResults decodes the rows returned by a select operation into
+	// a slice of Models. The models parameter must be a pointer to a slice of
+	// Models or of pointers to Models. Decoded rows are appended to the slice
+	GetSelectResults(result ovsdb.OperationResult, models interface{}) error
+
 	// Create returns the operation needed to add the model(s) to the Database
 	// Only fields with non-default values will be added to the transaction
 	// If the field associated with column "_uuid" has some content, it will be
 	// treated as named-uuid
 	Create(...model.Model) ([]ovsdb.Operation, error)
+
+	// PreviewTransaction returns the changes the operations would cause to
+	// the cache, simulating them against a copy of it
+	PreviewTransaction(context.Context, ...ovsdb.Operation) ([]RowDelta, error)
 }
 
 // ConditionalAPI is an interface used to perform operations that require / use Conditions
@@ -88,12 +98,21 @@ func (e *ErrWrongType) Error() string {
 // ErrNotFound is used to inform the object or table was not found in the cache
 var ErrNotFound = errors.New("object not found")
 
+// ErrCacheDisabled is returned when reading from, or monitoring into, the cache
+// of a client created with WithoutCache
+var ErrCacheDisabled = errors.New("cache disabled")
+
 // api struct implements both API and ConditionalAPI
 // Where() can be used to create a ConditionalAPI api
 type api struct {
-	cache  *cache.TableCache
-	cond   Conditional
-	logger *logr.Logger
+	cache *cache.TableCache
+	// dbModel is the database model of an API without cache
+	dbModel model.DatabaseModel
+	cond    Conditional
+	logger  *logr.Logger
+	// guardVersions makes updates and mutations fail if the rows were
+	// modified since the models were read (see WithVersionGuard)
+	guardVersions bool
 }
 
 // List populates a slice of Models given as parameter based on the configured Condition
@@ -113,6 +132,10 @@ func (a api) List(ctx context.Context, result interface{}) error {
 		return err
 	}
 
+	if a.cache == nil {
+		return ErrCacheDisabled
+	}
+
 	if a.cond != nil && a.cond.Table() != table {
 		return &ErrWrongType{resultPtr.Type(),
 			fmt.Sprintf("Table derived from input type (%s) does not match Table from Condition (%s)", table, a.cond.Table())}
@@ -151,19 +174,71 @@ func (a api) List(ctx context.Context, result interface{}) error {
 	return nil
 }
 
+// GetSelectResults decodes the rows of a select operation result and appends
+// them to the slice of Models given as parameter
+func (a api) GetSelectResults(result ovsdb.OperationResult, models interface{}) error {
+	resultPtr := reflect.ValueOf(models)
+	if resultPtr.Type().Kind() != reflect.Ptr {
+		return &ErrWrongType{resultPtr.Type(), "Expected pointer to slice of valid Models"}
+	}
+
+	resultVal := reflect.Indirect(resultPtr)
+	if resultVal.Type().Kind() != reflect.Slice {
+		return &ErrWrongType{resultPtr.Type(), "Expected pointer to slice of valid Models"}
+	}
+
+	// the slice may hold either Models or pointers to Models
+	elemType := resultVal.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+
+	table, err := a.getTableFromModel(reflect.New(elemType).Interface())
+	if err != nil {
+		return err
+	}
+
+	if result.Error != "" {
+		return fmt.Errorf("select operation failed: %s: %s", result.Error, result.Details)
+	}
+
+	for i := range result.Rows {
+		// the mapper ignores the _uuid column, so pass it explicitly if the
+		// select operation returned it
+		var uuid string
+		if u, ok := result.Rows[i]["_uuid"].(ovsdb.UUID); ok {
+			uuid = u.GoUUID
+		}
+		m, err := a.createModel(table, &result.Rows[i], uuid)
+		if err != nil {
+			return err
+		}
+		v := reflect.ValueOf(m)
+		if !isPtr {
+			v = reflect.Indirect(v)
+		}
+		resultVal.Set(reflect.Append(resultVal, v))
+	}
+	return nil
+}
+
 // Where returns a conditionalAPI based on a Condition list
 func (a api) Where(model model.Model, cond ...model.Condition) ConditionalAPI {
-	return newConditionalAPI(a.cache, a.conditionFromModel(false, model, cond...), a.logger)
+	a.cond = a.conditionFromModel(false, model, cond...)
+	return a
 }
 
 // Where returns a conditionalAPI based on a Condition list
 func (a api) WhereAll(model model.Model, cond ...model.Condition) ConditionalAPI {
-	return newConditionalAPI(a.cache, a.conditionFromModel(true, model, cond...), a.logger)
+	a.cond = a.conditionFromModel(true, model, cond...)
+	return a
 }
 
 // Where returns a conditionalAPI based a Predicate
 func (a api) WhereCache(predicate interface{}) ConditionalAPI {
-	return newConditionalAPI(a.cache, a.conditionFromFunc(predicate), a.logger)
+	a.cond = a.conditionFromFunc(predicate)
+	return a
 }
 
 // Conditional interface implementation
@@ -174,6 +249,9 @@ func (a api) conditionFromFunc(predicate interface{}) Conditional {
 		return newErrorConditional(err)
 	}
 
+	if a.cache == nil {
+		return newErrorConditional(ErrCacheDisabled)
+	}
 	condition, err := newPredicateConditional(table, a.cache, predicate)
 	if err != nil {
 		return newErrorConditional(err)
@@ -192,13 +270,13 @@ func (a api) conditionFromModel(any bool, model model.Model, cond ...model.Condi
 	}
 
 	if len(cond) == 0 {
-		conditional, err = newEqualityConditional(a.cache.DatabaseModel(), tableName, any, model)
+		conditional, err = newEqualityConditional(a.databaseModel(), tableName, any, model)
 		if err != nil {
 			conditional = newErrorConditional(err)
 		}
 
 	} else {
-		conditional, err = newExplicitConditional(a.cache.DatabaseModel(), tableName, any, model, cond...)
+		conditional, err = newExplicitConditional(a.databaseModel(), tableName, any, model, cond...)
 		if err != nil {
 			conditional = newErrorConditional(err)
 		}
@@ -218,6 +296,9 @@ func (a api) Get(ctx context.Context, m model.Model) error {
 		return err
 	}
 
+	if a.cache == nil {
+		return ErrCacheDisabled
+	}
 	tableCache := a.cache.Table(table)
 	if tableCache == nil {
 		return ErrNotFound
@@ -248,7 +329,7 @@ func (a api) Create(models ...model.Model) ([]ovsdb.Operation, error) {
 		}
 
 		// Read _uuid field, and use it as named-uuid
-		info, err := a.cache.DatabaseModel().NewModelInfo(model)
+		info, err := a.databaseModel().NewModelInfo(model)
 		if err != nil {
 			return nil, err
 		}
@@ -258,10 +339,12 @@ func (a api) Create(models ...model.Model) ([]ovsdb.Operation, error) {
 			return nil, err
 		}
 
-		row, err := a.cache.Mapper().NewRow(info)
+		row, err := a.databaseModel().Mapper.NewRow(info)
 		if err != nil {
 			return nil, err
 		}
+		// the server assigns the version of new rows
+		delete(row, "_version")
 
 		operations = append(operations, ovsdb.Operation{
 			Op:       ovsdb.OperationInsert,
@@ -282,11 +365,11 @@ func (a api) Mutate(model model.Model, mutationObjs ...model.Mutation) ([]ovsdb.
 		return nil, fmt.Errorf("at least one Mutation must be provided")
 	}
 
-	tableName := a.cache.DatabaseModel().FindTable(reflect.ValueOf(model).Type())
+	tableName := a.databaseModel().FindTable(reflect.ValueOf(model).Type())
 	if tableName == "" {
 		return nil, fmt.Errorf("table not found for object")
 	}
-	table := a.cache.Mapper().Schema.Table(tableName)
+	table := a.databaseModel().Mapper.Schema.Table(tableName)
 	if table == nil {
 		return nil, fmt.Errorf("schema error: table not found in Database Model for type %s", reflect.TypeOf(model))
 	}
@@ -296,7 +379,7 @@ func (a api) Mutate(model model.Model, mutationObjs ...model.Mutation) ([]ovsdb.
 		return nil, err
 	}
 
-	info, err := a.cache.DatabaseModel().NewModelInfo(model)
+	info, err := a.databaseModel().NewModelInfo(model)
 	if err != nil {
 		return nil, err
 	}
@@ -307,12 +390,17 @@ func (a api) Mutate(model model.Model, mutationObjs ...model.Mutation) ([]ovsdb.
 			return nil, err
 		}
 
-		mutation, err := a.cache.Mapper().NewMutation(info, col, mobj.Mutator, mobj.Value)
+		mutation, err := a.databaseModel().Mapper.NewMutation(info, col, mobj.Mutator, mobj.Value)
 		if err != nil {
 			return nil, err
 		}
 		mutations = append(mutations, *mutation)
 	}
+	guard, err := a.versionGuard(tableName, info)
+	if err != nil {
+		return nil, err
+	}
+	operations = append(operations, guard...)
 	for _, condition := range conditions {
 		operations = append(operations,
 			ovsdb.Operation{
@@ -336,8 +424,8 @@ func (a api) Update(model model.Model, fields ...interface{}) ([]ovsdb.Operation
 	if err != nil {
 		return nil, err
 	}
-	tableSchema := a.cache.Mapper().Schema.Table(table)
-	info, err := a.cache.DatabaseModel().NewModelInfo(model)
+	tableSchema := a.databaseModel().Mapper.Schema.Table(table)
+	info, err := a.databaseModel().NewModelInfo(model)
 	if err != nil {
 		return nil, err
 	}
@@ -359,7 +447,7 @@ func (a api) Update(model model.Model, fields ...interface{}) ([]ovsdb.Operation
 		return nil, err
 	}
 
-	row, err := a.cache.Mapper().NewRow(info, fields...)
+	row, err := a.databaseModel().Mapper.NewRow(info, fields...)
 	if err != nil {
 		return nil, err
 	}
@@ -371,11 +459,17 @@ func (a api) Update(model model.Model, fields ...interface{}) ([]ovsdb.Operation
 		}
 	}
 	delete(row, "_uuid")
+	delete(row, "_version")
 
 	if len(row) == 0 {
 		return nil, fmt.Errorf("attempted to update using an empty row. please check that all fields you wish to update are mutable")
 	}
 
+	guard, err := a.versionGuard(table, info)
+	if err != nil {
+		return nil, err
+	}
+	operations = append(operations, guard...)
 	for _, condition := range conditions {
 		operations = append(operations,
 			ovsdb.Operation{
@@ -389,6 +483,43 @@ func (a api) Update(model model.Model, fields ...interface{}) ([]ovsdb.Operation
 	return operations, nil
 }
 
+// versionGuard returns the wait operation that fails the transaction if the
+// row of the model was modified since the model was read, that is if its
+// _version changed. No operation is returned if versions are not guarded or
+// if the model does not hold both a _uuid and a _version. The guard only covers
+// the row of the model, so it fails if the conditions may select other rows,
+// that is unless they are the equality on the _uuid of the model
+func (a api) versionGuard(table string, info *mapper.Info) ([]ovsdb.Operation, error) {
+	if !a.guardVersions {
+		return nil, nil
+	}
+	uuid, err := info.FieldByColumn("_uuid")
+	if err != nil || uuid == "" {
+		return nil, nil
+	}
+	version, err := info.FieldByColumn("_version")
+	if err != nil || version == "" {
+		return nil, nil
+	}
+	var selected interface{}
+	if cond, ok := a.cond.(*equalityConditional); ok {
+		selected, _ = cond.info.FieldByColumn("_uuid")
+	}
+	if selected != uuid {
+		return nil, fmt.Errorf("cannot guard the version of row %s of table %s: the conditions may select other rows", uuid, table)
+	}
+	timeout := 0
+	return []ovsdb.Operation{{
+		Op:      ovsdb.OperationWait,
+		Table:   table,
+		Timeout: &timeout,
+		Where:   []ovsdb.Condition{ovsdb.NewCondition("_uuid", ovsdb.ConditionEqual, ovsdb.UUID{GoUUID: uuid.(string)})},
+		Columns: []string{"_version"},
+		Until:   string(ovsdb.WaitConditionEqual),
+		Rows:    []ovsdb.Row{{"_version": ovsdb.UUID{GoUUID: version.(string)}}},
+	}}, nil
+}
+
 // Delete returns the Operation needed to delete the selected models from the database
 func (a api) Delete() ([]ovsdb.Operation, error) {
 	var operations []ovsdb.Operation
@@ -441,7 +572,7 @@ func (a api) Wait(untilConFun ovsdb.WaitCondition, timeout *int, model model.Mod
 		return nil, err
 	}
 
-	info, err := a.cache.DatabaseModel().NewModelInfo(model)
+	info, err := a.databaseModel().NewModelInfo(model)
 	if err != nil {
 		return nil, err
 	}
@@ -458,7 +589,7 @@ func (a api) Wait(untilConFun ovsdb.WaitCondition, timeout *int, model model.Mod
 		}
 	}
 
-	row, err := a.cache.Mapper().NewRow(info, fields...)
+	row, err := a.databaseModel().Mapper.NewRow(info, fields...)
 	if err != nil {
 		return nil, err
 	}
@@ -490,7 +621,7 @@ func (a api) getTableFromModel(m interface{}) (string, error) {
 	if _, ok := m.(model.Model); !ok {
 		return "", &ErrWrongType{reflect.TypeOf(m), "Type does not implement Model interface"}
 	}
-	table := a.cache.DatabaseModel().FindTable(reflect.TypeOf(m))
+	table := a.databaseModel().FindTable(reflect.TypeOf(m))
 	if table == "" {
 		return "", &ErrWrongType{reflect.TypeOf(m), "Model not found in Database Model"}
 	}
@@ -515,7 +646,7 @@ func (a api) getTableFromFunc(predicate interface{}) (string, error) {
 			fmt.Sprintf("Type %s does not implement Model interface", modelType.String())}
 	}
 
-	table := a.cache.DatabaseModel().FindTable(modelType)
+	table := a.databaseModel().FindTable(modelType)
 	if table == "" {
 		return "", &ErrWrongType{predType,
 			fmt.Sprintf("Model %s not found in Database Model", modelType.String())}
@@ -531,6 +662,55 @@ func newAPI(cache *cache.TableCache, logger *logr.Logger) API {
 	}
 }
 
+// newCachelessAPI returns a new API to interact with a database without cache,
+// which only builds operations and decodes results
+func newCachelessAPI(dbModel model.DatabaseModel, logger *logr.Logger) API {
+	return api{
+		dbModel: dbModel,
+		logger:  logger,
+	}
+}
+
+// databaseModel returns the database model the API works with
+func (a api) databaseModel() model.DatabaseModel {
+	if a.cache == nil {
+		return a.dbModel
+	}
+	return a.cache.DatabaseModel()
+}
+
+// createModel creates a model of the given table from a row
+func (a api) createModel(table string, row *ovsdb.Row, uuid string) (model.Model, error) {
+	if a.cache != nil {
+		return a.cache.CreateModel(table, row, uuid)
+	}
+	dbModel := a.databaseModel()
+	m, err := dbModel.NewModel(table)
+	if err != nil {
+		return nil, err
+	}
+	info, err := dbModel.NewModelInfo(m)
+	if err != nil {
+		return nil, err
+	}
+	if err := dbModel.Mapper.GetRowData(row, info); err != nil {
+		return nil, err
+	}
+	if uuid != "" {
+		if err := info.SetField("_uuid", uuid); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// withVersionGuard returns a copy of the API guarding the versions of the
+// rows it updates or mutates
+func (a api) withVersionGuard() api {
+	a.guardVersions = true
+	return a
+}
+
 // newConditionalAPI returns a new ConditionalAPI to interact with the database
 func newConditionalAPI(cache *cache.TableCache, cond Conditional, logger *logr.Logger) ConditionalAPI {
 	return api{
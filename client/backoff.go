@@ -0,0 +1,61 @@
+package client
+
+import (
+	"math/rand"
+	"time"
+)
+
+// BackoffStrategy decides how long the client waits between its attempts to
+// reconnect (see WithReconnectStrategy)
+type BackoffStrategy interface {
+	// NextInterval returns how long to wait after the given number of failed
+	// attempts, starting at 1, before attempting to reconnect again
+	NextInterval(attempt int) time.Duration
+}
+
+// ExponentialJitterBackoff is a BackoffStrategy doubling the interval after
+// each failed attempt, from Initial up to Max, and waiting a random duration
+// between half of the interval and the interval, so that clients disconnected
+// at the same time do not reconnect all at once
+type ExponentialJitterBackoff struct {
+	Initial time.Duration
+	Max     time.Duration
+}
+
+// NextInterval implements BackoffStrategy
+func (b ExponentialJitterBackoff) NextInterval(attempt int) time.Duration {
+	interval := b.Initial
+	for i := 1; i < attempt && interval < b.Max; i++ {
+		interval *= 2
+	}
+	if interval > b.Max {
+		interval = b.Max
+	}
+	if interval <= 0 {
+		return 0
+	}
+	half := interval / 2
+	return half + time.Duration(rand.Int63n(int64(interval-half)+1))
+}
+
+// defaultBackoffStrategy is the strategy used to reconnect when none is given
+var defaultBackoffStrategy = ExponentialJitterBackoff{
+	Initial: 500 * time.Millisecond,
+	Max:     time.Minute,
+}
+
+// strategyBackOff adapts a BackoffStrategy to the backoff.BackOff of the
+// reconnect loop, counting the failed attempts
+type strategyBackOff struct {
+	strategy BackoffStrategy
+	attempt  int
+}
+
+func (b *strategyBackOff) NextBackOff() time.Duration {
+	b.attempt++
+	return b.strategy.NextInterval(b.attempt)
+}
+
+func (b *strategyBackOff) Reset() {
+	b.attempt = 0
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExponentialJitterBackoff(t *testing.T) {
+	b := ExponentialJitterBackoff{Initial: time.Second, Max: 10 * time.Second}
+	for attempt, interval := range map[int]time.Duration{
+		1: time.Second,
+		2: 2 * time.Second,
+		3: 4 * time.Second,
+		4: 8 * time.Second,
+		5: 10 * time.Second,
+		9: 10 * time.Second,
+	} {
+		for i := 0; i < 10; i++ {
+			next := b.NextInterval(attempt)
+			assert.Truef(t, next >= interval/2 && next <= interval, "attempt %d: %s", attempt, next)
+		}
+	}
+	assert.Equal(t, time.Duration(0), ExponentialJitterBackoff{}.NextInterval(1))
+}
+
+// recordingStrategy waits attempt minutes after each failed attempt, and
+// records the attempts
+type recordingStrategy struct {
+	attempts chan int
+}
+
+func (s *recordingStrategy) NextInterval(attempt int) time.Duration {
+	s.attempts <- attempt
+	return time.Duration(attempt) * time.Minute
+}
+
+func TestClientReconnectStrategy(t *testing.T) {
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+
+	clock := newFakeClock()
+	strategy := &recordingStrategy{attempts: make(chan int, 10)}
+	ovs, err := newOVSDBClient(defDB,
+		WithReconnectStrategy(time.Second, strategy),
+		WithClock(clock),
+		WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	// moving the socket away makes the reconnection attempts fail
+	hidden := sock + ".hidden"
+	t.Cleanup(func() {
+		os.Remove(hidden)
+	})
+	err = os.Rename(sock, hidden)
+	require.NoError(t, err)
+	ovs.Disconnect()
+
+	waiting := func(interval time.Duration) func() bool {
+		deadline := clock.Now().Add(interval)
+		return func() bool {
+			clock.mutex.Lock()
+			defer clock.mutex.Unlock()
+			for _, w := range clock.waiters {
+				if w.deadline.Equal(deadline) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	for attempt := 1; attempt <= 3; attempt++ {
+		select {
+		case got := <-strategy.attempts:
+			require.Equal(t, attempt, got)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("attempt %d did not fail", attempt)
+		}
+		interval := time.Duration(attempt) * time.Minute
+		require.Eventually(t, waiting(interval), time.Second, 10*time.Millisecond)
+
+		// the client does not attempt to reconnect before the interval
+		clock.Advance(interval - time.Second)
+		select {
+		case got := <-strategy.attempts:
+			t.Fatalf("attempt %d failed before the interval of attempt %d", got, attempt)
+		case <-time.After(100 * time.Millisecond):
+		}
+		if attempt < 3 {
+			clock.Advance(time.Second)
+		}
+	}
+
+	// the next attempt succeeds once the server is reachable again
+	err = os.Rename(hidden, sock)
+	require.NoError(t, err)
+	clock.Advance(time.Second)
+	require.Eventually(t, ovs.Connected, 5*time.Second, 10*time.Millisecond)
+	assert.Empty(t, strategy.attempts)
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"bufio"
+	"net"
+	"sync"
+	"time"
+)
+
+// bufferedConn is a connection buffering what is written to it, so that the
+// messages of a burst of transactions are sent with a few writes. The buffer
+// is flushed once it holds size bytes, or flushInterval after the first write
+// it holds, whichever comes first
+type bufferedConn struct {
+	net.Conn
+	clock         Clock
+	flushInterval time.Duration
+	writer        *bufio.Writer
+	// pending tells whether a flush is scheduled
+	pending bool
+	// err is the error of a scheduled flush, returned by the next writes
+	err    error
+	closed chan struct{}
+	mutex  sync.Mutex
+}
+
+func newBufferedConn(conn net.Conn, size int, flushInterval time.Duration, clock Clock) *bufferedConn {
+	return &bufferedConn{
+		Conn:          conn,
+		clock:         clock,
+		flushInterval: flushInterval,
+		writer:        bufio.NewWriterSize(conn, size),
+		closed:        make(chan struct{}),
+	}
+}
+
+func (c *bufferedConn) Write(b []byte) (int, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.err != nil {
+		return 0, c.err
+	}
+	// the writer flushes by itself what does not fit in the buffer
+	n, err := c.writer.Write(b)
+	if err != nil {
+		return n, err
+	}
+	if c.writer.Available() == 0 {
+		return n, c.writer.Flush()
+	}
+	if c.writer.Buffered() > 0 && !c.pending {
+		c.pending = true
+		after := c.clock.After(c.flushInterval)
+		go func() {
+			select {
+			case <-after:
+				c.flushPending()
+			case <-c.closed:
+			}
+		}()
+	}
+	return n, nil
+}
+
+// flushPending runs the scheduled flush. The connection is closed if it
+// fails, so that the reads fail as well
+func (c *bufferedConn) flushPending() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.pending = false
+	if c.err != nil {
+		return
+	}
+	if err := c.writer.Flush(); err != nil {
+		c.err = err
+		c.Conn.Close()
+	}
+}
+
+func (c *bufferedConn) Close() error {
+	c.mutex.Lock()
+	select {
+	case <-c.closed:
+	default:
+		close(c.closed)
+		if c.err == nil {
+			c.writer.Flush()
+		}
+	}
+	c.mutex.Unlock()
+	return c.Conn.Close()
+}
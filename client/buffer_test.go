@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/rpc2"
+	"github.com/cenkalti/rpc2/jsonrpc"
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingConn records the writes to a connection
+type recordingConn struct {
+	net.Conn
+	writes []string
+	closed bool
+	mutex  sync.Mutex
+}
+
+func (c *recordingConn) Write(b []byte) (int, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.writes = append(c.writes, string(b))
+	return len(b), nil
+}
+
+func (c *recordingConn) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.closed = true
+	return nil
+}
+
+func (c *recordingConn) Writes() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]string{}, c.writes...)
+}
+
+func TestBufferedConnFlushOnSize(t *testing.T) {
+	clock := newFakeClock()
+	conn := &recordingConn{}
+	bc := newBufferedConn(conn, 10, time.Second, clock)
+
+	_, err := bc.Write([]byte("abc"))
+	require.NoError(t, err)
+	assert.Empty(t, conn.Writes())
+
+	// the buffer is full
+	_, err = bc.Write([]byte("defghij"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"abcdefghij"}, conn.Writes())
+
+	// the buffer is flushed each time a write fills it
+	_, err = bc.Write([]byte("k"))
+	require.NoError(t, err)
+	_, err = bc.Write([]byte("lmnopqrstuvwxyz"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"abcdefghij", "klmnopqrst"}, conn.Writes())
+
+	// and when closed
+	require.NoError(t, bc.Close())
+	assert.Equal(t, []string{"abcdefghij", "klmnopqrst", "uvwxyz"}, conn.Writes())
+	assert.True(t, conn.closed)
+}
+
+func TestBufferedConnFlushOnInterval(t *testing.T) {
+	clock := newFakeClock()
+	conn := &recordingConn{}
+	bc := newBufferedConn(conn, 1024, time.Second, clock)
+
+	_, err := bc.Write([]byte("abc"))
+	require.NoError(t, err)
+	_, err = bc.Write([]byte("def"))
+	require.NoError(t, err)
+	clock.Advance(500 * time.Millisecond)
+	assert.Never(t, func() bool { return len(conn.Writes()) > 0 }, 50*time.Millisecond, 10*time.Millisecond)
+
+	clock.Advance(500 * time.Millisecond)
+	assert.Eventually(t, func() bool { return len(conn.Writes()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"abcdef"}, conn.Writes())
+
+	// the buffer is flushed when closed
+	_, err = bc.Write([]byte("ghi"))
+	require.NoError(t, err)
+	require.NoError(t, bc.Close())
+	assert.Equal(t, []string{"abcdef", "ghi"}, conn.Writes())
+}
+
+func TestClientWriteBuffer(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newNBServer(t)
+	ovs, err := newOVSDBClient(nbDB,
+		WithEndpoint(fmt.Sprintf("unix:%s", sock)),
+		WithWriteBuffer(64*1024, time.Millisecond),
+	)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = ovs.Connect(ctx)
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results, err := ovs.Transact(ctx, ovsdb.Operation{
+				Op:    ovsdb.OperationSelect,
+				Table: "Logical_Switch",
+				Where: []ovsdb.Condition{},
+			})
+			assert.NoError(t, err)
+			assert.Len(t, results, 1)
+		}()
+	}
+	wg.Wait()
+}
+
+func benchmarkEcho(b *testing.B, wrap func(net.Conn) net.Conn) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(b, err)
+	defer listener.Close()
+	srv := rpc2.NewServer()
+	srv.Handle("echo", func(_ *rpc2.Client, args []interface{}, reply *[]interface{}) error {
+		*reply = args
+		return nil
+	})
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		srv.ServeCodec(jsonrpc.NewJSONCodec(conn))
+	}()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(context.Background(), "tcp", listener.Addr().String())
+	require.NoError(b, err)
+	c := rpc2.NewClientWithCodec(jsonrpc.NewJSONCodec(wrap(conn)))
+	go c.Run()
+	defer c.Close()
+
+	b.SetParallelism(16)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			var reply []interface{}
+			if err := c.Call("echo", []interface{}{"ping"}, &reply); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkWriteUnbuffered(b *testing.B) {
+	benchmarkEcho(b, func(conn net.Conn) net.Conn {
+		return conn
+	})
+}
+
+func BenchmarkWriteBuffered(b *testing.B) {
+	benchmarkEcho(b, func(conn net.Conn) net.Conn {
+		return newBufferedConn(conn, 64*1024, 100*time.Microsecond, realClock{})
+	})
+}
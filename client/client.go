@@ -1,16 +1,19 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"net/url"
 	"os"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -44,6 +47,31 @@ var ErrAlreadyConnected = errors.New("already connected")
 // ErrUnsupportedRPC is an error returned when an unsupported RPC method is called
 var ErrUnsupportedRPC = errors.New("unsupported rpc")
 
+// ErrReconnectFailed is an error returned when the client gave up reconnecting
+// after reaching the limit set with WithReconnectLimit
+var ErrReconnectFailed = errors.New("reconnect failed")
+
+// LockNotHeldError is the error returned by TransactWithLock when the client
+// does not hold the lock the transaction was attached to
+type LockNotHeldError struct {
+	LockID string
+}
+
+func (e *LockNotHeldError) Error() string {
+	return fmt.Sprintf("lock %s not held", e.LockID)
+}
+
+// ReadOnlyError is the error returned when a client set with WithReadOnly is
+// asked to send a transaction writing to the database
+type ReadOnlyError struct {
+	Op    string
+	Table string
+}
+
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("cannot transact: %s operation on table %s not allowed by a read-only client", e.Op, e.Table)
+}
+
 // Client represents an OVSDB Client Connection
 // It provides all the necessary functionality to Connect to a server,
 // perform transactions, and build your own replica of the database with
@@ -54,12 +82,23 @@ type Client interface {
 	Disconnect()
 	Close()
 	Schema() ovsdb.DatabaseSchema
+	CurrentSchema() ovsdb.DatabaseSchema
 	Cache() *cache.TableCache
 	SetOption(Option) error
 	Connected() bool
 	DisconnectNotify() chan struct{}
 	Echo(context.Context) error
+	Health() HealthStatus
+	TableSynced(table string) <-chan struct{}
 	Transact(context.Context, ...ovsdb.Operation) ([]ovsdb.OperationResult, error)
+	TransactRows(context.Context, ovsdb.RowHandler, ...ovsdb.Operation) ([]ovsdb.OperationResult, error)
+	TransactWithLock(ctx context.Context, lockID string, operation ...ovsdb.Operation) ([]ovsdb.OperationResult, error)
+	VerifyCache(context.Context) error
+	Lock(ctx context.Context, lockID string) (bool, error)
+	Steal(ctx context.Context, lockID string) error
+	Unlock(ctx context.Context, lockID string) error
+	Insert(context.Context, ...model.Model) ([]string, error)
+	GetByUUIDs(ctx context.Context, table string, uuids []string) ([]model.Model, error)
 	Monitor(context.Context, *Monitor) (MonitorCookie, error)
 	MonitorAll(context.Context) (MonitorCookie, error)
 	MonitorCancel(ctx context.Context, cookie MonitorCookie) error
@@ -77,6 +116,7 @@ type bufferedUpdate struct {
 type epInfo struct {
 	address  string
 	serverID string
+	priority int
 }
 
 // ovsdbClient is an OVSDB client
@@ -86,6 +126,23 @@ type ovsdbClient struct {
 	connected bool
 	rpcClient *rpc2.Client
 	rpcMutex  sync.RWMutex
+	// reconnectErr holds the reason why the client gave up reconnecting
+	reconnectErr error
+	// writeQueue holds the transactions to replay on reconnect when the
+	// client was created with WithWriteQueue
+	writeQueue writeQueue
+	// lastEcho is the time of the last echo successfully exchanged with
+	// the server
+	lastEcho      time.Time
+	lastEchoMutex sync.Mutex
+	// currentSchema is the schema of the primary database as last reported
+	// by the server, which may be newer than the schema of the model
+	currentSchema      ovsdb.DatabaseSchema
+	currentSchemaMutex sync.RWMutex
+	// locks holds the locks requested with Lock or Steal and whether the
+	// client currently holds them. They are requested again on reconnect
+	locks      map[string]bool
+	locksMutex sync.Mutex
 	// endpoints contains all possible endpoints; the first element is
 	// the active endpoint if connected=true
 	endpoints []*epInfo
@@ -114,6 +171,8 @@ type database struct {
 	cache *cache.TableCache
 	// cacheMutex protects cache from being replaced (via reconnect) while in use
 	cacheMutex sync.RWMutex
+	// withoutCache is set if the database is not cached nor monitored
+	withoutCache bool
 
 	api API
 
@@ -124,6 +183,45 @@ type database struct {
 	// tracks any outstanding updates while waiting for a monitor response
 	deferUpdates    bool
 	deferredUpdates []*bufferedUpdate
+	// the monitors cancelled on the current connection, whose late updates
+	// must be ignored
+	cancelledMonitors map[string]struct{}
+	// the updates of the monitors waiting for their reply, which may be
+	// received before it, to be applied once its initial dump is
+	pendingMonitors map[string][]*bufferedUpdate
+
+	// channels closed once the initial state of each table has been
+	// populated in the cache
+	tableSynced      map[string]chan struct{}
+	tableSyncedMutex sync.Mutex
+}
+
+// tableSyncedCh returns the channel closed once the initial state of the
+// table has been populated in the cache. Assumes tableSyncedMutex is held.
+func (db *database) tableSyncedCh(table string) chan struct{} {
+	if db.tableSynced == nil {
+		db.tableSynced = make(map[string]chan struct{})
+	}
+	ch, ok := db.tableSynced[table]
+	if !ok {
+		ch = make(chan struct{})
+		db.tableSynced[table] = ch
+	}
+	return ch
+}
+
+// setTableSynced signals that the initial state of the table has been
+// populated in the cache
+func (db *database) setTableSynced(table string) {
+	db.tableSyncedMutex.Lock()
+	defer db.tableSyncedMutex.Unlock()
+	ch := db.tableSyncedCh(table)
+	select {
+	case <-ch:
+		// already signaled
+	default:
+		close(ch)
+	}
 }
 
 // NewOVSDBClient creates a new OVSDB Client with the provided
@@ -136,25 +234,35 @@ func NewOVSDBClient(clientDBModel model.ClientDBModel, opts ...Option) (Client,
 
 // newOVSDBClient creates a new ovsdbClient
 func newOVSDBClient(clientDBModel model.ClientDBModel, opts ...Option) (*ovsdbClient, error) {
+	options, err := newOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if options.converters != nil {
+		clientDBModel = clientDBModel.WithConverters(options.converters)
+	}
 	ovs := &ovsdbClient{
 		primaryDBName: clientDBModel.Name(),
 		databases: map[string]*database{
 			clientDBModel.Name(): {
-				model:           model.NewPartialDatabaseModel(clientDBModel),
-				monitors:        make(map[string]*Monitor),
-				deferUpdates:    true,
-				deferredUpdates: make([]*bufferedUpdate, 0),
+				model:             model.NewPartialDatabaseModel(clientDBModel),
+				monitors:          make(map[string]*Monitor),
+				deferUpdates:      true,
+				deferredUpdates:   make([]*bufferedUpdate, 0),
+				cancelledMonitors: make(map[string]struct{}),
+				withoutCache:      options.withoutCache,
 			},
 		},
 		disconnect: make(chan struct{}),
-	}
-	var err error
-	ovs.options, err = newOptions(opts...)
-	if err != nil {
-		return nil, err
+		locks:      make(map[string]bool),
+		options:    options,
 	}
 	for _, address := range ovs.options.endpoints {
-		ovs.endpoints = append(ovs.endpoints, &epInfo{address: address})
+		ovs.endpoints = append(ovs.endpoints, &epInfo{address: address, priority: ovs.options.endpointPriorities[address]})
+	}
+
+	if ovs.options.clock == nil {
+		ovs.options.clock = realClock{}
 	}
 
 	if ovs.options.logger == nil {
@@ -174,15 +282,17 @@ func newOVSDBClient(clientDBModel model.ClientDBModel, opts ...Option) (*ovsdbCl
 	}
 	ovs.registerMetrics()
 
-	// if we should only connect to the leader, then add the special "_Server" database as well
-	if ovs.options.leaderOnly {
+	// if we should only connect to the leader, or follow the schema changes,
+	// then add the special "_Server" database as well
+	if ovs.options.leaderOnly || ovs.options.schemaChangeHandler != nil {
 		sm, err := serverdb.FullDatabaseModel()
 		if err != nil {
 			return nil, fmt.Errorf("could not initialize model _Server: %w", err)
 		}
 		ovs.databases[serverDB] = &database{
-			model:    model.NewPartialDatabaseModel(sm),
-			monitors: make(map[string]*Monitor),
+			model:             model.NewPartialDatabaseModel(sm),
+			monitors:          make(map[string]*Monitor),
+			cancelledMonitors: make(map[string]struct{}),
 		}
 	}
 	ovs.metrics.init(clientDBModel.Name())
@@ -201,12 +311,16 @@ func (o *ovsdbClient) Connect(ctx context.Context) error {
 		}
 		return err
 	}
+	if _, ok := o.databases[serverDB]; !ok {
+		return nil
+	}
 	if o.options.leaderOnly {
-		if err := o.watchForLeaderChange(); err != nil {
-			return err
-		}
+		o.watchForLeaderChange()
 	}
-	return nil
+	if o.options.schemaChangeHandler != nil {
+		o.watchForSchemaChange()
+	}
+	return o.monitorServerDatabases()
 }
 
 // moveEndpointFirst makes the endpoint requested by active the first element
@@ -238,6 +352,10 @@ func (o *ovsdbClient) connect(ctx context.Context, reconnect bool) error {
 		return ErrAlreadyConnected
 	}
 
+	// try the endpoints of higher priority first, in their current order
+	sort.SliceStable(o.endpoints, func(i, j int) bool {
+		return o.endpoints[i].priority > o.endpoints[j].priority
+	})
 	connected := false
 	connectErrors := []error{}
 	for i, endpoint := range o.endpoints {
@@ -285,15 +403,30 @@ func (o *ovsdbClient) connect(ctx context.Context, reconnect bool) error {
 				}
 			}
 		}
+		if err := o.reacquireLocks(ctx); err != nil {
+			o.resetRPCClient()
+			return err
+		}
 	}
 
 	go o.handleDisconnectNotification()
+	if o.options.inactivityProbe > 0 {
+		go o.handleInactivityProbe(o.stopCh)
+	}
 	for _, db := range o.databases {
+		if db.withoutCache {
+			continue
+		}
 		go o.handleCacheErrors(o.stopCh, db.cache.Errors())
 		go db.cache.Run(o.stopCh)
 	}
 
 	o.connected = true
+	o.reconnectErr = nil
+	if o.options.reconnect && o.options.writeQueue {
+		// the queue is replayed once the rpcMutex is released
+		go o.replayWriteQueue()
+	}
 	return nil
 }
 
@@ -301,32 +434,41 @@ func (o *ovsdbClient) connect(ctx context.Context, reconnect bool) error {
 // server ID (if clustered) on success, or an error.
 func (o *ovsdbClient) tryEndpoint(ctx context.Context, u *url.URL) (string, error) {
 	o.logger.V(5).Info("trying to connect", "endpoint", fmt.Sprintf("%v", u))
-	var dialer net.Dialer
-	var err error
-	var c net.Conn
-
-	switch u.Scheme {
-	case UNIX:
-		c, err = dialer.DialContext(ctx, u.Scheme, u.Path)
-	case TCP:
-		c, err = dialer.DialContext(ctx, u.Scheme, u.Opaque)
-	case SSL:
-		dialer := tls.Dialer{
-			Config: o.options.tlsConfig,
-		}
-		c, err = dialer.DialContext(ctx, "tcp", u.Opaque)
-	default:
-		err = fmt.Errorf("unknown network protocol %s", u.Scheme)
-	}
+	c, err := o.dial(ctx, u)
 	if err != nil {
-		return "", fmt.Errorf("failed to open connection: %w", err)
+		return "", err
 	}
 
-	o.createRPC2Client(c)
-
-	serverDBNames, err := o.listDbs(ctx)
-	if err != nil {
-		return "", err
+	var serverDBNames []string
+	compressed := false
+	if o.options.compression != nil {
+		cc, err := o.options.compression.Wrap(c)
+		if err != nil {
+			c.Close()
+			return "", err
+		}
+		o.createRPC2Client(cc)
+		serverDBNames, err = o.listDbs(ctx)
+		if err == nil {
+			compressed = true
+		} else {
+			// the peer does not support the compression, fall back to a plain
+			// connection
+			o.logger.V(3).Info("falling back to an uncompressed connection", "endpoint", fmt.Sprintf("%v", u),
+				"compression", o.options.compression.Name(), "error", err.Error())
+			o.resetRPCClient()
+			c, err = o.dial(ctx, u)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+	if !compressed {
+		o.createRPC2Client(c)
+		serverDBNames, err = o.listDbs(ctx)
+		if err != nil {
+			return "", err
+		}
 	}
 
 	// for every requested database, ensure the DB exists in the server and
@@ -349,6 +491,9 @@ func (o *ovsdbClient) tryEndpoint(ctx context.Context, u *url.URL) (string, erro
 		if err != nil {
 			return "", err
 		}
+		if dbName == o.primaryDBName {
+			o.setCurrentSchema(schema)
+		}
 
 		db.modelMutex.Lock()
 		var errors []error
@@ -364,13 +509,24 @@ func (o *ovsdbClient) tryEndpoint(ctx context.Context, u *url.URL) (string, erro
 		}
 
 		db.cacheMutex.Lock()
-		if db.cache == nil {
+		if db.withoutCache {
+			db.api = newCachelessAPI(db.model, o.logger)
+			if o.options.versionGuard {
+				db.api = db.api.(api).withVersionGuard()
+			}
+		} else if db.cache == nil {
 			db.cache, err = cache.NewTableCache(db.model, nil, o.logger)
 			if err != nil {
 				db.cacheMutex.Unlock()
 				return "", err
 			}
+			for table, filter := range o.options.filters {
+				db.cache.SetFilter(table, filter)
+			}
 			db.api = newAPI(db.cache, o.logger)
+			if o.options.versionGuard {
+				db.api = db.api.(api).withVersionGuard()
+			}
 		} else {
 			db.cache.Purge(db.model)
 		}
@@ -392,11 +548,42 @@ func (o *ovsdbClient) tryEndpoint(ctx context.Context, u *url.URL) (string, erro
 	return sid, nil
 }
 
+// dial opens a connection to the endpoint
+func (o *ovsdbClient) dial(ctx context.Context, u *url.URL) (net.Conn, error) {
+	var dialer net.Dialer
+	var err error
+	var c net.Conn
+
+	switch u.Scheme {
+	case UNIX:
+		c, err = dialer.DialContext(ctx, u.Scheme, u.Path)
+	case TCP:
+		c, err = dialer.DialContext(ctx, u.Scheme, u.Opaque)
+	case SSL:
+		dialer := tls.Dialer{
+			Config: o.options.tlsConfig,
+		}
+		c, err = dialer.DialContext(ctx, "tcp", u.Opaque)
+	default:
+		err = fmt.Errorf("unknown network protocol %s", u.Scheme)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open connection: %w", err)
+	}
+	if o.options.writeBufferSize > 0 {
+		c = newBufferedConn(c, o.options.writeBufferSize, o.options.writeBufferInterval, o.options.clock)
+	}
+	return c, nil
+}
+
 // createRPC2Client creates an rpcClient using the provided connection
 // It is also responsible for setting up go routines for client-side event handling
 // Should only be called when the mutex is held
 func (o *ovsdbClient) createRPC2Client(conn net.Conn) {
 	o.stopCh = make(chan struct{})
+	if o.options.wireTap != nil {
+		conn = newTapConn(conn, o.options.wireTap)
+	}
 	o.rpcClient = rpc2.NewClientWithCodec(jsonrpc.NewJSONCodec(conn))
 	o.rpcClient.SetBlocking(true)
 	o.rpcClient.Handle("echo", func(_ *rpc2.Client, args []interface{}, reply *[]interface{}) error {
@@ -411,6 +598,12 @@ func (o *ovsdbClient) createRPC2Client(conn net.Conn) {
 	o.rpcClient.Handle("update3", func(_ *rpc2.Client, args []json.RawMessage, reply *[]interface{}) error {
 		return o.update3(args, reply)
 	})
+	o.rpcClient.Handle("locked", func(_ *rpc2.Client, args []interface{}, reply *[]interface{}) error {
+		return o.lockNotification(args, true)
+	})
+	o.rpcClient.Handle("stolen", func(_ *rpc2.Client, args []interface{}, reply *[]interface{}) error {
+		return o.lockNotification(args, false)
+	})
 	go o.rpcClient.Run()
 }
 
@@ -490,9 +683,28 @@ func (o *ovsdbClient) Schema() ovsdb.DatabaseSchema {
 	return db.model.Schema
 }
 
+// CurrentSchema returns the schema of the database as last reported by the
+// server, to validate rows against without fetching it again. It is the
+// schema negotiated on connection, and it is replaced by the new schema
+// after a convert: on reconnection, or as soon as the server reports it when
+// the client was created WithSchemaChangeHandler. Unlike Schema, it may be
+// newer than the schema of the model in use
+func (o *ovsdbClient) CurrentSchema() ovsdb.DatabaseSchema {
+	o.currentSchemaMutex.RLock()
+	defer o.currentSchemaMutex.RUnlock()
+	return o.currentSchema
+}
+
+func (o *ovsdbClient) setCurrentSchema(schema ovsdb.DatabaseSchema) {
+	o.currentSchemaMutex.Lock()
+	defer o.currentSchemaMutex.Unlock()
+	o.currentSchema = schema
+}
+
 // Cache returns the TableCache that is populated from
 // ovsdb update notifications. It will be nil until a connection
-// has been established, and empty unless you call Monitor
+// has been established, and empty unless you call Monitor. It is
+// always nil if the client was created WithoutCache
 func (o *ovsdbClient) Cache() *cache.TableCache {
 	db := o.primaryDB()
 	db.cacheMutex.RLock()
@@ -536,9 +748,16 @@ func (o *ovsdbClient) DisconnectNotify() chan struct{} {
 // RFC 7047 : Section 4.1.6 : Echo
 func (o *ovsdbClient) echo(args []interface{}, reply *[]interface{}) error {
 	*reply = args
+	o.echoSucceeded()
 	return nil
 }
 
+func (o *ovsdbClient) echoSucceeded() {
+	o.lastEchoMutex.Lock()
+	defer o.lastEchoMutex.Unlock()
+	o.lastEcho = o.options.clock.Now()
+}
+
 // RFC 7047 : Update Notification Section 4.1.6
 // params is an array of length 2: [json-value, table-updates]
 // - json-value: the arbitrary json-value passed when creating the Monitor, i.e. the "cookie"
@@ -568,6 +787,17 @@ func (o *ovsdbClient) update(params []json.RawMessage, reply *[]interface{}) err
 	}
 
 	db.cacheMutex.Lock()
+	if _, ok := db.cancelledMonitors[cookie.ID]; ok {
+		// a late update of a cancelled monitor
+		db.cacheMutex.Unlock()
+		return nil
+	}
+	if pending, ok := db.pendingMonitors[cookie.ID]; ok {
+		// an update of a monitor received before its reply
+		db.pendingMonitors[cookie.ID] = append(pending, &bufferedUpdate{&updates, nil, ""})
+		db.cacheMutex.Unlock()
+		return nil
+	}
 	if db.deferUpdates {
 		db.deferredUpdates = append(db.deferredUpdates, &bufferedUpdate{&updates, nil, ""})
 		db.cacheMutex.Unlock()
@@ -605,6 +835,17 @@ func (o *ovsdbClient) update2(params []json.RawMessage, reply *[]interface{}) er
 	}
 
 	db.cacheMutex.Lock()
+	if _, ok := db.cancelledMonitors[cookie.ID]; ok {
+		// a late update of a cancelled monitor
+		db.cacheMutex.Unlock()
+		return nil
+	}
+	if pending, ok := db.pendingMonitors[cookie.ID]; ok {
+		// an update of a monitor received before its reply
+		db.pendingMonitors[cookie.ID] = append(pending, &bufferedUpdate{nil, &updates, ""})
+		db.cacheMutex.Unlock()
+		return nil
+	}
 	if db.deferUpdates {
 		db.deferredUpdates = append(db.deferredUpdates, &bufferedUpdate{nil, &updates, ""})
 		db.cacheMutex.Unlock()
@@ -648,6 +889,17 @@ func (o *ovsdbClient) update3(params []json.RawMessage, reply *[]interface{}) er
 	}
 
 	db.cacheMutex.Lock()
+	if _, ok := db.cancelledMonitors[cookie.ID]; ok {
+		// a late update of a cancelled monitor
+		db.cacheMutex.Unlock()
+		return nil
+	}
+	if pending, ok := db.pendingMonitors[cookie.ID]; ok {
+		// an update of a monitor received before its reply
+		db.pendingMonitors[cookie.ID] = append(pending, &bufferedUpdate{nil, &updates, lastTransactionID})
+		db.cacheMutex.Unlock()
+		return nil
+	}
 	if db.deferUpdates {
 		db.deferredUpdates = append(db.deferredUpdates, &bufferedUpdate{nil, &updates, lastTransactionID})
 		db.cacheMutex.Unlock()
@@ -657,13 +909,14 @@ func (o *ovsdbClient) update3(params []json.RawMessage, reply *[]interface{}) er
 
 	// Update the local DB cache with the tableUpdates
 	db.cacheMutex.RLock()
-	err = db.cache.Update2(cookie, updates)
+	err = db.cache.Update3(cookie, lastTransactionID, updates)
 	db.cacheMutex.RUnlock()
 
 	if err == nil {
 		db.monitorsMutex.Lock()
-		mon := db.monitors[cookie.ID]
-		mon.LastTransactionID = lastTransactionID
+		if mon, ok := db.monitors[cookie.ID]; ok {
+			mon.LastTransactionID = lastTransactionID
+		}
 		db.monitorsMutex.Unlock()
 	}
 
@@ -703,41 +956,432 @@ func (o *ovsdbClient) listDbs(ctx context.Context) ([]string, error) {
 
 // Transact performs the provided Operations on the database
 // RFC 7047 : transact
+// An empty list of operations is a no-op: it returns an empty result without
+// contacting the server
+// If the client was created with WithWriteQueue, operations submitted while
+// disconnected are queued and replayed once the client has reconnected
 func (o *ovsdbClient) Transact(ctx context.Context, operation ...ovsdb.Operation) ([]ovsdb.OperationResult, error) {
+	return o.transactPrimary(ctx, nil, operation...)
+}
+
+// TransactRows is like Transact, except that the rows of the select results
+// are passed to fn one at a time and in order as they are decoded from the
+// reply, instead of being returned in the Rows of the results, which bounds
+// the memory needed to process huge selects. An error returned by fn fails
+// the call. Unlike Transact, the transaction is never queued by WithWriteQueue
+func (o *ovsdbClient) TransactRows(ctx context.Context, fn ovsdb.RowHandler, operation ...ovsdb.Operation) ([]ovsdb.OperationResult, error) {
+	if fn == nil {
+		return nil, fmt.Errorf("invalid nil row handler")
+	}
+	return o.transactPrimary(ctx, fn, operation...)
+}
+
+// TransactWithLock is like Transact, except that the transaction asserts that
+// the client holds the given lock, acquired with Lock or Steal, and is only
+// committed if it does. Otherwise, a *LockNotHeldError is returned. The
+// transaction fails without being sent while the client does not know itself
+// to hold the lock, such as after a reconnect until the lock is granted again.
+// The results of the operations are returned in the same order, without the
+// one of the assertion
+func (o *ovsdbClient) TransactWithLock(ctx context.Context, lockID string, operation ...ovsdb.Operation) ([]ovsdb.OperationResult, error) {
+	if len(operation) == 0 {
+		return []ovsdb.OperationResult{}, nil
+	}
+	if !o.lockHeld(lockID) {
+		return nil, &LockNotHeldError{LockID: lockID}
+	}
+	lock := lockID
+	ops := append([]ovsdb.Operation{{Op: ovsdb.OperationAssert, Lock: &lock}}, operation...)
+	results, err := o.Transact(ctx, ops...)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no result for the assertion of lock %s", lockID)
+	}
+	if errs, _ := ovsdb.CheckOperationResults(results[:1], ops[:1]); len(errs) > 0 {
+		if _, ok := errs[0].(*ovsdb.NotOwner); ok {
+			o.setLockHeld(lockID, false)
+			return nil, &LockNotHeldError{LockID: lockID}
+		}
+		return nil, errs[0]
+	}
+	return results[1:], nil
+}
+
+// Lock tries to acquire the lock with the given id for the client, returning
+// whether the server granted it. A lock not granted right away is held once
+// the server sends the locked notification. Locks are released by Unlock and
+// lost when the client disconnects; on reconnect, they are requested again
+// and are not held until the server grants them
+func (o *ovsdbClient) Lock(ctx context.Context, lockID string) (bool, error) {
+	o.rpcMutex.RLock()
+	defer o.rpcMutex.RUnlock()
+	if o.rpcClient == nil {
+		return false, ErrNotConnected
+	}
+	o.locksMutex.Lock()
+	if _, ok := o.locks[lockID]; !ok {
+		o.locks[lockID] = false
+	}
+	o.locksMutex.Unlock()
+	return o.lock(ctx, "lock", lockID)
+}
+
+// Steal acquires the lock with the given id for the client, taking it away
+// from the client holding it, if any
+func (o *ovsdbClient) Steal(ctx context.Context, lockID string) error {
+	o.rpcMutex.RLock()
+	defer o.rpcMutex.RUnlock()
+	if o.rpcClient == nil {
+		return ErrNotConnected
+	}
+	o.locksMutex.Lock()
+	if _, ok := o.locks[lockID]; !ok {
+		o.locks[lockID] = false
+	}
+	o.locksMutex.Unlock()
+	_, err := o.lock(ctx, "steal", lockID)
+	return err
+}
+
+// lock sends a lock or steal RPC and records whether the lock was granted.
+// Assumes rpcMutex is held
+func (o *ovsdbClient) lock(ctx context.Context, method, lockID string) (bool, error) {
+	var reply ovsdb.LockResult
+	if err := o.rpcClient.CallWithContext(ctx, method, ovsdb.NewLockArgs(lockID), &reply); err != nil {
+		if err == rpc2.ErrShutdown {
+			return false, ErrNotConnected
+		}
+		return false, err
+	}
+	if reply.Locked {
+		o.setLockHeld(lockID, true)
+	}
+	return reply.Locked, nil
+}
+
+// Unlock releases the lock with the given id held by the client
+func (o *ovsdbClient) Unlock(ctx context.Context, lockID string) error {
+	o.rpcMutex.RLock()
+	defer o.rpcMutex.RUnlock()
+	if o.rpcClient == nil {
+		return ErrNotConnected
+	}
+	o.locksMutex.Lock()
+	delete(o.locks, lockID)
+	o.locksMutex.Unlock()
+	var reply interface{}
+	if err := o.rpcClient.CallWithContext(ctx, "unlock", ovsdb.NewLockArgs(lockID), &reply); err != nil {
+		if err == rpc2.ErrShutdown {
+			return ErrNotConnected
+		}
+		return err
+	}
+	return nil
+}
+
+// lockHeld returns whether the client knows itself to hold a lock
+func (o *ovsdbClient) lockHeld(lockID string) bool {
+	o.locksMutex.Lock()
+	defer o.locksMutex.Unlock()
+	return o.locks[lockID]
+}
+
+// setLockHeld records whether the client holds a lock it requested. Locks
+// released with Unlock are not recorded again
+func (o *ovsdbClient) setLockHeld(lockID string, held bool) {
+	o.locksMutex.Lock()
+	defer o.locksMutex.Unlock()
+	if _, ok := o.locks[lockID]; ok {
+		o.locks[lockID] = held
+	}
+}
+
+// RFC 7047 : Locked Notification Section 4.1.9 and Stolen Notification
+// Section 4.1.10
+func (o *ovsdbClient) lockNotification(args []interface{}, held bool) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected a single lock id, got %d arguments", len(args))
+	}
+	lockID, ok := args[0].(string)
+	if !ok {
+		return fmt.Errorf("lock id %v is not a string", args[0])
+	}
+	o.logger.V(3).Info("lock notification", "lock", lockID, "held", held)
+	o.setLockHeld(lockID, held)
+	return nil
+}
+
+// loseLocks marks all the locks requested by the client as not held, as
+// happens when the connection is lost. If forget is set, the locks are not
+// requested again on reconnect
+func (o *ovsdbClient) loseLocks(forget bool) {
+	o.locksMutex.Lock()
+	defer o.locksMutex.Unlock()
+	if forget {
+		o.locks = make(map[string]bool)
+		return
+	}
+	for lockID := range o.locks {
+		o.locks[lockID] = false
+	}
+}
+
+// reacquireLocks requests again the locks of the client after a reconnect.
+// Assumes rpcMutex is held
+func (o *ovsdbClient) reacquireLocks(ctx context.Context) error {
+	o.locksMutex.Lock()
+	lockIDs := make([]string, 0, len(o.locks))
+	for lockID := range o.locks {
+		lockIDs = append(lockIDs, lockID)
+	}
+	o.locksMutex.Unlock()
+	for _, lockID := range lockIDs {
+		locked, err := o.lock(ctx, "lock", lockID)
+		if err != nil {
+			return fmt.Errorf("failed to request lock %s: %w", lockID, err)
+		}
+		o.logger.V(3).Info("reconnected - requested lock", "lock", lockID, "locked", locked)
+	}
+	return nil
+}
+
+// transactPrimary sends a transaction to the primary database, waiting for a
+// reconnection if needed. If fn is not nil, the rows of the results are
+// passed to it instead of being returned
+func (o *ovsdbClient) transactPrimary(ctx context.Context, fn ovsdb.RowHandler, operation ...ovsdb.Operation) ([]ovsdb.OperationResult, error) {
+	if len(operation) == 0 {
+		return []ovsdb.OperationResult{}, nil
+	}
+	if o.options.readOnly {
+		if err := checkReadOnly(operation); err != nil {
+			return nil, err
+		}
+	}
+	if o.options.reconnect && o.options.writeQueue && fn == nil {
+		if txn := o.enqueueTransaction(operation); txn != nil {
+			return o.waitQueuedTransaction(ctx, txn)
+		}
+	}
+	if err := o.waitConnected(ctx, operation); err != nil {
+		return nil, err
+	}
+	rpcClient := o.rpcClient
+	results, err := o.transactRows(ctx, o.primaryDBName, fn, operation...)
+	o.rpcMutex.RUnlock()
+	retry := o.options.leaderRetry && o.options.leaderOnly && o.options.reconnect
+	if fn != nil || !retry || !isNotLeader(results, err) {
+		return results, err
+	}
+	// the endpoint lost the leadership since the client connected to it
+	o.rpcMutex.Lock()
+	if o.rpcClient == rpcClient && o.connected {
+		o.logger.V(3).Info("transaction rejected by a follower, reconnecting to the leader",
+			"endpoint", o.endpoints[0].address)
+		o.moveEndpointLast(0)
+		o._disconnect()
+	}
+	o.rpcMutex.Unlock()
+	if err := o.waitConnected(ctx, operation); err != nil {
+		return nil, err
+	}
+	defer o.rpcMutex.RUnlock()
+	return o.transactRows(ctx, o.primaryDBName, fn, operation...)
+}
+
+// waitConnected returns once the client is connected, with a read lock on
+// rpcMutex that the caller must release. If the client was created with
+// WithReconnect, it waits for the client to reconnect, otherwise it fails
+// with ErrNotConnected
+func (o *ovsdbClient) waitConnected(ctx context.Context, operation []ovsdb.Operation) error {
 	o.rpcMutex.RLock()
 	if o.rpcClient == nil || !o.connected {
 		o.rpcMutex.RUnlock()
 		if o.options.reconnect {
 			o.logger.V(5).Info("blocking transaction until reconnected", "operations",
 				fmt.Sprintf("%+v", operation))
-			ticker := time.NewTicker(50 * time.Millisecond)
+			ticker := o.options.clock.NewTicker(50 * time.Millisecond)
 			defer ticker.Stop()
 		ReconnectWaitLoop:
 			for {
 				select {
 				case <-ctx.Done():
-					return nil, fmt.Errorf("%w: while awaiting reconnection", ctx.Err())
-				case <-ticker.C:
+					return fmt.Errorf("%w: while awaiting reconnection", ctx.Err())
+				case <-ticker.C():
 					o.rpcMutex.RLock()
 					if o.rpcClient != nil && o.connected {
 						break ReconnectWaitLoop
 					}
+					if err := o.reconnectErr; err != nil {
+						o.rpcMutex.RUnlock()
+						return err
+					}
 					o.rpcMutex.RUnlock()
 				}
 			}
 		} else {
-			return nil, ErrNotConnected
+			return ErrNotConnected
 		}
 	}
-	defer o.rpcMutex.RUnlock()
-	return o.transact(ctx, o.primaryDBName, operation...)
+	return nil
+}
+
+// notLeader is the error of the transactions a clustered server rejects
+// because it is not the leader of the cluster
+const notLeader = "not leader"
+
+// isNotLeader returns whether a transaction was rejected because the server is
+// not the leader, either as the error of the call or of one of its results
+func isNotLeader(results []ovsdb.OperationResult, err error) bool {
+	if err != nil {
+		return err.Error() == notLeader
+	}
+	for _, result := range results {
+		if result.Error == notLeader {
+			return true
+		}
+	}
+	return false
+}
+
+// Insert creates the given models in the database in a single transaction.
+// It returns the UUIDs assigned by the server to the new rows, in the same
+// order as the models, and sets them in the models' _uuid field
+func (o *ovsdbClient) Insert(ctx context.Context, models ...model.Model) ([]string, error) {
+	ops, err := o.Create(models...)
+	if err != nil {
+		return nil, err
+	}
+	results, err := o.Transact(ctx, ops...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ovsdb.CheckOperationResults(results, ops); err != nil {
+		return nil, err
+	}
+	db := o.primaryDB()
+	db.modelMutex.RLock()
+	defer db.modelMutex.RUnlock()
+	uuids := make([]string, 0, len(models))
+	for i, m := range models {
+		uuid := results[i].UUID.GoUUID
+		if uuid == "" {
+			return nil, fmt.Errorf("no uuid returned for the insert of %v", m)
+		}
+		info, err := db.model.NewModelInfo(m)
+		if err != nil {
+			return nil, err
+		}
+		if err := info.SetField("_uuid", uuid); err != nil {
+			return nil, err
+		}
+		uuids = append(uuids, uuid)
+	}
+	return uuids, nil
+}
+
+// GetByUUIDs returns the rows of a table of the primary database with the given
+// UUIDs, in the same order. The rows are looked up in the cache, and the ones
+// missing from it are fetched from the server in a single transaction. Without
+// a cache, all of them are fetched from the server. If any of the rows does
+// not exist, an error wrapping ErrNotFound and listing the missing UUIDs is
+// returned
+func (o *ovsdbClient) GetByUUIDs(ctx context.Context, table string, uuids []string) ([]model.Model, error) {
+	primaryDB := o.primaryDB()
+	primaryDB.modelMutex.RLock()
+	_, ok := primaryDB.model.Types()[table]
+	primaryDB.modelMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("table %s not found in the model", table)
+	}
+
+	models := make([]model.Model, len(uuids))
+	var ops []ovsdb.Operation
+	var missing []int
+	waitForCacheConsistent(ctx, primaryDB, o.options.clock, o.logger, o.primaryDBName)
+	var tableCache *cache.RowCache
+	if primaryDB.cache != nil {
+		tableCache = primaryDB.cache.Table(table)
+	}
+	for i, uuid := range uuids {
+		if tableCache != nil {
+			if m := tableCache.Row(uuid); m != nil {
+				models[i] = m
+				continue
+			}
+		}
+		missing = append(missing, i)
+		ops = append(ops, ovsdb.Operation{
+			Op:    ovsdb.OperationSelect,
+			Table: table,
+			Where: []ovsdb.Condition{ovsdb.NewCondition("_uuid", ovsdb.ConditionEqual, ovsdb.UUID{GoUUID: uuid})},
+		})
+	}
+	primaryDB.cacheMutex.RUnlock()
+	if len(ops) == 0 {
+		return models, nil
+	}
+
+	results, err := o.Transact(ctx, ops...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ovsdb.CheckOperationResults(results, ops); err != nil {
+		return nil, err
+	}
+	primaryDB.cacheMutex.RLock()
+	defer primaryDB.cacheMutex.RUnlock()
+	var notFound []string
+	for i, result := range results {
+		uuid := uuids[missing[i]]
+		if len(result.Rows) == 0 {
+			notFound = append(notFound, uuid)
+			continue
+		}
+		m, err := primaryDB.api.(api).createModel(table, &result.Rows[0], uuid)
+		if err != nil {
+			return nil, err
+		}
+		models[missing[i]] = m
+	}
+	if len(notFound) > 0 {
+		return nil, fmt.Errorf("%w: %s rows %s", ErrNotFound, table, strings.Join(notFound, ", "))
+	}
+	return models, nil
+}
+
+// checkReadOnly returns a *ReadOnlyError for the first of the operations
+// writing to the database
+func checkReadOnly(operation []ovsdb.Operation) error {
+	for _, op := range operation {
+		switch op.Op {
+		case ovsdb.OperationInsert, ovsdb.OperationUpdate, ovsdb.OperationDelete, ovsdb.OperationMutate:
+			return &ReadOnlyError{Op: op.Op, Table: op.Table}
+		}
+	}
+	return nil
 }
 
 func (o *ovsdbClient) transact(ctx context.Context, dbName string, operation ...ovsdb.Operation) ([]ovsdb.OperationResult, error) {
+	return o.transactRows(ctx, dbName, nil, operation...)
+}
+
+// transactRows sends a transaction to a database. If fn is not nil, the rows
+// of the results are passed to it instead of being returned. The name of the
+// database is the first param of the transaction, which the server, or the
+// clustered server it forwards the transaction to, uses to select the database
+func (o *ovsdbClient) transactRows(ctx context.Context, dbName string, fn ovsdb.RowHandler, operation ...ovsdb.Operation) ([]ovsdb.OperationResult, error) {
 	var reply []ovsdb.OperationResult
-	db := o.databases[dbName]
+	if dbName == "" {
+		return nil, fmt.Errorf("cannot transact: empty database name")
+	}
+	db, ok := o.databases[dbName]
+	if !ok {
+		return nil, fmt.Errorf("cannot transact to database %s: not a database of the client", dbName)
+	}
 	db.modelMutex.RLock()
-	schema := o.databases[dbName].model.Schema
+	schema := db.model.Schema
 	db.modelMutex.RUnlock()
 	if reflect.DeepEqual(schema, ovsdb.DatabaseSchema{}) {
 		return nil, fmt.Errorf("cannot transact to database %s: schema unknown", dbName)
@@ -751,16 +1395,45 @@ func (o *ovsdbClient) transact(ctx context.Context, dbName string, operation ...
 		return nil, ErrNotConnected
 	}
 	o.logger.V(5).Info("transacting operations", "database", dbName, "operations", fmt.Sprintf("%+v", operation))
-	err := o.rpcClient.CallWithContext(ctx, "transact", args, &reply)
+	var raw json.RawMessage
+	err := o.rpcClient.CallWithContext(ctx, "transact", args, &raw)
 	if err != nil {
 		if err == rpc2.ErrShutdown {
 			return nil, ErrNotConnected
 		}
 		return nil, err
 	}
+	if fn != nil {
+		return o.decodeReplyRows(raw, fn)
+	}
+	if err := o.decodeReply(raw, &reply); err != nil {
+		return nil, err
+	}
 	return reply, nil
 }
 
+// decodeReply decodes a reply from the server. If the client was created with
+// WithRejectDuplicateKeys, replies holding duplicate keys are rejected
+func (o *ovsdbClient) decodeReply(raw json.RawMessage, reply interface{}) error {
+	if o.options.rejectDuplicateKeys {
+		if err := ovsdb.CheckDuplicateKeys(raw); err != nil {
+			return fmt.Errorf("invalid reply: %w", err)
+		}
+	}
+	return json.Unmarshal(raw, reply)
+}
+
+// decodeReplyRows decodes the reply of a transaction, passing the rows of its
+// results to fn
+func (o *ovsdbClient) decodeReplyRows(raw json.RawMessage, fn ovsdb.RowHandler) ([]ovsdb.OperationResult, error) {
+	if o.options.rejectDuplicateKeys {
+		if err := ovsdb.CheckDuplicateKeys(raw); err != nil {
+			return nil, fmt.Errorf("invalid reply: %w", err)
+		}
+	}
+	return ovsdb.DecodeOperationResults(bytes.NewReader(raw), fn)
+}
+
 // MonitorAll is a convenience method to monitor every table/column
 func (o *ovsdbClient) MonitorAll(ctx context.Context) (MonitorCookie, error) {
 	m := newMonitor()
@@ -772,17 +1445,43 @@ func (o *ovsdbClient) MonitorAll(ctx context.Context) (MonitorCookie, error) {
 
 // MonitorCancel will request cancel a previously issued monitor request
 // RFC 7047 : monitor_cancel
+// The monitor is removed from the client before the request is sent, so it is
+// not restarted on reconnection and the updates that the server may still
+// send for it are ignored, whether the reply is late or never comes. If the
+// client is disconnected, the server has dropped the monitor along with the
+// connection and no request is sent.
 func (o *ovsdbClient) MonitorCancel(ctx context.Context, cookie MonitorCookie) error {
+	db := o.databases[cookie.DatabaseName]
+	if db == nil {
+		return fmt.Errorf("monitor cancel: invalid database name: %s unknown", cookie.DatabaseName)
+	}
+	db.monitorsMutex.Lock()
+	_, registered := db.monitors[cookie.ID]
+	if registered {
+		delete(db.monitors, cookie.ID)
+		o.metrics.numMonitors.Dec()
+	}
+	db.monitorsMutex.Unlock()
+	db.cacheMutex.Lock()
+	db.cancelledMonitors[cookie.ID] = struct{}{}
+	db.cacheMutex.Unlock()
+
 	var reply ovsdb.OperationResult
 	args := ovsdb.NewMonitorCancelArgs(cookie)
-	o.rpcMutex.Lock()
-	defer o.rpcMutex.Unlock()
+	o.rpcMutex.RLock()
+	defer o.rpcMutex.RUnlock()
 	if o.rpcClient == nil {
+		if registered {
+			return nil
+		}
 		return ErrNotConnected
 	}
 	err := o.rpcClient.CallWithContext(ctx, "monitor_cancel", args, &reply)
 	if err != nil {
 		if err == rpc2.ErrShutdown {
+			if registered {
+				return nil
+			}
 			return ErrNotConnected
 		}
 		return err
@@ -790,16 +1489,14 @@ func (o *ovsdbClient) MonitorCancel(ctx context.Context, cookie MonitorCookie) e
 	if reply.Error != "" {
 		return fmt.Errorf("error while executing transaction: %s", reply.Error)
 	}
-	o.primaryDB().monitorsMutex.Lock()
-	defer o.primaryDB().monitorsMutex.Unlock()
-	delete(o.primaryDB().monitors, cookie.ID)
-	o.metrics.numMonitors.Dec()
 	return nil
 }
 
 // Monitor will provide updates for a given table/column
 // and populate the cache with them. Subsequent updates will be processed
-// by the Update Notifications
+// by the Update Notifications. If the connection is closed before the initial
+// dump is received, or the dump fails to populate the cache, an error is
+// returned and no part of the dump is left in the cache
 // RFC 7047 : monitor
 func (o *ovsdbClient) Monitor(ctx context.Context, monitor *Monitor) (MonitorCookie, error) {
 	cookie := newMonitorCookie(o.primaryDBName)
@@ -812,6 +1509,9 @@ func (o *ovsdbClient) Monitor(ctx context.Context, monitor *Monitor) (MonitorCoo
 //gocyclo:ignore
 // monitor must only be called with a lock on monitorsMutex
 func (o *ovsdbClient) monitor(ctx context.Context, cookie MonitorCookie, reconnecting bool, monitor *Monitor) error {
+	if o.databases[cookie.DatabaseName].withoutCache {
+		return ErrCacheDisabled
+	}
 	// if we're reconnecting, we already hold the rpcMutex
 	if !reconnecting {
 		o.rpcMutex.RLock()
@@ -832,6 +1532,7 @@ func (o *ovsdbClient) monitor(ctx context.Context, cookie MonitorCookie, reconne
 	}
 	dbName := cookie.DatabaseName
 	db := o.databases[dbName]
+	monitor.synced = false
 	db.modelMutex.RLock()
 	mmapper := db.model.Mapper
 	typeMap := db.model.Types()
@@ -841,9 +1542,15 @@ func (o *ovsdbClient) monitor(ctx context.Context, cookie MonitorCookie, reconne
 		if !ok {
 			return fmt.Errorf("type for table %s does not exist in model", o.Table)
 		}
-		model, err := db.model.NewModel(o.Table)
-		if err != nil {
-			return err
+		// the fields and the condition point to the model given to the
+		// MonitorOption, if any
+		model := o.model
+		if model == nil {
+			var err error
+			model, err = db.model.NewModel(o.Table)
+			if err != nil {
+				return err
+			}
 		}
 		info, err := db.model.NewModelInfo(model)
 		if err != nil {
@@ -853,6 +1560,14 @@ func (o *ovsdbClient) monitor(ctx context.Context, cookie MonitorCookie, reconne
 		if err != nil {
 			return err
 		}
+		request.Select = o.Select
+		if o.Condition.Field != nil {
+			condition, err := mmapper.NewCondition(info, o.Condition.Field, o.Condition.Function, o.Condition.Value)
+			if err != nil {
+				return err
+			}
+			request.Where = []ovsdb.Condition{*condition}
+		}
 		requests[o.Table] = *request
 	}
 	db.modelMutex.RUnlock()
@@ -870,6 +1585,15 @@ func (o *ovsdbClient) monitor(ctx context.Context, cookie MonitorCookie, reconne
 	}
 	var err error
 	var tableUpdates interface{}
+	var lastTransactionID string
+
+	// the updates of the monitor received before its reply are buffered
+	db.cacheMutex.Lock()
+	if db.pendingMonitors == nil {
+		db.pendingMonitors = make(map[string][]*bufferedUpdate)
+	}
+	db.pendingMonitors[cookie.ID] = nil
+	db.cacheMutex.Unlock()
 
 	switch monitor.Method {
 	case ovsdb.MonitorRPC:
@@ -886,14 +1610,21 @@ func (o *ovsdbClient) monitor(ctx context.Context, cookie MonitorCookie, reconne
 		if err == nil && reply.Found {
 			monitor.LastTransactionID = reply.LastTransactionID
 		}
+		lastTransactionID = reply.LastTransactionID
 		tableUpdates = reply.Updates
 	default:
-		return fmt.Errorf("unsupported monitor method: %v", monitor.Method)
+		err = fmt.Errorf("unsupported monitor method: %v", monitor.Method)
 	}
 
+	db.cacheMutex.Lock()
+	early := db.pendingMonitors[cookie.ID]
+	delete(db.pendingMonitors, cookie.ID)
 	if err != nil {
-		if err == rpc2.ErrShutdown {
-			return ErrNotConnected
+		db.cacheMutex.Unlock()
+		if err == rpc2.ErrShutdown || err == io.EOF || err == io.ErrUnexpectedEOF {
+			// the connection was closed before the whole initial dump was
+			// received, none of it has been populated
+			return fmt.Errorf("%w: connection closed before the initial dump of monitor %s was received", ErrNotConnected, cookie.ID)
 		}
 		if err.Error() == "unknown method" {
 			if monitor.Method == ovsdb.ConditionalMonitorSinceRPC {
@@ -909,48 +1640,139 @@ func (o *ovsdbClient) monitor(ctx context.Context, cookie MonitorCookie, reconne
 		}
 		return err
 	}
-
-	if !reconnecting {
-		db.monitors[cookie.ID] = monitor
-		o.metrics.numMonitors.Inc()
-	}
-
-	db.cacheMutex.Lock()
 	defer db.cacheMutex.Unlock()
+
+	var created map[string][]string
+	metadata := cache.EventMetadata{Database: dbName, TransactionID: lastTransactionID, Initial: true}
 	if monitor.Method == ovsdb.MonitorRPC {
 		u := tableUpdates.(ovsdb.TableUpdates)
-		err = db.cache.Populate(u)
+		created = uncachedRows(db.cache, u)
+		err = db.cache.PopulateWithMetadata(u, metadata)
 	} else {
 		u := tableUpdates.(ovsdb.TableUpdates2)
-		err = db.cache.Populate2(u)
+		created = uncachedRows2(db.cache, u)
+		err = db.cache.Populate2WithMetadata(u, metadata)
 	}
 
 	if err != nil {
-		return err
+		// do not leave the part of the dump populated before the error in
+		// the cache, nor register a monitor that is not in sync
+		if undoErr := removeRows(db.cache, created); undoErr != nil {
+			o.logger.V(3).Error(undoErr, "failed to remove the partial initial dump from the cache", "monitor", cookie.ID)
+		}
+		return fmt.Errorf("failed to populate the cache with the initial dump of monitor %s: %w", cookie.ID, err)
+	}
+
+	if !reconnecting {
+		db.monitors[cookie.ID] = monitor
+		o.metrics.numMonitors.Inc()
 	}
 
-	// populate any deferred updates
+	// populate the updates of the monitor received before its reply, then
+	// any deferred updates
+	if err = populateBufferedUpdates(db.cache, early, monitor); err != nil {
+		return err
+	}
 	db.deferUpdates = false
-	for _, update := range db.deferredUpdates {
+	if err = populateBufferedUpdates(db.cache, db.deferredUpdates, monitor); err != nil {
+		return err
+	}
+	// clear deferred updates for next time
+	db.deferredUpdates = make([]*bufferedUpdate, 0)
+	monitor.synced = true
+	for _, table := range monitor.Tables {
+		db.setTableSynced(table.Table)
+	}
+
+	return err
+}
+
+// populateBufferedUpdates populates the cache with the buffered updates, in
+// the order they were received
+func populateBufferedUpdates(tableCache *cache.TableCache, updates []*bufferedUpdate, monitor *Monitor) error {
+	for _, update := range updates {
+		metadata := cache.EventMetadata{TransactionID: update.lastTxnID}
 		if update.updates != nil {
-			if err = db.cache.Populate(*update.updates); err != nil {
+			if err := tableCache.PopulateWithMetadata(*update.updates, metadata); err != nil {
 				return err
 			}
 		}
-
 		if update.updates2 != nil {
-			if err = db.cache.Populate2(*update.updates2); err != nil {
+			if err := tableCache.Populate2WithMetadata(*update.updates2, metadata); err != nil {
 				return err
 			}
 		}
 		if len(update.lastTxnID) > 0 {
-			db.monitors[cookie.ID].LastTransactionID = update.lastTxnID
+			monitor.LastTransactionID = update.lastTxnID
 		}
 	}
-	// clear deferred updates for next time
-	db.deferredUpdates = make([]*bufferedUpdate, 0)
+	return nil
+}
 
-	return err
+// uncachedRows returns the UUIDs of the rows of the updates, by table, that
+// are not in the cache yet
+func uncachedRows(tableCache *cache.TableCache, tableUpdates ovsdb.TableUpdates) map[string][]string {
+	rows := make(map[string][]string)
+	for table, updates := range tableUpdates {
+		for uuid := range updates {
+			rows[table] = append(rows[table], uuid)
+		}
+	}
+	return uncached(tableCache, rows)
+}
+
+// uncachedRows2 is the uncachedRows of TableUpdates2
+func uncachedRows2(tableCache *cache.TableCache, tableUpdates ovsdb.TableUpdates2) map[string][]string {
+	rows := make(map[string][]string)
+	for table, updates := range tableUpdates {
+		for uuid := range updates {
+			rows[table] = append(rows[table], uuid)
+		}
+	}
+	return uncached(tableCache, rows)
+}
+
+func uncached(tableCache *cache.TableCache, rows map[string][]string) map[string][]string {
+	for table, uuids := range rows {
+		rowCache := tableCache.Table(table)
+		if rowCache == nil {
+			delete(rows, table)
+			continue
+		}
+		var missing []string
+		for _, uuid := range uuids {
+			if rowCache.Row(uuid) == nil {
+				missing = append(missing, uuid)
+			}
+		}
+		rows[table] = missing
+	}
+	return rows
+}
+
+// removeRows deletes the given rows, by table, from the cache, as a delete
+// update would, ignoring those not in the cache
+func removeRows(tableCache *cache.TableCache, rows map[string][]string) error {
+	deletes := make(ovsdb.TableUpdates2)
+	for table, uuids := range rows {
+		rowCache := tableCache.Table(table)
+		if rowCache == nil {
+			continue
+		}
+		for _, uuid := range uuids {
+			if rowCache.Row(uuid) == nil {
+				continue
+			}
+			if deletes[table] == nil {
+				deletes[table] = make(ovsdb.TableUpdate2)
+			}
+			deletes[table][uuid] = &ovsdb.RowUpdate2{}
+		}
+	}
+	if len(deletes) == 0 {
+		return nil
+	}
+	return tableCache.Populate2(deletes)
 }
 
 // Echo tests the liveness of the OVSDB connetion
@@ -971,12 +1793,106 @@ func (o *ovsdbClient) Echo(ctx context.Context) error {
 	if !reflect.DeepEqual(args, reply) {
 		return fmt.Errorf("incorrect server response: %v, %v", args, reply)
 	}
+	o.echoSucceeded()
 	return nil
 }
 
+// TableSynced returns a channel that is closed once the initial state of the
+// given table of the primary database has been populated in the cache by a
+// monitor. Unlike Health, which reports on all the monitors, it allows to start
+// processing a table as soon as the monitor including it is synced, without
+// waiting for the monitors of the other tables. The channel remains closed
+// afterwards, even if the client reconnects.
+func (o *ovsdbClient) TableSynced(table string) <-chan struct{} {
+	db := o.primaryDB()
+	db.tableSyncedMutex.Lock()
+	defer db.tableSyncedMutex.Unlock()
+	return db.tableSyncedCh(table)
+}
+
+// HealthStatus aggregates the signals telling whether a client is healthy
+type HealthStatus struct {
+	// Connected is true if the client is connected to the server
+	Connected bool
+	// Monitors tells, for every monitor of the client, whether its initial
+	// state has been populated in the cache
+	Monitors map[MonitorCookie]bool
+	// LastEcho is the time of the last echo successfully exchanged with the
+	// server, in either direction. It is zero if there has not been any
+	LastEcho time.Time
+	// clock is the clock of the client, used to measure the age of LastEcho
+	clock Clock
+}
+
+// Synced returns true if the client has at least one monitor and the initial
+// state of all of them has been populated in the cache
+func (h HealthStatus) Synced() bool {
+	if len(h.Monitors) == 0 {
+		return false
+	}
+	for _, synced := range h.Monitors {
+		if !synced {
+			return false
+		}
+	}
+	return true
+}
+
+// Ready returns true if the client is connected and synced. If maxEchoAge is
+// not zero, the last echo must also have happened within maxEchoAge
+func (h HealthStatus) Ready(maxEchoAge time.Duration) bool {
+	if !h.Connected || !h.Synced() {
+		return false
+	}
+	now := time.Now()
+	if h.clock != nil {
+		now = h.clock.Now()
+	}
+	if maxEchoAge > 0 && (h.LastEcho.IsZero() || now.Sub(h.LastEcho) > maxEchoAge) {
+		return false
+	}
+	return true
+}
+
+// Health returns the HealthStatus of the client, suited for readiness probes
+func (o *ovsdbClient) Health() HealthStatus {
+	status := HealthStatus{
+		Monitors: make(map[MonitorCookie]bool),
+		clock:    o.options.clock,
+	}
+	o.rpcMutex.RLock()
+	status.Connected = o.rpcClient != nil && o.connected
+	o.rpcMutex.RUnlock()
+
+	for dbName, db := range o.databases {
+		db.monitorsMutex.Lock()
+		for id, monitor := range db.monitors {
+			status.Monitors[MonitorCookie{DatabaseName: dbName, ID: id}] = monitor.synced
+		}
+		db.monitorsMutex.Unlock()
+	}
+
+	o.lastEchoMutex.Lock()
+	status.LastEcho = o.lastEcho
+	o.lastEchoMutex.Unlock()
+	return status
+}
+
+// monitorServerDatabases monitors the Database table of the _Server database
+func (o *ovsdbClient) monitorServerDatabases() error {
+	m := newMonitor()
+	// NOTE: _Server does not support monitor_cond_since
+	m.Method = ovsdb.ConditionalMonitorRPC
+	m.Tables = []TableMonitor{{Table: "Database"}}
+	db := o.databases[serverDB]
+	db.monitorsMutex.Lock()
+	defer db.monitorsMutex.Unlock()
+	return o.monitor(context.Background(), newMonitorCookie(serverDB), false, m)
+}
+
 // watchForLeaderChange will trigger a reconnect if the connected endpoint
 // ever loses leadership
-func (o *ovsdbClient) watchForLeaderChange() error {
+func (o *ovsdbClient) watchForLeaderChange() {
 	updates := make(chan model.Model)
 	o.databases[serverDB].cache.AddEventHandler(&cache.EventHandlerFuncs{
 		UpdateFunc: func(table string, _, new model.Model) {
@@ -986,18 +1902,6 @@ func (o *ovsdbClient) watchForLeaderChange() error {
 		},
 	})
 
-	m := newMonitor()
-	// NOTE: _Server does not support monitor_cond_since
-	m.Method = ovsdb.ConditionalMonitorRPC
-	m.Tables = []TableMonitor{{Table: "Database"}}
-	db := o.databases[serverDB]
-	db.monitorsMutex.Lock()
-	defer db.monitorsMutex.Unlock()
-	err := o.monitor(context.Background(), newMonitorCookie(serverDB), false, m)
-	if err != nil {
-		return err
-	}
-
 	go func() {
 		for m := range updates {
 			dbInfo, ok := m.(*serverdb.Database)
@@ -1043,7 +1947,41 @@ func (o *ovsdbClient) watchForLeaderChange() error {
 			o.rpcMutex.Unlock()
 		}
 	}()
-	return nil
+}
+
+// watchForSchemaChange will call the schema change handler whenever the
+// version of the schema of the client database changes. The version is
+// remembered across reconnections, so a change that happened while the client
+// was disconnected is notified once the cache is repopulated
+func (o *ovsdbClient) watchForSchemaChange() {
+	var version string
+	check := func(table string, m model.Model) {
+		dbInfo, ok := m.(*serverdb.Database)
+		if table != "Database" || !ok || dbInfo.Name != o.primaryDBName || dbInfo.Schema == nil {
+			return
+		}
+		var schema ovsdb.DatabaseSchema
+		if err := json.Unmarshal([]byte(*dbInfo.Schema), &schema); err != nil {
+			o.logger.V(3).Error(err, "failed to parse the schema of the database", "name", dbInfo.Name)
+			return
+		}
+		if schema.Version == version {
+			return
+		}
+		oldVersion := version
+		version = schema.Version
+		if oldVersion != "" {
+			o.setCurrentSchema(schema)
+			o.logger.V(3).Info("database schema changed", "from", oldVersion, "to", version)
+			o.options.schemaChangeHandler(dbInfo.Name, oldVersion, version)
+		}
+	}
+	o.databases[serverDB].cache.AddEventHandler(&cache.EventHandlerFuncs{
+		AddFunc: check,
+		UpdateFunc: func(table string, _, new model.Model) {
+			check(table, new)
+		},
+	})
 }
 
 func (o *ovsdbClient) handleCacheErrors(stopCh <-chan struct{}, errorChan <-chan error) {
@@ -1056,16 +1994,7 @@ func (o *ovsdbClient) handleCacheErrors(stopCh <-chan struct{}, errorChan <-chan
 				// trigger a reconnect, which will purge the cache
 				// hopefully a rebuild will fix any inconsistency
 				o.logger.V(3).Error(err, "triggering reconnect to rebuild cache")
-				// for rebuilding cache with mon_cond_since (not yet fully supported in libovsdb) we
-				// need to reset the last txn ID
-				for _, db := range o.databases {
-					db.monitorsMutex.Lock()
-					for _, mon := range db.monitors {
-						mon.LastTransactionID = emptyUUID
-					}
-					db.monitorsMutex.Unlock()
-				}
-				o.Disconnect()
+				o.rebuildCache()
 			} else {
 				o.logger.V(3).Error(err, "error updating cache")
 			}
@@ -1073,10 +2002,73 @@ func (o *ovsdbClient) handleCacheErrors(stopCh <-chan struct{}, errorChan <-chan
 	}
 }
 
+// rebuildCache disconnects from the server so that the cache is purged and
+// populated again from scratch on reconnect
+func (o *ovsdbClient) rebuildCache() {
+	// for rebuilding cache with mon_cond_since (not yet fully supported in libovsdb) we
+	// need to reset the last txn ID
+	for _, db := range o.databases {
+		db.monitorsMutex.Lock()
+		for _, mon := range db.monitors {
+			mon.LastTransactionID = emptyUUID
+		}
+		db.monitorsMutex.Unlock()
+	}
+	o.Disconnect()
+}
+
+// handleInactivityProbe sends an echo to the server at every interval of the
+// inactivity probe until stopCh is closed, and closes the connection if the
+// server does not reply in time
+func (o *ovsdbClient) handleInactivityProbe(stopCh <-chan struct{}) {
+	ticker := o.options.clock.NewTicker(o.options.inactivityProbe)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C():
+			ctx, cancel := withClockTimeout(context.Background(), o.options.clock, o.options.inactivityProbe)
+			err := o.Echo(ctx)
+			cancel()
+			if err == nil {
+				continue
+			}
+			select {
+			case <-stopCh:
+				// the connection was closed while probing
+				return
+			default:
+			}
+			o.logger.V(3).Error(err, "inactivity probe failed, closing the connection")
+			o.Disconnect()
+			return
+		}
+	}
+}
+
+// reconnectBackoff returns the backoff used to reconnect, bounded by the
+// reconnect limits if any. The returned cancel function must be called once
+// the backoff is no longer used.
+func (o *ovsdbClient) reconnectBackoff() (backoff.BackOff, context.CancelFunc) {
+	b := o.options.backoff
+	if o.options.reconnectMaxAttempts > 0 {
+		// the first attempt is not a retry
+		b = backoff.WithMaxRetries(b, uint64(o.options.reconnectMaxAttempts-1))
+	}
+	if o.options.reconnectMaxDuration == 0 {
+		return b, func() {}
+	}
+	ctx, cancel := withClockTimeout(context.Background(), o.options.clock, o.options.reconnectMaxDuration)
+	return backoff.WithContext(b, ctx), cancel
+}
+
 func (o *ovsdbClient) handleDisconnectNotification() {
 	<-o.rpcClient.DisconnectNotify()
 	// close the stopCh, which will stop the cache event processor
 	close(o.stopCh)
+	// the server releases the locks of a client when it disconnects
+	o.loseLocks(false)
 	o.metrics.numDisconnects.Inc()
 	o.rpcMutex.Lock()
 	if o.options.reconnect && !o.shutdown {
@@ -1089,9 +2081,10 @@ func (o *ovsdbClient) handleDisconnectNotification() {
 				db.cacheMutex.Lock()
 				db.deferredUpdates = make([]*bufferedUpdate, 0)
 				db.deferUpdates = true
+				db.cancelledMonitors = make(map[string]struct{})
 				db.cacheMutex.Unlock()
 			}
-			ctx, cancel := context.WithTimeout(context.Background(), o.options.timeout)
+			ctx, cancel := withClockTimeout(context.Background(), o.options.clock, o.options.timeout)
 			defer cancel()
 			err := o.connect(ctx, true)
 			if err != nil {
@@ -1106,20 +2099,33 @@ func (o *ovsdbClient) handleDisconnectNotification() {
 			return err
 		}
 		o.logger.V(3).Info("connection lost, reconnecting", "endpoint", o.endpoints[0].address)
-		err := backoff.Retry(connect, o.options.backoff)
-		if err != nil {
+		b, cancel := o.reconnectBackoff()
+		err := backoff.RetryNotifyWithTimer(connect, b, nil, &clockTimer{clock: o.options.clock})
+		cancel()
+		if err == nil {
+			// this goroutine finishes, and is replaced with a new one (from Connect)
+			return
+		}
+		if o.options.reconnectMaxAttempts == 0 && o.options.reconnectMaxDuration == 0 {
 			// TODO: We should look at passing this back to the
 			// caller to handle
 			panic(err)
 		}
-		// this goroutine finishes, and is replaced with a new one (from Connect)
-		return
+		o.logger.Error(err, "reconnect limit reached, giving up")
+		o.rpcMutex.Lock()
+		o.reconnectErr = fmt.Errorf("%w: %v", ErrReconnectFailed, err)
 	}
 
 	// clear connection state
 	o.rpcClient = nil
+	queueErr := o.reconnectErr
 	o.rpcMutex.Unlock()
 
+	if queueErr == nil {
+		queueErr = ErrNotConnected
+	}
+	o.failWriteQueue(queueErr)
+
 	for _, db := range o.databases {
 		db.cacheMutex.Lock()
 		defer db.cacheMutex.Unlock()
@@ -1127,6 +2133,7 @@ func (o *ovsdbClient) handleDisconnectNotification() {
 		// need to defer updates if/when we reconnect and clear any stale updates
 		db.deferUpdates = true
 		db.deferredUpdates = make([]*bufferedUpdate, 0)
+		db.cancelledMonitors = make(map[string]struct{})
 
 		db.modelMutex.Lock()
 		defer db.modelMutex.Unlock()
@@ -1137,6 +2144,7 @@ func (o *ovsdbClient) handleDisconnectNotification() {
 		db.monitors = make(map[string]*Monitor)
 	}
 	o.metrics.numMonitors.Set(0)
+	o.loseLocks(true)
 
 	o.shutdownMutex.Lock()
 	defer o.shutdownMutex.Unlock()
@@ -1199,7 +2207,7 @@ func isCacheConsistent(db *database) bool {
 
 // best effort to ensure cache is in a good state for reading. RLocks the
 // database's cache before returning; caller must always unlock.
-func waitForCacheConsistent(ctx context.Context, db *database, logger *logr.Logger, dbName string) {
+func waitForCacheConsistent(ctx context.Context, db *database, clock Clock, logger *logr.Logger, dbName string) {
 	if !hasMonitors(db) {
 		db.cacheMutex.RLock()
 		return
@@ -1211,7 +2219,7 @@ func waitForCacheConsistent(ctx context.Context, db *database, logger *logr.Logg
 	}
 	db.cacheMutex.RUnlock()
 
-	ticker := time.NewTicker(50 * time.Millisecond)
+	ticker := clock.NewTicker(50 * time.Millisecond)
 	defer ticker.Stop()
 	for {
 		select {
@@ -1220,7 +2228,7 @@ func waitForCacheConsistent(ctx context.Context, db *database, logger *logr.Logg
 				"database", dbName)
 			db.cacheMutex.RLock()
 			return
-		case <-ticker.C:
+		case <-ticker.C():
 			db.cacheMutex.RLock()
 			if isCacheConsistent(db) {
 				return
@@ -1243,20 +2251,33 @@ func hasMonitors(db *database) bool {
 //Get implements the API interface's Get function
 func (o *ovsdbClient) Get(ctx context.Context, model model.Model) error {
 	primaryDB := o.primaryDB()
-	waitForCacheConsistent(ctx, primaryDB, o.logger, o.primaryDBName)
+	waitForCacheConsistent(ctx, primaryDB, o.options.clock, o.logger, o.primaryDBName)
 	defer primaryDB.cacheMutex.RUnlock()
 	return primaryDB.api.Get(ctx, model)
 }
 
+//GetSelectResults implements the API interface's GetSelectResults function
+func (o *ovsdbClient) GetSelectResults(result ovsdb.OperationResult, models interface{}) error {
+	return o.primaryDB().api.GetSelectResults(result, models)
+}
+
 //Create implements the API interface's Create function
 func (o *ovsdbClient) Create(models ...model.Model) ([]ovsdb.Operation, error) {
 	return o.primaryDB().api.Create(models...)
 }
 
+//PreviewTransaction implements the API interface's PreviewTransaction function
+func (o *ovsdbClient) PreviewTransaction(ctx context.Context, operations ...ovsdb.Operation) ([]RowDelta, error) {
+	primaryDB := o.primaryDB()
+	waitForCacheConsistent(ctx, primaryDB, o.options.clock, o.logger, o.primaryDBName)
+	defer primaryDB.cacheMutex.RUnlock()
+	return primaryDB.api.PreviewTransaction(ctx, operations...)
+}
+
 //List implements the API interface's List function
 func (o *ovsdbClient) List(ctx context.Context, result interface{}) error {
 	primaryDB := o.primaryDB()
-	waitForCacheConsistent(ctx, primaryDB, o.logger, o.primaryDBName)
+	waitForCacheConsistent(ctx, primaryDB, o.options.clock, o.logger, o.primaryDBName)
 	defer primaryDB.cacheMutex.RUnlock()
 	return primaryDB.api.List(ctx, result)
 }
@@ -3,11 +3,16 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math/rand"
+	"net"
 	"os"
 	"reflect"
+	"runtime"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -849,6 +854,63 @@ func TestOperationWhenNotConnected(t *testing.T) {
 	}
 }
 
+func TestTransactEmpty(t *testing.T) {
+	ovs, err := newOVSDBClient(defDB)
+	require.NoError(t, err)
+
+	// the client is not connected, so any RPC would fail with ErrNotConnected
+	reply, err := ovs.Transact(context.TODO())
+	require.NoError(t, err)
+	assert.NotNil(t, reply)
+	assert.Empty(t, reply)
+}
+
+func TestTransactDatabaseName(t *testing.T) {
+	ovs, err := newOVSDBClient(defDB)
+	require.NoError(t, err)
+	operation := ovsdb.Operation{Op: ovsdb.OperationSelect, Table: "Bridge", Where: []ovsdb.Condition{}}
+
+	_, err = ovs.transact(context.TODO(), "", operation)
+	assert.EqualError(t, err, "cannot transact: empty database name")
+	_, err = ovs.transact(context.TODO(), "OVN_Northbound", operation)
+	assert.EqualError(t, err, "cannot transact to database OVN_Northbound: not a database of the client")
+}
+
+func TestTransactReadOnly(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)), WithReadOnly())
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	insert := ovsdb.Operation{Op: ovsdb.OperationInsert, Table: "Bridge", Row: ovsdb.Row{"name": "br0"}}
+	selectAll := ovsdb.Operation{Op: ovsdb.OperationSelect, Table: "Bridge", Where: []ovsdb.Condition{}}
+
+	// a write, alone or along reads, is rejected before being sent
+	for _, operation := range [][]ovsdb.Operation{{insert}, {selectAll, insert}} {
+		_, err = ovs.Transact(ctx, operation...)
+		var readOnly *ReadOnlyError
+		require.Truef(t, errors.As(err, &readOnly), "unexpected error %v", err)
+		assert.Equal(t, &ReadOnlyError{Op: ovsdb.OperationInsert, Table: "Bridge"}, readOnly)
+	}
+
+	// reads are sent
+	results, err := ovs.Transact(ctx, selectAll)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Empty(t, results[0].Error)
+	assert.Empty(t, results[0].Rows)
+}
+
 func TestSetOption(t *testing.T) {
 	o, err := newOVSDBClient(defDB)
 	require.NoError(t, err)
@@ -1013,3 +1075,2052 @@ func TestClientReconnectLeaderOnly(t *testing.T) {
 		return atomic.LoadInt32(&connected2) > 2
 	}, 2*time.Second, 10*time.Millisecond)
 }
+
+// followerProxy forwards the connections to a server, until reject is set:
+// it then answers the transactions to a database as a follower of a cluster
+// that lost the leadership, rejecting them
+type followerProxy struct {
+	database string
+	reject   int32
+	rejected int32
+}
+
+func (p *followerProxy) serve(t *testing.T, listener net.Listener, server string) {
+	for {
+		clientConn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		serverConn, err := net.Dial("unix", server)
+		if err != nil {
+			t.Error(err)
+			clientConn.Close()
+			return
+		}
+		go p.forward(clientConn, serverConn)
+	}
+}
+
+func (p *followerProxy) forward(clientConn, serverConn net.Conn) {
+	var mutex sync.Mutex
+	write := func(msg []byte) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		_, err := clientConn.Write(msg)
+		return err
+	}
+	go func() {
+		defer clientConn.Close()
+		decoder := json.NewDecoder(serverConn)
+		for {
+			var msg json.RawMessage
+			if err := decoder.Decode(&msg); err != nil {
+				return
+			}
+			if err := write(msg); err != nil {
+				return
+			}
+		}
+	}()
+	defer serverConn.Close()
+	decoder := json.NewDecoder(clientConn)
+	for {
+		var msg json.RawMessage
+		if err := decoder.Decode(&msg); err != nil {
+			return
+		}
+		var request struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+			ID     json.RawMessage   `json:"id"`
+		}
+		if err := json.Unmarshal(msg, &request); err == nil && request.Method == "transact" &&
+			len(request.Params) > 0 && string(request.Params[0]) == fmt.Sprintf("%q", p.database) &&
+			atomic.LoadInt32(&p.reject) == 1 {
+			atomic.AddInt32(&p.rejected, 1)
+			reply := fmt.Sprintf(`{"id":%s,"result":[{"error":%q}],"error":null}`, request.ID, notLeader)
+			if err := write([]byte(reply)); err != nil {
+				return
+			}
+			continue
+		}
+		if _, err := serverConn.Write(msg); err != nil {
+			return
+		}
+	}
+}
+
+func TestClientLeaderRetry(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	var connected1, connected2 int32
+	_, _, endpoint1 := newClientServerPair(t, &connected1, true)
+	cli2, row2, endpoint2 := newClientServerPair(t, &connected2, false)
+
+	proxySock := fmt.Sprintf("/tmp/ovsdb-proxy-%d.sock", rand.Intn(10000))
+	listener, err := net.Listen("unix", proxySock)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		listener.Close()
+		os.Remove(proxySock)
+	})
+	proxy := &followerProxy{database: defDB.Name()}
+	go proxy.serve(t, listener, strings.TrimPrefix(endpoint1, "unix:"))
+
+	newClient := func(opts ...Option) *ovsdbClient {
+		ovs, err := newOVSDBClient(defDB, append([]Option{
+			WithLeaderOnly(true),
+			WithReconnect(5*time.Second, &backoff.ZeroBackOff{}),
+			WithEndpoint("unix:" + proxySock),
+			WithEndpoint(endpoint2),
+		}, opts...)...)
+		require.NoError(t, err)
+		err = ovs.Connect(context.Background())
+		require.NoError(t, err)
+		t.Cleanup(ovs.Close)
+		return ovs
+	}
+	insert := func(ovs *ovsdbClient, name string) ([]ovsdb.OperationResult, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return ovs.Transact(ctx, ovsdb.Operation{
+			Op:    ovsdb.OperationInsert,
+			Table: "Bridge",
+			Row:   ovsdb.Row{"name": name},
+		})
+	}
+
+	retrying := newClient(WithLeaderRetry())
+	notRetrying := newClient()
+	// the second server only has the connection of cli2
+	require.Equal(t, int32(1), atomic.LoadInt32(&connected2))
+
+	// the leadership moves to the second server, but the first one has not
+	// reported it yet and rejects the writes
+	atomic.StoreInt32(&proxy.reject, 1)
+	setLeader(t, cli2, row2, true)
+
+	results, err := insert(notRetrying, "br0")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, notLeader, results[0].Error)
+
+	results, err = insert(retrying, "br1")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Empty(t, results[0].Error)
+	assert.NotEmpty(t, results[0].UUID.GoUUID)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&proxy.rejected))
+
+	// the retry was sent to the leader
+	assert.Equal(t, int32(2), atomic.LoadInt32(&connected2))
+	retrying.rpcMutex.RLock()
+	assert.Equal(t, endpoint2, retrying.endpoints[0].address)
+	retrying.rpcMutex.RUnlock()
+}
+
+func setSchemaVersion(t *testing.T, cli Client, row *serverdb.Database, version string) {
+	schema := fmt.Sprintf(`{"name": %q, "version": %q, "tables": {}}`, row.Name, version)
+	row.Schema = &schema
+	ops, err := cli.Where(row).Update(row, &row.Schema)
+	require.Nil(t, err)
+	reply, err := cli.Transact(context.Background(), ops...)
+	require.Nil(t, err)
+	opErr, err := ovsdb.CheckOperationResults(reply, ops)
+	assert.NoErrorf(t, err, "%+v", opErr)
+}
+
+func TestClientSchemaChangeHandler(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	var connected int32
+	cli, row, endpoint := newClientServerPair(t, &connected, true)
+	setSchemaVersion(t, cli, row, "1.0.0")
+
+	type change struct {
+		database, oldVersion, newVersion string
+	}
+	changes := make(chan change, 1)
+	ovs, err := newOVSDBClient(defDB,
+		WithEndpoint(endpoint),
+		WithSchemaChangeHandler(func(database, oldVersion, newVersion string) {
+			changes <- change{database, oldVersion, newVersion}
+		}))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	// the initial schema version is not a change
+	select {
+	case c := <-changes:
+		t.Fatalf("unexpected schema change %+v", c)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	setSchemaVersion(t, cli, row, "1.1.0")
+	select {
+	case c := <-changes:
+		assert.Equal(t, change{defDB.Name(), "1.0.0", "1.1.0"}, c)
+	case <-time.After(2 * time.Second):
+		t.Fatal("schema change handler was not called")
+	}
+
+	// updates of other columns do not change the version
+	setLeader(t, cli, row, false)
+	select {
+	case c := <-changes:
+		t.Fatalf("unexpected schema change %+v", c)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	_, err = newOVSDBClient(defDB, WithSchemaChangeHandler(nil))
+	assert.Error(t, err)
+}
+
+func TestClientCurrentSchema(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	var connected int32
+	cli, row, endpoint := newClientServerPair(t, &connected, true)
+	setSchemaVersion(t, cli, row, "1.0.0")
+
+	changed := make(chan struct{}, 1)
+	ovs, err := newOVSDBClient(defDB,
+		WithEndpoint(endpoint),
+		WithSchemaChangeHandler(func(_, _, _ string) {
+			changed <- struct{}{}
+		}))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	// the current schema is the negotiated one
+	negotiated := ovs.CurrentSchema()
+	assert.Equal(t, ovs.Schema(), negotiated)
+	assert.Equal(t, defDB.Name(), negotiated.Name)
+	assert.NotEmpty(t, negotiated.Tables)
+
+	// simulate a convert
+	setSchemaVersion(t, cli, row, "1.1.0")
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("schema change handler was not called")
+	}
+	current := ovs.CurrentSchema()
+	assert.Equal(t, defDB.Name(), current.Name)
+	assert.Equal(t, "1.1.0", current.Version)
+	// the model keeps the negotiated schema
+	assert.Equal(t, negotiated, ovs.Schema())
+}
+
+func TestClientReconnectLimit(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+
+	ovs, err := newOVSDBClient(defDB,
+		WithReconnect(time.Second, &backoff.ZeroBackOff{}),
+		WithReconnectLimit(3, 0),
+		WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	// removing the socket makes any reconnection attempt fail
+	err = os.Remove(sock)
+	require.NoError(t, err)
+	ovs.Disconnect()
+
+	select {
+	case <-ovs.DisconnectNotify():
+	case <-time.After(5 * time.Second):
+		t.Fatal("client did not give up reconnecting")
+	}
+	assert.False(t, ovs.Connected())
+
+	comment := "this is only a test"
+	_, err = ovs.Transact(context.Background(), ovsdb.Operation{Op: ovsdb.OperationComment, Comment: &comment})
+	assert.ErrorIs(t, err, ErrReconnectFailed)
+}
+
+// newNBServer starts a server with the northbound test schema and returns the
+// client database model to connect to it and the server socket
+func newNBServer(t *testing.T) (model.ClientDBModel, string) {
+	var nbSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal(apiTestSchema, &nbSchema)
+	require.NoError(t, err)
+	nbDB, err := model.NewClientDBModel("OVN_Northbound", map[string]model.Model{
+		"Logical_Switch":      &testLogicalSwitch{},
+		"Logical_Switch_Port": &testLogicalSwitchPort{},
+	})
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, nbDB, nbSchema)
+	return nbDB, sock
+}
+
+func TestClientEndpointPriority(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	_, lowSock := newNBServer(t)
+	nbDB, highSock := newNBServer(t)
+	low := fmt.Sprintf("unix:%s", lowSock)
+	high := fmt.Sprintf("unix:%s", highSock)
+
+	ovs, err := newOVSDBClient(nbDB,
+		WithReconnect(time.Second, &backoff.ZeroBackOff{}),
+		WithEndpoint(low),
+		WithEndpointPriority(high, 10))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+	assert.Equal(t, high, ovs.CurrentEndpoint())
+
+	// moving the socket away makes the preferred endpoint unreachable
+	hidden := highSock + ".hidden"
+	t.Cleanup(func() {
+		os.Remove(hidden)
+	})
+	err = os.Rename(highSock, hidden)
+	require.NoError(t, err)
+	ovs.Disconnect()
+	require.Eventually(t, func() bool {
+		return ovs.CurrentEndpoint() == low
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the preferred endpoint is used again on the next reconnection
+	err = os.Rename(hidden, highSock)
+	require.NoError(t, err)
+	ovs.Disconnect()
+	require.Eventually(t, func() bool {
+		return ovs.CurrentEndpoint() == high
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestClientGetSelectResults(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newNBServer(t)
+
+	ovs, err := newOVSDBClient(nbDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	ops, err := ovs.Create(
+		&testLogicalSwitch{Name: "ls0", ExternalIds: map[string]string{"foo": "bar"}},
+		&testLogicalSwitch{Name: "ls1", Ports: []string{}},
+	)
+	require.NoError(t, err)
+	results, err := ovs.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+	_, err = ovsdb.CheckOperationResults(results, ops)
+	require.NoError(t, err)
+
+	results, err = ovs.Transact(context.Background(), ovsdb.Operation{
+		Op:    ovsdb.OperationSelect,
+		Table: "Logical_Switch",
+		Where: []ovsdb.Condition{},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	var lsList []*testLogicalSwitch
+	err = ovs.GetSelectResults(results[0], &lsList)
+	require.NoError(t, err)
+	require.Len(t, lsList, 2)
+	names := map[string]*testLogicalSwitch{}
+	for _, ls := range lsList {
+		assert.NotEmpty(t, ls.UUID)
+		names[ls.Name] = ls
+	}
+	require.Contains(t, names, "ls0")
+	require.Contains(t, names, "ls1")
+	assert.Equal(t, map[string]string{"foo": "bar"}, names["ls0"].ExternalIds)
+
+	var lsValues []testLogicalSwitch
+	err = ovs.GetSelectResults(results[0], &lsValues)
+	require.NoError(t, err)
+	assert.Len(t, lsValues, 2)
+
+	err = ovs.GetSelectResults(results[0], lsList)
+	assert.Error(t, err)
+	var ports []*testLogicalSwitchPort
+	err = ovs.GetSelectResults(ovsdb.OperationResult{Error: "not found"}, &ports)
+	assert.Error(t, err)
+}
+
+// newWriteQueueServer returns a server of the northbound test database holding
+// the logical switch that records the markers of the transactions replayed by
+// a client created with writeQueueOption
+func newWriteQueueServer(t *testing.T) (model.ClientDBModel, string) {
+	nbDB, sock := newNBServer(t)
+	cli, err := newOVSDBClient(nbDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = cli.Connect(context.Background())
+	require.NoError(t, err)
+	defer cli.Close()
+	ops, err := cli.Create(&testLogicalSwitch{Name: "markers"})
+	require.NoError(t, err)
+	results, err := cli.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+	_, err = ovsdb.CheckOperationResults(results, ops)
+	require.NoError(t, err)
+	return nbDB, sock
+}
+
+var writeQueueOption = WithWriteQueue("Logical_Switch", "external_ids")
+
+// listLogicalSwitches returns the logical switches of the server, by name
+func listLogicalSwitches(t *testing.T, ovs *ovsdbClient) map[string]*testLogicalSwitch {
+	t.Helper()
+	results, err := ovs.Transact(context.Background(), ovsdb.Operation{
+		Op:    ovsdb.OperationSelect,
+		Table: "Logical_Switch",
+		Where: []ovsdb.Condition{},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	var lsList []*testLogicalSwitch
+	err = ovs.GetSelectResults(results[0], &lsList)
+	require.NoError(t, err)
+	switches := map[string]*testLogicalSwitch{}
+	for _, ls := range lsList {
+		require.NotContains(t, switches, ls.Name)
+		switches[ls.Name] = ls
+	}
+	return switches
+}
+
+func TestClientWriteQueue(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newWriteQueueServer(t)
+
+	ovs, err := newOVSDBClient(nbDB,
+		WithReconnect(time.Second, &backoff.ZeroBackOff{}),
+		writeQueueOption,
+		WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+	_, err = ovs.MonitorAll(context.Background())
+	require.NoError(t, err)
+
+	ops, err := ovs.Create(&testLogicalSwitch{Name: "ls0"})
+	require.NoError(t, err)
+	ops2, err := ovs.Create(&testLogicalSwitch{Name: "ls1"})
+	require.NoError(t, err)
+
+	// moving the socket away makes reconnection attempts fail until it is
+	// moved back, while the server keeps its data
+	hidden := sock + ".hidden"
+	t.Cleanup(func() {
+		os.Remove(hidden)
+	})
+	err = os.Rename(sock, hidden)
+	require.NoError(t, err)
+	ovs.Disconnect()
+
+	type transactResult struct {
+		results []ovsdb.OperationResult
+		err     error
+	}
+	resultCh := make(chan transactResult, 1)
+	go func() {
+		results, err := ovs.Transact(context.Background(), ops...)
+		resultCh <- transactResult{results, err}
+	}()
+	require.Eventually(t, func() bool {
+		return ovs.writeQueue.len() == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// a transaction whose context expires is dequeued and never sent
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = ovs.Transact(ctx, ops2...)
+	assert.ErrorIs(t, err, ErrTransactionQueued)
+	assert.Equal(t, 1, ovs.writeQueue.len())
+
+	err = os.Rename(hidden, sock)
+	require.NoError(t, err)
+
+	var res transactResult
+	select {
+	case res = <-resultCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("queued transaction was not replayed")
+	}
+	require.NoError(t, res.err)
+	require.Len(t, res.results, len(ops))
+	_, err = ovsdb.CheckOperationResults(res.results, ops)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return ovs.writeQueue.len() == 0
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// transactions go straight to the server once the queue is drained. The
+	// queued transaction has been applied once, and the canceled one never
+	switches := listLogicalSwitches(t, ovs)
+	assert.Len(t, switches, 2)
+	assert.Contains(t, switches, "ls0")
+	// and the marker of the replayed transaction has been removed
+	for _, ls := range switches {
+		assert.Empty(t, ls.ExternalIds, ls.Name)
+	}
+
+	require.Eventually(t, func() bool {
+		var cached []testLogicalSwitch
+		err := ovs.List(context.Background(), &cached)
+		return err == nil && len(cached) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestClientWriteQueueReplayedOnce(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newWriteQueueServer(t)
+
+	ovs, err := newOVSDBClient(nbDB,
+		WithReconnect(time.Second, &backoff.ZeroBackOff{}),
+		writeQueueOption,
+		WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	hidden := sock + ".hidden"
+	t.Cleanup(func() {
+		os.Remove(hidden)
+	})
+	err = os.Rename(sock, hidden)
+	require.NoError(t, err)
+	ovs.Disconnect()
+
+	ops, err := ovs.Create(&testLogicalSwitch{Name: "ls0"})
+	require.NoError(t, err)
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := ovs.Transact(context.Background(), ops...)
+		errCh <- err
+	}()
+	require.Eventually(t, func() bool {
+		return ovs.writeQueue.len() == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// a first replay commits the transaction, but its reply is lost
+	ovs.writeQueue.mutex.Lock()
+	txn := ovs.writeQueue.transactions[0]
+	ovs.writeQueue.mutex.Unlock()
+	guards, record, err := ovs.markerOperations(txn)
+	require.NoError(t, err)
+	other, err := newOVSDBClient(nbDB, WithEndpoint(fmt.Sprintf("unix:%s", hidden)))
+	require.NoError(t, err)
+	err = other.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(other.Close)
+	replay := append(append(guards, txn.operations...), record)
+	results, err := other.Transact(context.Background(), replay...)
+	require.NoError(t, err)
+	_, err = ovsdb.CheckOperationResults(results, replay)
+	require.NoError(t, err)
+
+	// so the transaction is replayed again on reconnect, which applies nothing
+	err = os.Rename(hidden, sock)
+	require.NoError(t, err)
+	select {
+	case err = <-errCh:
+		assert.ErrorIs(t, err, ErrTransactionReplayed)
+	case <-time.After(5 * time.Second):
+		t.Fatal("queued transaction was not replayed")
+	}
+	assert.Equal(t, 0, ovs.writeQueue.len())
+
+	switches := listLogicalSwitches(t, ovs)
+	assert.Len(t, switches, 2)
+	assert.Contains(t, switches, "ls0")
+	for _, ls := range switches {
+		assert.Empty(t, ls.ExternalIds, ls.Name)
+	}
+}
+
+func TestClientWriteQueueNotSentWhileDisconnected(t *testing.T) {
+	ovs, err := newOVSDBClient(defDB, WithReconnect(time.Second, &backoff.ZeroBackOff{}), writeQueueOption)
+	require.NoError(t, err)
+
+	op := ovsdb.Operation{
+		Op:    ovsdb.OperationInsert,
+		Table: "Logical_Switch",
+		Row:   ovsdb.Row{"name": "ls0"},
+	}
+	txn := ovs.enqueueTransaction([]ovsdb.Operation{op})
+	require.NotNil(t, txn)
+	// a replay finding the client disconnected does not send the transaction,
+	// which is then dequeued by its caller rather than being left queued with
+	// an unknown outcome
+	ovs.replayWriteQueue()
+	ovs.writeQueue.mutex.Lock()
+	assert.False(t, txn.sent)
+	ovs.writeQueue.mutex.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ovs.waitQueuedTransaction(ctx, txn)
+	assert.ErrorIs(t, err, ErrTransactionQueued)
+	assert.Equal(t, 0, ovs.writeQueue.len())
+}
+
+func TestClientWriteQueueCloseDuringReplay(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newWriteQueueServer(t)
+
+	ovs, err := newOVSDBClient(nbDB,
+		WithReconnect(time.Second, &backoff.ZeroBackOff{}),
+		writeQueueOption,
+		WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+
+	const count = 50
+	errCh := make(chan error, count)
+	for i := 0; i < count; i++ {
+		op := ovsdb.Operation{
+			Op:    ovsdb.OperationInsert,
+			Table: "Logical_Switch",
+			Row:   ovsdb.Row{"name": fmt.Sprintf("ls%d", i)},
+		}
+		go func() {
+			_, err := ovs.Transact(context.Background(), op)
+			errCh <- err
+		}()
+	}
+	require.Eventually(t, func() bool {
+		return ovs.writeQueue.len() == count
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// closing the client while the queue is being replayed fails the
+	// remaining transactions, including the one being sent, exactly once
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return ovs.writeQueue.len() < count
+	}, 2*time.Second, time.Millisecond)
+	ovs.Close()
+
+	for i := 0; i < count; i++ {
+		select {
+		case <-errCh:
+		case <-time.After(5 * time.Second):
+			t.Fatal("queued transaction did not complete")
+		}
+	}
+	assert.Equal(t, 0, ovs.writeQueue.len())
+}
+
+func TestClientWriteQueueBeforeConnect(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newWriteQueueServer(t)
+
+	ovs, err := newOVSDBClient(nbDB,
+		WithReconnect(time.Second, &backoff.ZeroBackOff{}),
+		writeQueueOption,
+		WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	// the API is not available before the first connect
+	op := ovsdb.Operation{
+		Op:    ovsdb.OperationInsert,
+		Table: "Logical_Switch",
+		Row:   ovsdb.Row{"name": "ls0"},
+	}
+	resultCh := make(chan error, 1)
+	go func() {
+		_, err := ovs.Transact(context.Background(), op)
+		resultCh <- err
+	}()
+	require.Eventually(t, func() bool {
+		return ovs.writeQueue.len() == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// the transaction queued before the first connect is replayed by it
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	select {
+	case err = <-resultCh:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("queued transaction was not replayed")
+	}
+
+	// and the later transactions are not queued behind it
+	ops, err := ovs.Create(&testLogicalSwitch{Name: "ls1"})
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	results, err := ovs.Transact(ctx, ops...)
+	require.NoError(t, err)
+	_, err = ovsdb.CheckOperationResults(results, ops)
+	require.NoError(t, err)
+	assert.Equal(t, 0, ovs.writeQueue.len())
+}
+
+func TestClientWriteQueueFailDuringReplay(t *testing.T) {
+	ovs, err := newOVSDBClient(defDB, WithReconnect(time.Second, &backoff.ZeroBackOff{}), writeQueueOption)
+	require.NoError(t, err)
+
+	op := ovsdb.Operation{
+		Op:    ovsdb.OperationInsert,
+		Table: "Logical_Switch",
+		Row:   ovsdb.Row{"name": "ls0"},
+	}
+	txn := ovs.enqueueTransaction([]ovsdb.Operation{op})
+	require.NotNil(t, txn)
+	// the replay has started sending the transaction when the client
+	// disconnects, and completes it once the reply has failed
+	ovs.writeQueue.mutex.Lock()
+	txn.sent = true
+	ovs.writeQueue.mutex.Unlock()
+	ovs.failWriteQueue(ErrNotConnected)
+	assert.NotPanics(t, func() {
+		ovs.completeQueuedTransaction(txn, nil, errors.New("connection closed"))
+	})
+
+	_, err = ovs.waitQueuedTransaction(context.Background(), txn)
+	assert.ErrorIs(t, err, ErrNotConnected)
+	assert.Equal(t, 0, ovs.writeQueue.len())
+}
+
+func TestClientHealth(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+
+	ovs, err := newOVSDBClient(defDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+
+	health := ovs.Health()
+	assert.False(t, health.Connected)
+	assert.False(t, health.Ready(0))
+
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	// connected, but the cache has not been synced by any monitor yet
+	health = ovs.Health()
+	assert.True(t, health.Connected)
+	assert.Empty(t, health.Monitors)
+	assert.False(t, health.Synced())
+	assert.False(t, health.Ready(0))
+
+	cookie, err := ovs.MonitorAll(context.Background())
+	require.NoError(t, err)
+	health = ovs.Health()
+	assert.Equal(t, map[MonitorCookie]bool{cookie: true}, health.Monitors)
+	assert.True(t, health.Synced())
+	assert.True(t, health.Ready(0))
+
+	// no echo has been exchanged yet
+	assert.True(t, health.LastEcho.IsZero())
+	assert.False(t, health.Ready(time.Minute))
+	err = ovs.Echo(context.Background())
+	require.NoError(t, err)
+	health = ovs.Health()
+	assert.False(t, health.LastEcho.IsZero())
+	assert.True(t, health.Ready(time.Minute))
+
+	ovs.Disconnect()
+	require.Eventually(t, func() bool {
+		return !ovs.Health().Connected
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.False(t, ovs.Health().Ready(0))
+}
+
+func TestClientTableSynced(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newNBServer(t)
+	ovs, err := newOVSDBClient(nbDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	// a single switch with many ports
+	ls := &testLogicalSwitch{Name: "ls0"}
+	models := []model.Model{}
+	for i := 0; i < 100; i++ {
+		uuid := fmt.Sprintf("lsp%d", i)
+		ls.Ports = append(ls.Ports, uuid)
+		models = append(models, &testLogicalSwitchPort{UUID: uuid, Name: uuid})
+	}
+	ops, err := ovs.Create(append(models, ls)...)
+	require.NoError(t, err)
+	results, err := ovs.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+	_, err = ovsdb.CheckOperationResults(results, ops)
+	require.NoError(t, err)
+
+	isClosed := func(ch <-chan struct{}) bool {
+		select {
+		case <-ch:
+			return true
+		default:
+			return false
+		}
+	}
+	lsSynced := ovs.TableSynced("Logical_Switch")
+	lspSynced := ovs.TableSynced("Logical_Switch_Port")
+	assert.False(t, isClosed(lsSynced))
+	assert.False(t, isClosed(lspSynced))
+
+	_, err = ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&testLogicalSwitch{})))
+	require.NoError(t, err)
+	assert.True(t, isClosed(lsSynced))
+	assert.False(t, isClosed(lspSynced))
+	// the cache holds the switch while the ports are not monitored yet
+	var switches []testLogicalSwitch
+	err = ovs.List(context.Background(), &switches)
+	require.NoError(t, err)
+	require.Len(t, switches, 1)
+	assert.Len(t, switches[0].Ports, 100)
+
+	_, err = ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&testLogicalSwitchPort{})))
+	require.NoError(t, err)
+	assert.True(t, isClosed(lspSynced))
+	var ports []testLogicalSwitchPort
+	err = ovs.List(context.Background(), &ports)
+	require.NoError(t, err)
+	assert.Len(t, ports, 100)
+
+	// tables synced before the call return a closed channel
+	assert.True(t, isClosed(ovs.TableSynced("Logical_Switch")))
+}
+
+// assertGoroutinesReleased waits for the number of goroutines to be back to at
+// most n. It polls from the test goroutine, as require.Eventually runs its
+// condition in a goroutine of its own, which would be counted
+func assertGoroutinesReleased(t *testing.T, n int) {
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > n && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), n)
+}
+
+func TestClientMonitorCancel(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newNBServer(t)
+	goroutines := runtime.NumGoroutine()
+	ovs, err := newOVSDBClient(nbDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+
+	cookie, err := ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&testLogicalSwitch{})))
+	require.NoError(t, err)
+	_, err = ovs.Insert(context.Background(), &testLogicalSwitch{Name: "ls0"})
+	require.NoError(t, err)
+	var switches []testLogicalSwitch
+	require.Eventually(t, func() bool {
+		err := ovs.List(context.Background(), &switches)
+		return err == nil && len(switches) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	err = ovs.MonitorCancel(context.Background(), cookie)
+	require.NoError(t, err)
+	assert.Empty(t, ovs.Health().Monitors)
+
+	// the updates of the cancelled monitor are no longer applied
+	_, err = ovs.Insert(context.Background(), &testLogicalSwitch{Name: "ls1"})
+	require.NoError(t, err)
+	require.Never(t, func() bool {
+		err := ovs.List(context.Background(), &switches)
+		return err != nil || len(switches) != 1
+	}, 200*time.Millisecond, 10*time.Millisecond)
+
+	// nor are the updates that the server sent before the cancellation
+	var reply []interface{}
+	params := []json.RawMessage{
+		[]byte(fmt.Sprintf(`{"databaseName":%q,"id":%q}`, cookie.DatabaseName, cookie.ID)),
+		[]byte(`"` + aUUID0 + `"`),
+		[]byte(`{"Logical_Switch":{"` + aUUID1 + `":{"insert":{"name":"late"}}}}`),
+	}
+	err = ovs.update3(params, &reply)
+	require.NoError(t, err)
+	err = ovs.List(context.Background(), &switches)
+	require.NoError(t, err)
+	assert.Len(t, switches, 1)
+
+	// the server no longer knows the monitor
+	err = ovs.MonitorCancel(context.Background(), cookie)
+	assert.Error(t, err)
+
+	ovs.Close()
+	assertGoroutinesReleased(t, goroutines)
+}
+
+// metadataHandler records the metadata of the add events of the cache
+type metadataHandler struct {
+	cache.EventHandlerFuncs
+	mutex    sync.Mutex
+	metadata map[string]cache.EventMetadata
+}
+
+func (h *metadataHandler) OnAddWithMetadata(table string, m model.Model, metadata cache.EventMetadata) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.metadata[m.(*testLogicalSwitch).Name] = metadata
+}
+
+func (h *metadataHandler) OnUpdateWithMetadata(string, model.Model, model.Model, cache.EventMetadata) {
+}
+
+func (h *metadataHandler) OnDeleteWithMetadata(string, model.Model, cache.EventMetadata) {}
+
+func (h *metadataHandler) get(name string) (cache.EventMetadata, bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	metadata, ok := h.metadata[name]
+	return metadata, ok
+}
+
+func TestClientEventMetadata(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newNBServer(t)
+	ovs, err := newOVSDBClient(nbDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+	handler := &metadataHandler{metadata: map[string]cache.EventMetadata{}}
+	ovs.Cache().AddEventHandler(handler)
+
+	// a row of the initial dump, with the transaction id of the reply to
+	// monitor_cond_since
+	_, err = ovs.Insert(context.Background(), &testLogicalSwitch{Name: "initial"})
+	require.NoError(t, err)
+	cookie, err := ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&testLogicalSwitch{})))
+	require.NoError(t, err)
+	var metadata cache.EventMetadata
+	require.Eventually(t, func() bool {
+		var ok bool
+		metadata, ok = handler.get("initial")
+		return ok
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, cache.EventMetadata{
+		Database:      "OVN_Northbound",
+		TransactionID: "00000000-0000-0000-000000000000",
+		Initial:       true,
+	}, metadata)
+
+	// a row of an update2 notification
+	_, err = ovs.Insert(context.Background(), &testLogicalSwitch{Name: "update2"})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		var ok bool
+		metadata, ok = handler.get("update2")
+		return ok
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, cache.EventMetadata{Database: "OVN_Northbound"}, metadata)
+
+	// a row of an update3 notification, with its transaction id
+	var reply []interface{}
+	params := []json.RawMessage{
+		[]byte(fmt.Sprintf(`{"databaseName":%q,"id":%q}`, cookie.DatabaseName, cookie.ID)),
+		[]byte(`"` + aUUID0 + `"`),
+		[]byte(`{"Logical_Switch":{"` + aUUID1 + `":{"insert":{"name":"update3"}}}}`),
+	}
+	err = ovs.update3(params, &reply)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		var ok bool
+		metadata, ok = handler.get("update3")
+		return ok
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, cache.EventMetadata{Database: "OVN_Northbound", TransactionID: aUUID0}, metadata)
+}
+
+func TestClientMonitorCancelReconnecting(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newNBServer(t)
+	goroutines := runtime.NumGoroutine()
+	ovs, err := newOVSDBClient(nbDB,
+		WithReconnect(time.Second, &backoff.ZeroBackOff{}),
+		WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+
+	switchCookie, err := ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&testLogicalSwitch{})))
+	require.NoError(t, err)
+	portCookie, err := ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&testLogicalSwitchPort{})))
+	require.NoError(t, err)
+
+	// moving the socket away makes reconnection attempts fail until it is
+	// moved back
+	hidden := sock + ".hidden"
+	t.Cleanup(func() {
+		os.Remove(hidden)
+	})
+	err = os.Rename(sock, hidden)
+	require.NoError(t, err)
+	ovs.Disconnect()
+	require.Eventually(t, func() bool {
+		return !ovs.Connected()
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// there is no reply to wait for
+	err = ovs.MonitorCancel(context.Background(), switchCookie)
+	require.NoError(t, err)
+
+	err = os.Rename(hidden, sock)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return ovs.Connected()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// only the other monitor is restarted
+	require.Eventually(t, func() bool {
+		return reflect.DeepEqual(map[MonitorCookie]bool{portCookie: true}, ovs.Health().Monitors)
+	}, 2*time.Second, 10*time.Millisecond)
+	_, err = ovs.Insert(context.Background(), &testLogicalSwitch{Name: "ls0"}, &testLogicalSwitchPort{Name: "lsp0"})
+	require.NoError(t, err)
+	var ports []testLogicalSwitchPort
+	require.Eventually(t, func() bool {
+		err := ovs.List(context.Background(), &ports)
+		return err == nil && len(ports) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	var switches []testLogicalSwitch
+	err = ovs.List(context.Background(), &switches)
+	require.NoError(t, err)
+	assert.Empty(t, switches)
+
+	err = ovs.MonitorCancel(context.Background(), portCookie)
+	require.NoError(t, err)
+	assert.Empty(t, ovs.Health().Monitors)
+
+	ovs.Close()
+	assertGoroutinesReleased(t, goroutines)
+}
+
+func TestClientInsert(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newNBServer(t)
+	ovs, err := newOVSDBClient(nbDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	ls0 := &testLogicalSwitch{Name: "ls0"}
+	ls1 := &testLogicalSwitch{Name: "ls1"}
+	uuids, err := ovs.Insert(context.Background(), ls0, ls1)
+	require.NoError(t, err)
+	require.Len(t, uuids, 2)
+	_, err = uuid.Parse(uuids[0])
+	assert.NoError(t, err)
+	_, err = uuid.Parse(uuids[1])
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuids[0], uuids[1])
+	assert.Equal(t, uuids[0], ls0.UUID)
+	assert.Equal(t, uuids[1], ls1.UUID)
+
+	// the rows were created with the returned uuids
+	results, err := ovs.Transact(context.Background(), ovsdb.Operation{
+		Op:    ovsdb.OperationSelect,
+		Table: "Logical_Switch",
+		Where: []ovsdb.Condition{},
+	})
+	require.NoError(t, err)
+	var lsList []*testLogicalSwitch
+	err = ovs.GetSelectResults(results[0], &lsList)
+	require.NoError(t, err)
+	byUUID := map[string]string{}
+	for _, ls := range lsList {
+		byUUID[ls.UUID] = ls.Name
+	}
+	assert.Equal(t, map[string]string{uuids[0]: "ls0", uuids[1]: "ls1"}, byUUID)
+
+	uuids, err = ovs.Insert(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, uuids)
+}
+
+func TestClientMonitorConditionAndColumns(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newNBServer(t)
+	ovs, err := newOVSDBClient(nbDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	uuids, err := ovs.Insert(context.Background(),
+		&testLogicalSwitch{Name: "ls0", ExternalIds: map[string]string{"foo": "bar"}},
+		&testLogicalSwitch{Name: "ls1", ExternalIds: map[string]string{"foo": "baz"}, Ports: []string{"port"}},
+	)
+	require.NoError(t, err)
+
+	// only watch the external_ids of ls1
+	ls := &testLogicalSwitch{}
+	monitor := ovs.NewMonitor(WithConditionalTable(ls, model.Condition{
+		Field:    &ls.Name,
+		Function: ovsdb.ConditionEqual,
+		Value:    "ls1",
+	}, &ls.ExternalIds))
+	_, err = ovs.Monitor(context.Background(), monitor)
+	require.NoError(t, err)
+
+	rows := ovs.Cache().Table("Logical_Switch").Rows()
+	assert.Equal(t, map[string]model.Model{
+		uuids[1]: &testLogicalSwitch{
+			UUID:        uuids[1],
+			ExternalIds: map[string]string{"foo": "baz"},
+		},
+	}, rows)
+
+	// the later updates are filtered by the conditions as well: ls2 never
+	// enters the cache, while ls0 does once it is renamed to match them
+	_, err = ovs.Insert(context.Background(), &testLogicalSwitch{Name: "ls2", ExternalIds: map[string]string{"foo": "qux"}})
+	require.NoError(t, err)
+	renamed := &testLogicalSwitch{UUID: uuids[0], Name: "ls1"}
+	ops, err := ovs.Where(renamed).Update(renamed, &renamed.Name)
+	require.NoError(t, err)
+	_, err = ovs.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return ovs.Cache().Table("Logical_Switch").Len() == 2
+	}, 2*time.Second, 10*time.Millisecond)
+	rows = ovs.Cache().Table("Logical_Switch").Rows()
+	assert.Equal(t, map[string]model.Model{
+		uuids[0]: &testLogicalSwitch{
+			UUID:        uuids[0],
+			ExternalIds: map[string]string{"foo": "bar"},
+		},
+		uuids[1]: &testLogicalSwitch{
+			UUID:        uuids[1],
+			ExternalIds: map[string]string{"foo": "baz"},
+		},
+	}, rows)
+}
+
+func TestClientRejectDuplicateKeys(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	raw := json.RawMessage(`[{"uuid":["uuid","2f77b348-9768-4866-b761-89d5177ecdab"],"uuid":["uuid","6e7b7e7c-6f2b-4a4f-8b5c-7c8d9e0f1a2b"]}]`)
+
+	// by default, the last value is used
+	ovs, err := newOVSDBClient(defDB)
+	require.NoError(t, err)
+	var results []ovsdb.OperationResult
+	err = ovs.decodeReply(raw, &results)
+	require.NoError(t, err)
+	assert.Equal(t, "6e7b7e7c-6f2b-4a4f-8b5c-7c8d9e0f1a2b", results[0].UUID.GoUUID)
+
+	ovs, err = newOVSDBClient(defDB, WithRejectDuplicateKeys())
+	require.NoError(t, err)
+	results = nil
+	err = ovs.decodeReply(raw, &results)
+	assert.True(t, errors.Is(err, ovsdb.ErrDuplicateKey))
+	assert.Nil(t, results)
+
+	// well-formed replies are accepted
+	nbDB, sock := newNBServer(t)
+	ovs, err = newOVSDBClient(nbDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)), WithRejectDuplicateKeys())
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+	uuids, err := ovs.Insert(context.Background(), &testLogicalSwitch{Name: "ls0"})
+	require.NoError(t, err)
+	assert.Len(t, uuids, 1)
+}
+
+func TestClientGetByUUIDs(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newNBServer(t)
+	ovs, err := newOVSDBClient(nbDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	uuids, err := ovs.Insert(context.Background(),
+		&testLogicalSwitch{Name: "ls0"},
+		&testLogicalSwitch{Name: "ls1"},
+		&testLogicalSwitch{Name: "ls2"},
+	)
+	require.NoError(t, err)
+
+	// only ls1 is in the cache, the other rows are fetched from the server
+	ls := &testLogicalSwitch{}
+	monitor := ovs.NewMonitor(WithConditionalTable(ls, model.Condition{
+		Field:    &ls.Name,
+		Function: ovsdb.ConditionEqual,
+		Value:    "ls1",
+	}))
+	_, err = ovs.Monitor(context.Background(), monitor)
+	require.NoError(t, err)
+	require.Len(t, ovs.Cache().Table("Logical_Switch").Rows(), 1)
+
+	t.Run("all present", func(t *testing.T) {
+		requested := []string{uuids[2], uuids[0], uuids[1]}
+		models, err := ovs.GetByUUIDs(context.Background(), "Logical_Switch", requested)
+		require.NoError(t, err)
+		require.Len(t, models, 3)
+		names := []string{}
+		for i, m := range models {
+			ls := m.(*testLogicalSwitch)
+			assert.Equal(t, requested[i], ls.UUID)
+			names = append(names, ls.Name)
+		}
+		assert.Equal(t, []string{"ls2", "ls0", "ls1"}, names)
+	})
+
+	t.Run("partially missing", func(t *testing.T) {
+		missing0 := uuid.NewString()
+		missing1 := uuid.NewString()
+		models, err := ovs.GetByUUIDs(context.Background(), "Logical_Switch", []string{uuids[0], missing0, uuids[1], missing1})
+		assert.True(t, errors.Is(err, ErrNotFound))
+		assert.Contains(t, err.Error(), missing0+", "+missing1)
+		assert.NotContains(t, err.Error(), uuids[0])
+		assert.Nil(t, models)
+	})
+
+	t.Run("unknown table", func(t *testing.T) {
+		_, err := ovs.GetByUUIDs(context.Background(), "Unknown", []string{uuids[0]})
+		assert.Error(t, err)
+	})
+}
+
+func TestClientMonitorSelect(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newNBServer(t)
+	newClient := func(sel *ovsdb.MonitorSelect) *ovsdbClient {
+		ovs, err := newOVSDBClient(nbDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+		require.NoError(t, err)
+		err = ovs.Connect(context.Background())
+		require.NoError(t, err)
+		t.Cleanup(ovs.Close)
+		monitor := ovs.NewMonitor()
+		monitor.Tables = []TableMonitor{{Table: "Logical_Switch", Select: sel}}
+		_, err = ovs.Monitor(context.Background(), monitor)
+		require.NoError(t, err)
+		return ovs
+	}
+	// the select is omitted from the request, the server defaults to send
+	// every update
+	defaultSelect := newClient(nil)
+	noModify := newClient(ovsdb.NewMonitorSelect(true, true, true, false))
+
+	uuids, err := defaultSelect.Insert(context.Background(), &testLogicalSwitch{Name: "ls0"})
+	require.NoError(t, err)
+	lsName := func(ovs *ovsdbClient, uuid string) string {
+		m := ovs.Cache().Table("Logical_Switch").Row(uuid)
+		if m == nil {
+			return ""
+		}
+		return m.(*testLogicalSwitch).Name
+	}
+	for _, ovs := range []*ovsdbClient{defaultSelect, noModify} {
+		ovs := ovs
+		require.Eventually(t, func() bool { return lsName(ovs, uuids[0]) == "ls0" }, time.Second, 10*time.Millisecond)
+	}
+
+	ls := &testLogicalSwitch{UUID: uuids[0], Name: "ls1"}
+	ops, err := defaultSelect.Where(ls).Update(ls, &ls.Name)
+	require.NoError(t, err)
+	results, err := defaultSelect.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+	_, err = ovsdb.CheckOperationResults(results, ops)
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return lsName(defaultSelect, uuids[0]) == "ls1" }, time.Second, 10*time.Millisecond)
+
+	// updates are sent in order: once the next insert is received, the
+	// modification would have been received too if it was selected
+	uuids2, err := defaultSelect.Insert(context.Background(), &testLogicalSwitch{Name: "ls2"})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return lsName(noModify, uuids2[0]) == "ls2" }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "ls0", lsName(noModify, uuids[0]))
+}
+
+// cidrConverter maps a string column holding a CIDR to a net.IPNet field
+type cidrConverter struct{}
+
+func (cidrConverter) FieldType() reflect.Type {
+	return reflect.TypeOf(net.IPNet{})
+}
+
+func (cidrConverter) ToNative(value interface{}) (interface{}, error) {
+	ipNet := value.(net.IPNet)
+	if ipNet.IP == nil {
+		return "", nil
+	}
+	return ipNet.String(), nil
+}
+
+func (cidrConverter) FromNative(value interface{}) (interface{}, error) {
+	if value.(string) == "" {
+		return net.IPNet{}, nil
+	}
+	_, ipNet, err := net.ParseCIDR(value.(string))
+	if err != nil {
+		return nil, err
+	}
+	return *ipNet, nil
+}
+
+type cidrLogicalSwitch struct {
+	UUID string    `ovsdb:"_uuid"`
+	Name net.IPNet `ovsdb:"name"`
+}
+
+func (*cidrLogicalSwitch) Table() string {
+	return "Logical_Switch"
+}
+
+func TestClientColumnConverter(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	_, sock := newNBServer(t)
+	endpoint := fmt.Sprintf("unix:%s", sock)
+	cidrDB, err := model.NewClientDBModel("OVN_Northbound", map[string]model.Model{
+		"Logical_Switch": &cidrLogicalSwitch{},
+	})
+	require.NoError(t, err)
+
+	t.Run("without converter", func(t *testing.T) {
+		ovs, err := newOVSDBClient(cidrDB, WithEndpoint(endpoint))
+		require.NoError(t, err)
+		err = ovs.Connect(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Wrong type")
+	})
+
+	for _, opt := range []Option{
+		WithColumnConverter("Logical_Switch", "name", cidrConverter{}),
+		WithTypeConverter(cidrConverter{}),
+	} {
+		ovs, err := newOVSDBClient(cidrDB, WithEndpoint(endpoint), opt)
+		require.NoError(t, err)
+		err = ovs.Connect(context.Background())
+		require.NoError(t, err)
+		_, err = ovs.MonitorAll(context.Background())
+		require.NoError(t, err)
+
+		_, ipNet, err := net.ParseCIDR("10.0.0.0/24")
+		require.NoError(t, err)
+		ls := &cidrLogicalSwitch{Name: *ipNet}
+		uuids, err := ovs.Insert(context.Background(), ls)
+		require.NoError(t, err)
+
+		// the server holds the column as a string
+		results, err := ovs.Transact(context.Background(), ovsdb.Operation{
+			Op:      ovsdb.OperationSelect,
+			Table:   "Logical_Switch",
+			Where:   []ovsdb.Condition{ovsdb.NewCondition("_uuid", ovsdb.ConditionEqual, ovsdb.UUID{GoUUID: uuids[0]})},
+			Columns: []string{"name"},
+		})
+		require.NoError(t, err)
+		require.Len(t, results[0].Rows, 1)
+		assert.Equal(t, "10.0.0.0/24", results[0].Rows[0]["name"])
+
+		// and the client reads it back as a net.IPNet
+		require.Eventually(t, func() bool {
+			return ovs.Cache().Table("Logical_Switch").Row(uuids[0]) != nil
+		}, time.Second, 10*time.Millisecond)
+		got := ovs.Cache().Table("Logical_Switch").Row(uuids[0]).(*cidrLogicalSwitch)
+		assert.Equal(t, ipNet.String(), got.Name.String())
+
+		fetched := &cidrLogicalSwitch{UUID: uuids[0]}
+		err = ovs.Get(context.Background(), fetched)
+		require.NoError(t, err)
+		assert.Equal(t, ipNet.String(), fetched.Name.String())
+
+		ovs.Close()
+	}
+}
+
+func TestClientTransactRows(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newNBServer(t)
+	ovs, err := newOVSDBClient(nbDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	var switches []model.Model
+	for i := 0; i < 20; i++ {
+		switches = append(switches, &testLogicalSwitch{Name: fmt.Sprintf("ls%d", i)})
+	}
+	_, err = ovs.Insert(context.Background(), switches...)
+	require.NoError(t, err)
+
+	selectAll := ovsdb.Operation{
+		Op:      ovsdb.OperationSelect,
+		Table:   "Logical_Switch",
+		Where:   []ovsdb.Condition{},
+		Columns: []string{"name"},
+	}
+	expected, err := ovs.Transact(context.Background(), selectAll)
+	require.NoError(t, err)
+	require.Len(t, expected[0].Rows, 20)
+
+	var names []string
+	results, err := ovs.TransactRows(context.Background(), func(op int, row ovsdb.Row) error {
+		assert.Equal(t, 0, op)
+		names = append(names, row["name"].(string))
+		return nil
+	}, selectAll)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Nil(t, results[0].Rows)
+	var expectedNames []string
+	for _, row := range expected[0].Rows {
+		expectedNames = append(expectedNames, row["name"].(string))
+	}
+	assert.ElementsMatch(t, expectedNames, names)
+
+	stop := errors.New("stop")
+	_, err = ovs.TransactRows(context.Background(), func(int, ovsdb.Row) error {
+		return stop
+	}, selectAll)
+	assert.True(t, errors.Is(err, stop))
+}
+
+func TestClientTransactWithLock(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newNBServer(t)
+	newClient := func() *ovsdbClient {
+		ovs, err := newOVSDBClient(nbDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+		require.NoError(t, err)
+		err = ovs.Connect(context.Background())
+		require.NoError(t, err)
+		t.Cleanup(ovs.Close)
+		return ovs
+	}
+	owner := newClient()
+	other := newClient()
+
+	locked, err := owner.Lock(context.Background(), "leader")
+	require.NoError(t, err)
+	require.True(t, locked)
+	locked, err = other.Lock(context.Background(), "leader")
+	require.NoError(t, err)
+	require.False(t, locked)
+
+	countSwitches := func() int {
+		results, err := owner.Transact(context.Background(), ovsdb.Operation{
+			Op:    ovsdb.OperationSelect,
+			Table: "Logical_Switch",
+			Where: []ovsdb.Condition{},
+		})
+		require.NoError(t, err)
+		return len(results[0].Rows)
+	}
+
+	t.Run("lock not held", func(t *testing.T) {
+		ops, err := other.Create(&testLogicalSwitch{Name: "ls-other"})
+		require.NoError(t, err)
+		_, err = other.TransactWithLock(context.Background(), "leader", ops...)
+		require.Error(t, err)
+		var lockErr *LockNotHeldError
+		require.True(t, errors.As(err, &lockErr))
+		assert.Equal(t, "leader", lockErr.LockID)
+		assert.Equal(t, 0, countSwitches())
+	})
+
+	t.Run("lock held", func(t *testing.T) {
+		ops, err := owner.Create(&testLogicalSwitch{Name: "ls-owner"})
+		require.NoError(t, err)
+		results, err := owner.TransactWithLock(context.Background(), "leader", ops...)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.NotEmpty(t, results[0].UUID.GoUUID)
+		assert.Equal(t, 1, countSwitches())
+	})
+
+	t.Run("lock released", func(t *testing.T) {
+		err := owner.Unlock(context.Background(), "leader")
+		require.NoError(t, err)
+		locked, err := other.Lock(context.Background(), "leader")
+		require.NoError(t, err)
+		assert.True(t, locked)
+		ops, err := owner.Create(&testLogicalSwitch{Name: "ls-owner2"})
+		require.NoError(t, err)
+		_, err = owner.TransactWithLock(context.Background(), "leader", ops...)
+		var lockErr *LockNotHeldError
+		assert.True(t, errors.As(err, &lockErr))
+	})
+}
+
+func TestClientLockReacquiredOnReconnect(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newNBServer(t)
+	owner, err := newOVSDBClient(nbDB,
+		WithReconnect(time.Second, &backoff.ZeroBackOff{}),
+		WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = owner.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(owner.Close)
+	other, err := newOVSDBClient(nbDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = other.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(other.Close)
+
+	locked, err := owner.Lock(context.Background(), "leader")
+	require.NoError(t, err)
+	require.True(t, locked)
+	assert.True(t, owner.lockHeld("leader"))
+
+	// moving the socket away makes reconnection attempts fail until it is
+	// moved back, while the server keeps its data
+	hidden := sock + ".hidden"
+	t.Cleanup(func() {
+		os.Remove(hidden)
+	})
+	err = os.Rename(sock, hidden)
+	require.NoError(t, err)
+	owner.Disconnect()
+
+	// the lock is stolen while the owner is disconnected, so the owner
+	// misses the stolen notification
+	err = other.Steal(context.Background(), "leader")
+	require.NoError(t, err)
+	assert.True(t, other.lockHeld("leader"))
+
+	ops, err := owner.Create(&testLogicalSwitch{Name: "ls-owner"})
+	require.NoError(t, err)
+	var lockErr *LockNotHeldError
+	_, err = owner.TransactWithLock(context.Background(), "leader", ops...)
+	assert.True(t, errors.As(err, &lockErr))
+
+	err = os.Rename(hidden, sock)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return owner.Connected()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the owner requested the lock again on reconnect, but was not granted it
+	assert.False(t, owner.lockHeld("leader"))
+	_, err = owner.TransactWithLock(context.Background(), "leader", ops...)
+	assert.True(t, errors.As(err, &lockErr))
+	results, err := owner.Transact(context.Background(), ovsdb.Operation{
+		Op:    ovsdb.OperationSelect,
+		Table: "Logical_Switch",
+		Where: []ovsdb.Condition{},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, results[0].Rows)
+
+	ops, err = other.Create(&testLogicalSwitch{Name: "ls-other"})
+	require.NoError(t, err)
+	_, err = other.TransactWithLock(context.Background(), "leader", ops...)
+	require.NoError(t, err)
+
+	// stealing the lock back notifies the other client that it lost it
+	err = owner.Steal(context.Background(), "leader")
+	require.NoError(t, err)
+	assert.True(t, owner.lockHeld("leader"))
+	require.Eventually(t, func() bool {
+		return !other.lockHeld("leader")
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+type versionedLogicalSwitch struct {
+	UUID        string            `ovsdb:"_uuid"`
+	Version     string            `ovsdb:"_version"`
+	Name        string            `ovsdb:"name"`
+	ExternalIds map[string]string `ovsdb:"external_ids"`
+}
+
+func (*versionedLogicalSwitch) Table() string {
+	return "Logical_Switch"
+}
+
+func TestClientVersionGuard(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	var nbSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal(apiTestSchema, &nbSchema)
+	require.NoError(t, err)
+	nbDB, err := model.NewClientDBModel("OVN_Northbound", map[string]model.Model{
+		"Logical_Switch": &versionedLogicalSwitch{},
+	})
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, nbDB, nbSchema)
+
+	newClient := func(opts ...Option) *ovsdbClient {
+		ovs, err := newOVSDBClient(nbDB, append(opts, WithEndpoint(fmt.Sprintf("unix:%s", sock)))...)
+		require.NoError(t, err)
+		err = ovs.Connect(context.Background())
+		require.NoError(t, err)
+		t.Cleanup(ovs.Close)
+		_, err = ovs.MonitorAll(context.Background())
+		require.NoError(t, err)
+		return ovs
+	}
+	guarded := newClient(WithVersionGuard())
+	other := newClient()
+
+	uuids, err := guarded.Insert(context.Background(), &versionedLogicalSwitch{Name: "ls0"})
+	require.NoError(t, err)
+	cached := func(ovs *ovsdbClient) *versionedLogicalSwitch {
+		var ls *versionedLogicalSwitch
+		require.Eventually(t, func() bool {
+			m := ovs.Cache().Table("Logical_Switch").Row(uuids[0])
+			if m == nil {
+				return false
+			}
+			ls = m.(*versionedLogicalSwitch)
+			return true
+		}, time.Second, 10*time.Millisecond)
+		return ls
+	}
+	stale := cached(guarded)
+	require.NotEmpty(t, stale.Version)
+	require.Eventually(t, func() bool {
+		return other.Cache().Table("Logical_Switch").Row(uuids[0]) != nil
+	}, time.Second, 10*time.Millisecond)
+
+	// a concurrent modification changes the version of the row
+	concurrent := &versionedLogicalSwitch{UUID: uuids[0], ExternalIds: map[string]string{"owner": "other"}}
+	ops, err := other.Where(concurrent).Update(concurrent, &concurrent.ExternalIds)
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+	results, err := other.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+	_, err = ovsdb.CheckOperationResults(results, ops)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return cached(guarded).Version != stale.Version
+	}, time.Second, 10*time.Millisecond)
+
+	// the update derived from the stale row is rejected
+	stale.ExternalIds = map[string]string{"owner": "guarded"}
+	ops, err = guarded.Where(stale).Update(stale, &stale.ExternalIds)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+	assert.Equal(t, ovsdb.OperationWait, ops[0].Op)
+	results, err = guarded.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+	errs, err := ovsdb.CheckOperationResults(results, ops)
+	require.Error(t, err)
+	require.Len(t, errs, 1)
+	assert.IsType(t, &ovsdb.TimedOut{}, errs[0])
+	results, err = guarded.Transact(context.Background(), ovsdb.Operation{
+		Op:    ovsdb.OperationSelect,
+		Table: "Logical_Switch",
+		Where: []ovsdb.Condition{ovsdb.NewCondition("_uuid", ovsdb.ConditionEqual, ovsdb.UUID{GoUUID: uuids[0]})},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ovsdb.OvsMap{GoMap: map[interface{}]interface{}{"owner": "other"}}, results[0].Rows[0]["external_ids"])
+
+	// the update derived from the current row succeeds
+	current := cached(guarded)
+	current.ExternalIds = map[string]string{"owner": "guarded"}
+	ops, err = guarded.Where(current).Update(current, &current.ExternalIds)
+	require.NoError(t, err)
+	results, err = guarded.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+	_, err = ovsdb.CheckOperationResults(results, ops)
+	require.NoError(t, err)
+
+	// the operations whose conditions may select other rows than the one of
+	// the model, which would be left unguarded, are rejected
+	current = cached(guarded)
+	_, err = guarded.WhereCache(func(ls *versionedLogicalSwitch) bool {
+		return true
+	}).Update(current, &current.ExternalIds)
+	assert.Error(t, err)
+	_, err = guarded.Where(current, model.Condition{
+		Field:    &current.Name,
+		Function: ovsdb.ConditionEqual,
+		Value:    "ls0",
+	}).Mutate(current, model.Mutation{
+		Field:   &current.ExternalIds,
+		Mutator: ovsdb.MutateOperationInsert,
+		Value:   map[string]string{"key": "value"},
+	})
+	assert.Error(t, err)
+	_, err = guarded.Where(&versionedLogicalSwitch{UUID: "other"}).Update(current, &current.ExternalIds)
+	assert.Error(t, err)
+}
+
+func TestClientWithoutCache(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newNBServer(t)
+	ovs, err := newOVSDBClient(nbDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)), WithoutCache())
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+	assert.Nil(t, ovs.Cache())
+
+	// operations are built and transacted as usual
+	ops, err := ovs.Create(&testLogicalSwitch{UUID: "ls0", Name: "ls0"})
+	require.NoError(t, err)
+	results, err := ovs.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	uuid := results[0].UUID.GoUUID
+
+	ops, err = ovs.Where(&testLogicalSwitch{UUID: uuid}).Update(&testLogicalSwitch{Name: "ls1"})
+	require.NoError(t, err)
+	_, err = ovs.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+
+	results, err = ovs.Transact(context.Background(), ovsdb.Operation{
+		Op:    ovsdb.OperationSelect,
+		Table: "Logical_Switch",
+		Where: []ovsdb.Condition{},
+	})
+	require.NoError(t, err)
+	var switches []testLogicalSwitch
+	err = ovs.GetSelectResults(results[0], &switches)
+	require.NoError(t, err)
+	require.Len(t, switches, 1)
+	assert.Equal(t, uuid, switches[0].UUID)
+	assert.Equal(t, "ls1", switches[0].Name)
+
+	// rows can still be fetched by UUID, from the server
+	models, err := ovs.GetByUUIDs(context.Background(), "Logical_Switch", []string{uuid})
+	require.NoError(t, err)
+	require.Len(t, models, 1)
+	assert.Equal(t, uuid, models[0].(*testLogicalSwitch).UUID)
+	assert.Equal(t, "ls1", models[0].(*testLogicalSwitch).Name)
+
+	// but nothing can be monitored nor read from the cache
+	_, err = ovs.MonitorAll(context.Background())
+	assert.Equal(t, ErrCacheDisabled, err)
+	_, err = ovs.Monitor(context.Background(), ovs.NewMonitor(WithTable(&testLogicalSwitch{})))
+	assert.Equal(t, ErrCacheDisabled, err)
+	err = ovs.List(context.Background(), &switches)
+	assert.Equal(t, ErrCacheDisabled, err)
+	err = ovs.Get(context.Background(), &testLogicalSwitch{UUID: uuid})
+	assert.Equal(t, ErrCacheDisabled, err)
+	err = ovs.WhereCache(func(*testLogicalSwitch) bool { return true }).List(context.Background(), &switches)
+	assert.Equal(t, ErrCacheDisabled, err)
+}
+
+// cuttingProxy forwards a connection to a server, until cut is set: the next
+// message of the server is then only half forwarded before the connection is
+// closed
+type cuttingProxy struct {
+	cut int32
+}
+
+func (p *cuttingProxy) serve(t *testing.T, listener net.Listener, server string) {
+	clientConn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	serverConn, err := net.Dial("unix", server)
+	if err != nil {
+		t.Error(err)
+		clientConn.Close()
+		return
+	}
+	go func() {
+		_, _ = io.Copy(serverConn, clientConn)
+		serverConn.Close()
+	}()
+	decoder := json.NewDecoder(serverConn)
+	for {
+		var msg json.RawMessage
+		if err := decoder.Decode(&msg); err != nil {
+			clientConn.Close()
+			return
+		}
+		if atomic.LoadInt32(&p.cut) == 1 {
+			_, _ = clientConn.Write(msg[:len(msg)/2])
+			clientConn.Close()
+			serverConn.Close()
+			return
+		}
+		if _, err := clientConn.Write(msg); err != nil {
+			serverConn.Close()
+			return
+		}
+	}
+}
+
+func TestClientMonitorDisconnectDuringDump(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newNBServer(t)
+	writer, err := newOVSDBClient(nbDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = writer.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(writer.Close)
+	for i := 0; i < 10; i++ {
+		_, err = writer.Insert(context.Background(), &testLogicalSwitch{Name: fmt.Sprintf("ls%d", i)})
+		require.NoError(t, err)
+	}
+
+	proxySock := fmt.Sprintf("/tmp/ovsdb-proxy-%d.sock", rand.Intn(10000))
+	listener, err := net.Listen("unix", proxySock)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		listener.Close()
+		os.Remove(proxySock)
+	})
+	proxy := &cuttingProxy{}
+	go proxy.serve(t, listener, sock)
+
+	ovs, err := newOVSDBClient(nbDB, WithEndpoint(fmt.Sprintf("unix:%s", proxySock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	// the server closes the connection halfway through the initial dump
+	atomic.StoreInt32(&proxy.cut, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = ovs.MonitorAll(ctx)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrNotConnected), err.Error())
+	assert.Contains(t, err.Error(), "initial dump")
+
+	// no part of the dump is exposed and no monitor is left registered
+	if c := ovs.Cache(); c != nil {
+		assert.Equal(t, 0, c.Table("Logical_Switch").Len())
+	}
+	db := ovs.databases[nbDB.Name()]
+	db.monitorsMutex.Lock()
+	assert.Empty(t, db.monitors)
+	db.monitorsMutex.Unlock()
+}
+
+func TestClientMonitorPopulateError(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newNBServer(t)
+	endpoint := fmt.Sprintf("unix:%s", sock)
+	writer, err := newOVSDBClient(nbDB, WithEndpoint(endpoint))
+	require.NoError(t, err)
+	err = writer.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(writer.Close)
+	for i := 0; i < 10; i++ {
+		_, err = writer.Insert(context.Background(), &testLogicalSwitch{Name: fmt.Sprintf("10.0.%d.0/24", i)})
+		require.NoError(t, err)
+	}
+	// a name the converter fails to read
+	_, err = writer.Insert(context.Background(), &testLogicalSwitch{Name: "not a cidr"})
+	require.NoError(t, err)
+
+	cidrDB, err := model.NewClientDBModel("OVN_Northbound", map[string]model.Model{
+		"Logical_Switch": &cidrLogicalSwitch{},
+	})
+	require.NoError(t, err)
+	ovs, err := newOVSDBClient(cidrDB, WithEndpoint(endpoint), WithTypeConverter(cidrConverter{}))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	_, err = ovs.MonitorAll(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "initial dump")
+
+	// the rows populated before the error are removed
+	assert.Equal(t, 0, ovs.Cache().Table("Logical_Switch").Len())
+	db := ovs.databases[cidrDB.Name()]
+	db.monitorsMutex.Lock()
+	assert.Empty(t, db.monitors)
+	db.monitorsMutex.Unlock()
+}
+
+// earlyUpdateProxy forwards the traffic between a client and a server, sending
+// an update renaming a row of the initial dump of a monitor right before its
+// reply, once inject is set
+type earlyUpdateProxy struct {
+	inject   int32
+	injected int32
+	// the monitor requests, by id
+	requests map[string]json.RawMessage
+	mutex    sync.Mutex
+}
+
+func (p *earlyUpdateProxy) serve(t *testing.T, listener net.Listener, server string) {
+	clientConn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	serverConn, err := net.Dial("unix", server)
+	if err != nil {
+		t.Error(err)
+		clientConn.Close()
+		return
+	}
+	go func() {
+		defer serverConn.Close()
+		decoder := json.NewDecoder(clientConn)
+		for {
+			var msg json.RawMessage
+			if err := decoder.Decode(&msg); err != nil {
+				return
+			}
+			var request struct {
+				Method string          `json:"method"`
+				ID     json.RawMessage `json:"id"`
+			}
+			if err := json.Unmarshal(msg, &request); err == nil && strings.HasPrefix(request.Method, "monitor") {
+				p.mutex.Lock()
+				p.requests[string(request.ID)] = msg
+				p.mutex.Unlock()
+			}
+			if _, err := serverConn.Write(msg); err != nil {
+				return
+			}
+		}
+	}()
+	defer clientConn.Close()
+	decoder := json.NewDecoder(serverConn)
+	for {
+		var msg json.RawMessage
+		if err := decoder.Decode(&msg); err != nil {
+			return
+		}
+		if atomic.LoadInt32(&p.inject) == 1 {
+			if update := p.earlyUpdate(t, msg); update != nil {
+				if _, err := clientConn.Write(update); err != nil {
+					return
+				}
+				atomic.StoreInt32(&p.injected, 1)
+			}
+		}
+		if _, err := clientConn.Write(msg); err != nil {
+			return
+		}
+	}
+}
+
+// earlyUpdate returns the update to send before a monitor reply, or nil if
+// the message is not one
+func (p *earlyUpdateProxy) earlyUpdate(t *testing.T, msg json.RawMessage) []byte {
+	var reply struct {
+		ID     json.RawMessage `json:"id"`
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(msg, &reply); err != nil || len(reply.ID) == 0 {
+		return nil
+	}
+	p.mutex.Lock()
+	raw, ok := p.requests[string(reply.ID)]
+	p.mutex.Unlock()
+	if !ok {
+		return nil
+	}
+	var request struct {
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &request))
+	cookie := request.Params[1]
+
+	var dump map[string]map[string]json.RawMessage
+	var lastTxnID string
+	if request.Method == ovsdb.ConditionalMonitorSinceRPC {
+		var result []json.RawMessage
+		require.NoError(t, json.Unmarshal(reply.Result, &result))
+		require.NoError(t, json.Unmarshal(result[1], &lastTxnID))
+		require.NoError(t, json.Unmarshal(result[2], &dump))
+	} else {
+		require.NoError(t, json.Unmarshal(reply.Result, &dump))
+	}
+	var uuid string
+	for uuid = range dump["Logical_Switch"] {
+		break
+	}
+	require.NotEmpty(t, uuid)
+
+	var update string
+	switch request.Method {
+	case ovsdb.MonitorRPC:
+		update = fmt.Sprintf(`{"method":"update","params":[%s,{"Logical_Switch":{%q:{"new":{"name":"early"}}}}],"id":null}`, cookie, uuid)
+	case ovsdb.ConditionalMonitorRPC:
+		update = fmt.Sprintf(`{"method":"update2","params":[%s,{"Logical_Switch":{%q:{"modify":{"name":"early"}}}}],"id":null}`, cookie, uuid)
+	default:
+		update = fmt.Sprintf(`{"method":"update3","params":[%s,%q,{"Logical_Switch":{%q:{"modify":{"name":"early"}}}}],"id":null}`, cookie, lastTxnID, uuid)
+	}
+	return []byte(update)
+}
+
+func TestClientMonitorUpdateBeforeReply(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newNBServer(t)
+	writer, err := newOVSDBClient(nbDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = writer.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(writer.Close)
+	_, err = writer.Insert(context.Background(), &testLogicalSwitch{Name: "ls0"})
+	require.NoError(t, err)
+
+	proxySock := fmt.Sprintf("/tmp/ovsdb-proxy-%d.sock", rand.Intn(10000))
+	listener, err := net.Listen("unix", proxySock)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		listener.Close()
+		os.Remove(proxySock)
+	})
+	proxy := &earlyUpdateProxy{requests: make(map[string]json.RawMessage)}
+	go proxy.serve(t, listener, sock)
+
+	ovs, err := newOVSDBClient(nbDB, WithEndpoint(fmt.Sprintf("unix:%s", proxySock)))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = ovs.Monitor(ctx, ovs.NewMonitor(WithTable(&testLogicalSwitchPort{})))
+	require.NoError(t, err)
+
+	// the update renaming ls0 is received before the initial dump holding it
+	atomic.StoreInt32(&proxy.inject, 1)
+	_, err = ovs.Monitor(ctx, ovs.NewMonitor(WithTable(&testLogicalSwitch{})))
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&proxy.injected))
+
+	switches := []testLogicalSwitch{}
+	err = ovs.List(ctx, &switches)
+	require.NoError(t, err)
+	require.Len(t, switches, 1)
+	assert.Equal(t, "early", switches[0].Name)
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// Clock provides the time to the client. Every time-dependent code path of the
+// client, such as the reconnect backoff, the inactivity probe or the waits for
+// a reconnection, goes through it, so tests can advance time deterministically
+// by supplying their own implementation with WithClock
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// After returns a channel on which the current time is sent once the
+	// duration has elapsed
+	After(d time.Duration) <-chan time.Time
+	// NewTicker returns a Ticker sending the current time every period
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks at intervals, like a time.Ticker
+type Ticker interface {
+	// C returns the channel on which the ticks are delivered
+	C() <-chan time.Time
+	// Stop turns off the ticker
+	Stop()
+}
+
+// realClock is the Clock used by default, backed by the time package
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
+
+// clockTimer adapts a Clock to the timer used by backoff.RetryNotifyWithTimer
+type clockTimer struct {
+	clock Clock
+	c     <-chan time.Time
+}
+
+func (t *clockTimer) Start(d time.Duration) {
+	t.c = t.clock.After(d)
+}
+
+func (t *clockTimer) Stop() {}
+
+func (t *clockTimer) C() <-chan time.Time {
+	return t.c
+}
+
+// withClockTimeout is like context.WithTimeout, measuring the timeout with the
+// given clock
+func withClockTimeout(parent context.Context, clock Clock, d time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := clock.(realClock); ok {
+		return context.WithTimeout(parent, d)
+	}
+	ctx, cancel := context.WithCancel(parent)
+	go func() {
+		select {
+		case <-clock.After(d):
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
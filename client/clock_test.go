@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a Clock whose time only moves when advanced by the test
+type fakeClock struct {
+	now     time.Time
+	waiters []fakeWaiter
+	tickers []*fakeTicker
+	mutex   sync.Mutex
+}
+
+type fakeWaiter struct {
+	deadline time.Time
+	c        chan time.Time
+}
+
+type fakeTicker struct {
+	clock  *fakeClock
+	period time.Duration
+	next   time.Time
+	c      chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, fakeWaiter{deadline: c.now.Add(d), c: ch})
+	return ch
+}
+
+func (c *fakeClock) NewTicker(d time.Duration) Ticker {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	t := &fakeTicker{clock: c, period: d, next: c.now.Add(d), c: make(chan time.Time, 1)}
+	c.tickers = append(c.tickers, t)
+	return t
+}
+
+// Advance moves the time forward, firing the timers and tickers that expire
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+	waiters := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.deadline.After(c.now) {
+			waiters = append(waiters, w)
+			continue
+		}
+		w.c <- c.now
+	}
+	c.waiters = waiters
+	for _, t := range c.tickers {
+		if t.next.After(c.now) {
+			continue
+		}
+		for !t.next.After(c.now) {
+			t.next = t.next.Add(t.period)
+		}
+		select {
+		case t.c <- c.now:
+		default:
+		}
+	}
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTicker) Stop() {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+	for i, other := range t.clock.tickers {
+		if other == t {
+			t.clock.tickers = append(t.clock.tickers[:i], t.clock.tickers[i+1:]...)
+			return
+		}
+	}
+}
+
+func TestWithClockTimeout(t *testing.T) {
+	clock := newFakeClock()
+	ctx, cancel := withClockTimeout(context.Background(), clock, time.Minute)
+	defer cancel()
+
+	require.Eventually(t, func() bool {
+		clock.mutex.Lock()
+		defer clock.mutex.Unlock()
+		return len(clock.waiters) == 1
+	}, time.Second, 10*time.Millisecond)
+	clock.Advance(59 * time.Second)
+	assert.NoError(t, ctx.Err())
+	clock.Advance(time.Second)
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context was not cancelled once the timeout elapsed")
+	}
+}
+
+func TestClientInactivityProbe(t *testing.T) {
+	clientDBModel, sock := newNBServer(t)
+	clock := newFakeClock()
+	ovs, err := newOVSDBClient(clientDBModel,
+		WithEndpoint(fmt.Sprintf("unix:%s", sock)),
+		WithInactivityProbe(time.Minute),
+		WithClock(clock),
+	)
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	// wait for the probe to start
+	require.Eventually(t, func() bool {
+		clock.mutex.Lock()
+		defer clock.mutex.Unlock()
+		return len(clock.tickers) == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.True(t, ovs.Health().LastEcho.IsZero())
+
+	// nothing is sent until the interval has elapsed
+	clock.Advance(30 * time.Second)
+	assert.True(t, ovs.Health().LastEcho.IsZero())
+
+	for i := 1; i <= 2; i++ {
+		clock.Advance(30 * time.Second)
+		now := clock.Now()
+		require.Eventually(t, func() bool {
+			return ovs.Health().LastEcho.Equal(now)
+		}, time.Second, 10*time.Millisecond, fmt.Sprintf("probe %d", i))
+		clock.Advance(30 * time.Second)
+	}
+	assert.True(t, ovs.Connected())
+
+	// the probe stops with the connection
+	ovs.Close()
+	require.Eventually(t, func() bool {
+		clock.mutex.Lock()
+		defer clock.mutex.Unlock()
+		return len(clock.tickers) == 0
+	}, time.Second, 10*time.Millisecond)
+}
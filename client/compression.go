@@ -0,0 +1,76 @@
+package client
+
+import (
+	"compress/flate"
+	"io"
+	"net"
+	"sync"
+)
+
+// Compression compresses the traffic of the connections to the peers that
+// support it, such as OVSDB proxies, which is worth it over constrained links
+// given the size of monitor replies. It is enabled with WithCompression
+type Compression interface {
+	// Name returns the name of the compression codec
+	Name() string
+	// Wrap returns a connection compressing what is written to conn, and
+	// decompressing what is read from it
+	Wrap(conn net.Conn) (net.Conn, error)
+}
+
+// deflateCompression compresses the traffic as a raw DEFLATE stream
+type deflateCompression struct {
+	level int
+}
+
+// NewDeflateCompression returns a Compression compressing the traffic as a
+// raw DEFLATE stream (RFC 1951) at the given compression level, as defined by
+// the compress/flate package. The stream is flushed after every message, so
+// the peer can decode it as soon as it is received
+func NewDeflateCompression(level int) Compression {
+	return deflateCompression{level: level}
+}
+
+func (d deflateCompression) Name() string {
+	return "deflate"
+}
+
+func (d deflateCompression) Wrap(conn net.Conn) (net.Conn, error) {
+	writer, err := flate.NewWriter(conn, d.level)
+	if err != nil {
+		return nil, err
+	}
+	return &deflateConn{
+		Conn:   conn,
+		reader: flate.NewReader(conn),
+		writer: writer,
+	}, nil
+}
+
+// deflateConn is a connection whose traffic is a raw DEFLATE stream
+type deflateConn struct {
+	net.Conn
+	reader      io.ReadCloser
+	writer      *flate.Writer
+	writerMutex sync.Mutex
+}
+
+func (c *deflateConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+func (c *deflateConn) Write(b []byte) (int, error) {
+	c.writerMutex.Lock()
+	defer c.writerMutex.Unlock()
+	n, err := c.writer.Write(b)
+	if err != nil {
+		return n, err
+	}
+	// the peer can only decode what precedes a flush
+	return n, c.writer.Flush()
+}
+
+func (c *deflateConn) Close() error {
+	c.reader.Close()
+	return c.Conn.Close()
+}
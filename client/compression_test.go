@@ -0,0 +1,93 @@
+package client
+
+import (
+	"compress/flate"
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/rpc2"
+	"github.com/cenkalti/rpc2/jsonrpc"
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingConn counts the bytes written to a connection
+type countingConn struct {
+	net.Conn
+	written int64
+}
+
+func (c *countingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	atomic.AddInt64(&c.written, int64(n))
+	return n, err
+}
+
+func TestDeflateCompression(t *testing.T) {
+	compression := NewDeflateCompression(flate.BestSpeed)
+	assert.Equal(t, "deflate", compression.Name())
+
+	clientConn, serverConn := net.Pipe()
+	counter := &countingConn{Conn: clientConn}
+	cc, err := compression.Wrap(counter)
+	require.NoError(t, err)
+	sc, err := compression.Wrap(serverConn)
+	require.NoError(t, err)
+
+	srv := rpc2.NewServer()
+	srv.Handle("echo", func(_ *rpc2.Client, args []interface{}, reply *[]interface{}) error {
+		*reply = args
+		return nil
+	})
+	go srv.ServeCodec(jsonrpc.NewJSONCodec(sc))
+
+	c := rpc2.NewClientWithCodec(jsonrpc.NewJSONCodec(cc))
+	go c.Run()
+	t.Cleanup(func() {
+		c.Close()
+	})
+
+	payload := strings.Repeat("compressible ", 10000)
+	for i := 0; i < 3; i++ {
+		var reply []interface{}
+		err = c.Call("echo", []interface{}{payload, i}, &reply)
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{payload, float64(i)}, reply)
+	}
+	assert.True(t, atomic.LoadInt64(&counter.written) < int64(len(payload)), "the traffic is not compressed")
+}
+
+func TestClientCompressionFallback(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	// the server does not support compression
+	nbDB, sock := newNBServer(t)
+	ovs, err := newOVSDBClient(nbDB,
+		WithEndpoint(fmt.Sprintf("unix:%s", sock)),
+		WithCompression(NewDeflateCompression(flate.DefaultCompression)),
+	)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = ovs.Connect(ctx)
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	results, err := ovs.Transact(context.Background(), ovsdb.Operation{
+		Op:    ovsdb.OperationSelect,
+		Table: "Logical_Switch",
+		Where: []ovsdb.Condition{},
+	})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	_, err = newOVSDBClient(nbDB, WithCompression(nil))
+	assert.Error(t, err)
+}
@@ -210,3 +210,41 @@ func newErrorConditional(err error) Conditional {
 		err: fmt.Errorf("conditionerror: %s", err.Error()),
 	}
 }
+
+// ConditionsFromIndex returns the conditions matching the row of the given
+// table that has the same index as the model. They apply to the columns of the
+// first index of the schema for which the model has non-default values, so
+// the server row matching a populated model can be selected, or updated,
+// without knowing its uuid
+func ConditionsFromIndex(schema ovsdb.DatabaseSchema, table string, m model.Model) ([]ovsdb.Condition, error) {
+	tableSchema := schema.Table(table)
+	if tableSchema == nil {
+		return nil, fmt.Errorf("table %s not found in schema %s", table, schema.Name)
+	}
+	info, err := mapper.NewInfo(table, tableSchema, m)
+	if err != nil {
+		return nil, err
+	}
+OUTER:
+	for _, index := range tableSchema.Indexes {
+		var conditions []ovsdb.Condition
+		for _, column := range index {
+			field, err := info.FieldByColumn(column)
+			if err != nil {
+				// the model does not hold this index
+				continue OUTER
+			}
+			columnSchema := tableSchema.Column(column)
+			if columnSchema == nil || ovsdb.IsDefaultValue(columnSchema, field) {
+				continue OUTER
+			}
+			value, err := ovsdb.NativeToOvs(columnSchema, field)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, ovsdb.NewCondition(column, ovsdb.ConditionEqual, value))
+		}
+		return conditions, nil
+	}
+	return nil, fmt.Errorf("model of table %s has no valid index", table)
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/ovn-org/libovsdb/model"
 	"github.com/ovn-org/libovsdb/ovsdb"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEqualityConditional(t *testing.T) {
@@ -445,3 +447,29 @@ func TestExplicitConditional(t *testing.T) {
 		})
 	}
 }
+
+func TestConditionsFromIndex(t *testing.T) {
+	var schema ovsdb.DatabaseSchema
+	err := json.Unmarshal(apiTestSchema, &schema)
+	require.NoError(t, err)
+
+	conditions, err := ConditionsFromIndex(schema, "Logical_Switch_Port", &testLogicalSwitchPort{
+		Name:        "lsp0",
+		ExternalIds: map[string]string{"foo": "bar"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []ovsdb.Condition{{
+		Column:   "name",
+		Function: ovsdb.ConditionEqual,
+		Value:    "lsp0",
+	}}, conditions)
+
+	_, err = ConditionsFromIndex(schema, "Logical_Switch_Port", &testLogicalSwitchPort{UUID: aUUID0})
+	assert.Error(t, err)
+
+	_, err = ConditionsFromIndex(schema, "Logical_Switch", &testLogicalSwitch{Name: "ls0"})
+	assert.Error(t, err)
+
+	_, err = ConditionsFromIndex(schema, "Unknown", &testLogicalSwitchPort{Name: "lsp0"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb"
+)
+
+// CacheDivergencePolicy is the action taken by VerifyCache when the cache
+// diverges from the server
+type CacheDivergencePolicy int
+
+const (
+	// CacheDivergenceLog logs the divergence, leaving the cache as is
+	CacheDivergenceLog CacheDivergencePolicy = iota
+	// CacheDivergenceResync logs the divergence and rebuilds the cache by
+	// reconnecting to the server. Without WithReconnect, the divergence is
+	// returned like with CacheDivergenceReturnError instead
+	CacheDivergenceResync
+	// CacheDivergenceReturnError returns the divergence as a
+	// *CacheDivergenceError, leaving the cache as is
+	CacheDivergenceReturnError
+)
+
+// CacheDivergenceError describes the rows of the cache that diverge from the
+// server: the rows the server does not have, the rows whose columns differ
+// and the rows missing from the cache
+type CacheDivergenceError struct {
+	Database string
+	// Rows holds the UUIDs of the diverging rows by table
+	Rows map[string][]string
+}
+
+func (e *CacheDivergenceError) Error() string {
+	tables := make([]string, 0, len(e.Rows))
+	for table := range e.Rows {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	var diverged []string
+	for _, table := range tables {
+		diverged = append(diverged, fmt.Sprintf("%s: %s", table, strings.Join(e.Rows[table], ", ")))
+	}
+	return fmt.Sprintf("cache of database %s diverges from the server: %s", e.Database, strings.Join(diverged, "; "))
+}
+
+// CacheDivergenceHandler is called with the divergence found by VerifyCache,
+// before the action of the policy is taken
+type CacheDivergenceHandler func(divergence *CacheDivergenceError)
+
+// VerifyCache reads all the rows of the tables of the cache from the server
+// and compares them with the cache. When they diverge, the handler set with
+// WithCacheDivergenceHandler is called and the action of the policy set with
+// WithCacheDivergencePolicy is taken; otherwise, nil is returned. Rows outside
+// of the conditions of the monitors or the table filters are not expected in
+// the cache, and only the presence of the rows is compared for the tables
+// monitored for some of their columns. Rows changed while verifying may be
+// reported as diverging, so the cache is best verified when the database is
+// quiescent
+func (o *ovsdbClient) VerifyCache(ctx context.Context) error {
+	divergence, err := o.cacheDivergence(ctx)
+	if err != nil || divergence == nil {
+		return err
+	}
+	if o.options.cacheDivergenceHandler != nil {
+		o.options.cacheDivergenceHandler(divergence)
+	}
+	switch o.options.cacheDivergencePolicy {
+	case CacheDivergenceResync:
+		if !o.options.reconnect {
+			return divergence
+		}
+		o.logger.V(3).Error(divergence, "triggering reconnect to rebuild cache")
+		o.rebuildCache()
+	case CacheDivergenceReturnError:
+		return divergence
+	default:
+		o.logger.V(3).Error(divergence, "cache diverges from the server")
+	}
+	return nil
+}
+
+// cacheDivergence returns the divergence of the cache from the server, or nil
+// if they match
+func (o *ovsdbClient) cacheDivergence(ctx context.Context) (*CacheDivergenceError, error) {
+	db := o.primaryDB()
+	if db.withoutCache {
+		return nil, ErrCacheDisabled
+	}
+	tableCache := o.Cache()
+	if tableCache == nil {
+		return nil, ErrNotConnected
+	}
+
+	// tables whose rows are not all expected in the cache, and tables whose
+	// rows only hold some of their columns
+	partialRows := make(map[string]bool)
+	partialColumns := make(map[string]bool)
+	for table := range o.options.filters {
+		partialRows[table] = true
+	}
+	db.monitorsMutex.Lock()
+	for _, monitor := range db.monitors {
+		for _, tableMonitor := range monitor.Tables {
+			if tableMonitor.Condition.Field != nil {
+				partialRows[tableMonitor.Table] = true
+			}
+			if len(tableMonitor.Fields) > 0 {
+				partialColumns[tableMonitor.Table] = true
+			}
+		}
+	}
+	db.monitorsMutex.Unlock()
+
+	tables := tableCache.Tables()
+	sort.Strings(tables)
+	ops := make([]ovsdb.Operation, 0, len(tables))
+	for _, table := range tables {
+		ops = append(ops, ovsdb.Operation{
+			Op:    ovsdb.OperationSelect,
+			Table: table,
+			Where: []ovsdb.Condition{},
+		})
+	}
+	serverRows := make([]map[string]model.Model, len(tables))
+	for i := range serverRows {
+		serverRows[i] = make(map[string]model.Model)
+	}
+	_, err := o.TransactRows(ctx, func(op int, row ovsdb.Row) error {
+		uuid, ok := row["_uuid"].(ovsdb.UUID)
+		if !ok {
+			return fmt.Errorf("row of table %s without uuid", tables[op])
+		}
+		m, err := tableCache.CreateModel(tables[op], &row, uuid.GoUUID)
+		if err != nil {
+			return err
+		}
+		serverRows[op][uuid.GoUUID] = m
+		return nil
+	}, ops...)
+	if err != nil {
+		return nil, err
+	}
+
+	divergence := &CacheDivergenceError{
+		Database: o.primaryDBName,
+		Rows:     make(map[string][]string),
+	}
+	for i, table := range tables {
+		var diverged []string
+		cached := tableCache.Table(table).Rows()
+		for uuid, m := range cached {
+			serverModel, ok := serverRows[i][uuid]
+			if !ok || (!partialColumns[table] && !model.Equal(m, serverModel)) {
+				diverged = append(diverged, uuid)
+			}
+		}
+		if !partialRows[table] {
+			for uuid := range serverRows[i] {
+				if _, ok := cached[uuid]; !ok {
+					diverged = append(diverged, uuid)
+				}
+			}
+		}
+		if len(diverged) > 0 {
+			sort.Strings(diverged)
+			divergence.Rows[table] = diverged
+		}
+	}
+	if len(divergence.Rows) == 0 {
+		return nil, nil
+	}
+	return divergence, nil
+}
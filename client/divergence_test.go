@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientVerifyCache(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	nbDB, sock := newNBServer(t)
+	staleUUID := "b1d2ccb8-4b2b-4a7d-9a6b-6b9d9b8e0f01"
+
+	// newClient returns a connected client whose cache holds a row that the
+	// server does not have, next to a row that both have
+	newClient := func(t *testing.T, opts ...Option) *ovsdbClient {
+		opts = append(opts, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+		ovs, err := newOVSDBClient(nbDB, opts...)
+		require.NoError(t, err)
+		err = ovs.Connect(context.Background())
+		require.NoError(t, err)
+		t.Cleanup(ovs.Close)
+		_, err = ovs.MonitorAll(context.Background())
+		require.NoError(t, err)
+		err = ovs.VerifyCache(context.Background())
+		require.NoError(t, err)
+
+		err = ovs.Cache().Table("Logical_Switch").Create(staleUUID, &testLogicalSwitch{UUID: staleUUID, Name: "stale"}, false)
+		require.NoError(t, err)
+		return ovs
+	}
+
+	setup, err := newOVSDBClient(nbDB, WithEndpoint(fmt.Sprintf("unix:%s", sock)))
+	require.NoError(t, err)
+	err = setup.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(setup.Close)
+	ops, err := setup.Create(&testLogicalSwitch{Name: "ls0"})
+	require.NoError(t, err)
+	_, err = setup.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+
+	expected := &CacheDivergenceError{
+		Database: "OVN_Northbound",
+		Rows:     map[string][]string{"Logical_Switch": {staleUUID}},
+	}
+
+	t.Run("log", func(t *testing.T) {
+		var handled *CacheDivergenceError
+		ovs := newClient(t, WithCacheDivergenceHandler(func(divergence *CacheDivergenceError) {
+			handled = divergence
+		}))
+		err := ovs.VerifyCache(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, expected, handled)
+		assert.NotNil(t, ovs.Cache().Table("Logical_Switch").Row(staleUUID))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ovs := newClient(t, WithCacheDivergencePolicy(CacheDivergenceReturnError))
+		err := ovs.VerifyCache(context.Background())
+		var divergence *CacheDivergenceError
+		require.True(t, errors.As(err, &divergence))
+		assert.Equal(t, expected, divergence)
+		assert.NotNil(t, ovs.Cache().Table("Logical_Switch").Row(staleUUID))
+	})
+
+	t.Run("resync", func(t *testing.T) {
+		ovs := newClient(t,
+			WithReconnect(time.Second, &backoff.ZeroBackOff{}),
+			WithCacheDivergencePolicy(CacheDivergenceResync))
+		err := ovs.VerifyCache(context.Background())
+		assert.NoError(t, err)
+		require.Eventually(t, func() bool {
+			tableCache := ovs.Cache()
+			return ovs.Connected() && tableCache != nil && tableCache.Table("Logical_Switch").Len() == 1 &&
+				tableCache.Table("Logical_Switch").Row(staleUUID) == nil
+		}, 5*time.Second, 10*time.Millisecond)
+		err = ovs.VerifyCache(context.Background())
+		assert.NoError(t, err)
+	})
+
+	t.Run("resync without reconnect", func(t *testing.T) {
+		ovs := newClient(t, WithCacheDivergencePolicy(CacheDivergenceResync))
+		err := ovs.VerifyCache(context.Background())
+		var divergence *CacheDivergenceError
+		require.True(t, errors.As(err, &divergence))
+		assert.True(t, ovs.Connected())
+	})
+
+	t.Run("unknown policy", func(t *testing.T) {
+		_, err := newOVSDBClient(nbDB, WithCacheDivergencePolicy(CacheDivergencePolicy(42)))
+		assert.Error(t, err)
+	})
+}
@@ -90,6 +90,16 @@ For example, the following operation will delete all the Logical Switches named
 			Value: "bar",
 	}).Delete()
 
+To match the rows where an optional column is unset, or where a set or map column is empty, use a nil Value
+(or a nil pointer of the field type). It is sent to the server as the empty set (["set", []]) or the empty
+map (["map", []]):
+
+	ops, err := ovs.Where(lsp, client.Condition {
+		Field: &lsp.DynamicAddresses,
+		Function: ovsdb.ConditionEqual,
+		Value: nil,
+	}).Delete()
+
 To create a Condition that matches all of the conditions simultaneously (i.e: AND semantics), use WhereAll().
 
 Where() and WhereAll() inject conditions into operations that will be evaluated by the server.
@@ -17,6 +17,9 @@ type Monitor struct {
 	Tables            []TableMonitor
 	Errors            []error
 	LastTransactionID string
+	// synced is true once the initial state of the monitor has been
+	// populated in the cache
+	synced bool
 }
 
 // newMonitor creates a new *Monitor with default values
@@ -66,6 +69,11 @@ type TableMonitor struct {
 	// Fields are the fields in the model to monitor
 	// If none are supplied, all fields will be used
 	Fields []interface{}
+	// Select are the kinds of updates to monitor. If nil, it is omitted from
+	// the request and the server sends all of them
+	Select *ovsdb.MonitorSelect
+	// model is the model the Condition and Fields point to
+	model model.Model
 }
 
 func WithTable(m model.Model, fields ...interface{}) MonitorOption {
@@ -77,12 +85,17 @@ func WithTable(m model.Model, fields ...interface{}) MonitorOption {
 		tableMonitor := TableMonitor{
 			Table:  tableName,
 			Fields: fields,
+			model:  m,
 		}
 		monitor.Tables = append(monitor.Tables, tableMonitor)
 		return nil
 	}
 }
 
+// WithConditionalTable monitors the rows of a table matching a condition. The
+// condition and the fields, if any, must point to fields of the given model.
+// Both can be combined, in which case only the given fields of the matching
+// rows are monitored
 func WithConditionalTable(m model.Model, condition model.Condition, fields ...interface{}) MonitorOption {
 	return func(o *ovsdbClient, monitor *Monitor) error {
 		tableName := o.primaryDB().model.FindTable(reflect.TypeOf(m))
@@ -93,6 +106,7 @@ func WithConditionalTable(m model.Model, condition model.Condition, fields ...in
 			Table:     tableName,
 			Condition: condition,
 			Fields:    fields,
+			model:     m,
 		}
 		monitor.Tables = append(monitor.Tables, tableMonitor)
 		return nil
@@ -2,11 +2,14 @@ package client
 
 import (
 	"crypto/tls"
+	"fmt"
 	"net/url"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/go-logr/logr"
+	"github.com/ovn-org/libovsdb/cache"
+	"github.com/ovn-org/libovsdb/mapper"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -17,15 +20,37 @@ const (
 )
 
 type options struct {
-	endpoints             []string
-	tlsConfig             *tls.Config
-	reconnect             bool
-	leaderOnly            bool
-	timeout               time.Duration
-	backoff               backoff.BackOff
-	logger                *logr.Logger
-	registry              prometheus.Registerer
-	shouldRegisterMetrics bool // in case metrics are changed after-the-fact
+	endpoints              []string
+	endpointPriorities     map[string]int
+	tlsConfig              *tls.Config
+	reconnect              bool
+	leaderOnly             bool
+	leaderRetry            bool
+	timeout                time.Duration
+	backoff                backoff.BackOff
+	reconnectMaxAttempts   int
+	reconnectMaxDuration   time.Duration
+	writeQueue             bool
+	writeQueueTable        string
+	writeQueueColumn       string
+	writeBufferSize        int
+	writeBufferInterval    time.Duration
+	inactivityProbe        time.Duration
+	rejectDuplicateKeys    bool
+	versionGuard           bool
+	withoutCache           bool
+	clock                  Clock
+	converters             *mapper.Converters
+	filters                map[string]cache.Filter
+	compression            Compression
+	schemaChangeHandler    SchemaChangeHandler
+	cacheDivergencePolicy  CacheDivergencePolicy
+	cacheDivergenceHandler CacheDivergenceHandler
+	wireTap                WireTap
+	readOnly               bool
+	logger                 *logr.Logger
+	registry               prometheus.Registerer
+	shouldRegisterMetrics  bool // in case metrics are changed after-the-fact
 }
 
 type Option func(o *options) error
@@ -58,6 +83,17 @@ func WithTLSConfig(cfg *tls.Config) Option {
 // Endpoints are specified in OVSDB Connection Format
 // For more details, see the ovsdb(7) man page
 func WithEndpoint(endpoint string) Option {
+	return WithEndpointPriority(endpoint, 0)
+}
+
+// WithEndpointPriority sets an endpoint to be used by the client, like
+// WithEndpoint, with the given priority. On connection and reconnection, the
+// endpoints are tried by decreasing priority, so that the endpoints of lower
+// priority, e.g. in distant regions, are only used when those of higher
+// priority are unreachable. The endpoints set with WithEndpoint have a
+// priority of 0. Once connected, the client does not switch back to an
+// endpoint of higher priority until it reconnects.
+func WithEndpointPriority(endpoint string, priority int) Option {
 	return func(o *options) error {
 		ep, err := url.Parse(endpoint)
 		if err != nil {
@@ -66,21 +102,24 @@ func WithEndpoint(endpoint string) Option {
 		switch ep.Scheme {
 		case UNIX:
 			if len(ep.Path) == 0 {
-				o.endpoints = append(o.endpoints, defaultUnixEndpoint)
-				return nil
+				endpoint = defaultUnixEndpoint
 			}
 		case TCP:
 			if len(ep.Opaque) == 0 {
-				o.endpoints = append(o.endpoints, defaultTCPEndpoint)
-				return nil
+				endpoint = defaultTCPEndpoint
 			}
 		case SSL:
 			if len(ep.Opaque) == 0 {
-				o.endpoints = append(o.endpoints, defaultSSLEndpoint)
-				return nil
+				endpoint = defaultSSLEndpoint
 			}
 		}
 		o.endpoints = append(o.endpoints, endpoint)
+		if priority != 0 {
+			if o.endpointPriorities == nil {
+				o.endpointPriorities = make(map[string]int)
+			}
+			o.endpointPriorities[endpoint] = priority
+		}
 		return nil
 	}
 }
@@ -94,17 +133,318 @@ func WithLeaderOnly(leaderOnly bool) Option {
 	}
 }
 
+// WithLeaderRetry tells the client to retry a transaction once when it is
+// rejected because the server is not the leader of the cluster anymore, as the
+// leadership may change after the client connected to the leader. The client
+// then reconnects to another endpoint, the leader, and sends the transaction
+// again. The transactions of TransactRows are not retried. It has no effect
+// unless WithLeaderOnly and WithReconnect are also used.
+func WithLeaderRetry() Option {
+	return func(o *options) error {
+		o.leaderRetry = true
+		return nil
+	}
+}
+
 // WithReconnect tells the client to automatically reconnect when
 // disconnected. The timeout is used to construct the context on
 // each call to Connect, while backoff dictates the backoff
-// algorithm to use. Using WithReconnect implies that
-// requested transactions will block until the client has fully reconnected,
-// rather than immediately returning an error if there is no connection.
+// algorithm to use, an ExponentialJitterBackoff if nil. Using WithReconnect
+// implies that requested transactions will block until the client has fully
+// reconnected, rather than immediately returning an error if there is no
+// connection.
 func WithReconnect(timeout time.Duration, backoff backoff.BackOff) Option {
 	return func(o *options) error {
 		o.reconnect = true
 		o.timeout = timeout
 		o.backoff = backoff
+		if backoff == nil {
+			o.backoff = &strategyBackOff{strategy: defaultBackoffStrategy}
+		}
+		return nil
+	}
+}
+
+// WithReconnectStrategy is like WithReconnect, with the intervals between the
+// attempts to reconnect given by a BackoffStrategy, e.g. a fixed cadence or
+// decorrelated jitter, instead of a backoff.BackOff. A nil strategy stands
+// for the default ExponentialJitterBackoff. The intervals are waited with the
+// Clock of the client
+func WithReconnectStrategy(timeout time.Duration, strategy BackoffStrategy) Option {
+	if strategy == nil {
+		strategy = defaultBackoffStrategy
+	}
+	return WithReconnect(timeout, &strategyBackOff{strategy: strategy})
+}
+
+// WithReconnectLimit bounds the automatic reconnection configured with
+// WithReconnect. The client gives up after maxAttempts failed attempts or once
+// maxDuration has elapsed since the connection was lost, whichever comes
+// first. A zero value disables the corresponding limit. Once the client gives
+// up, it behaves as if it had been disconnected without WithReconnect: the
+// DisconnectNotify channel is notified and transactions waiting for the
+// reconnection fail with ErrReconnectFailed.
+func WithReconnectLimit(maxAttempts int, maxDuration time.Duration) Option {
+	return func(o *options) error {
+		if maxAttempts < 0 || maxDuration < 0 {
+			return fmt.Errorf("invalid reconnect limit: attempts %d, duration %s", maxAttempts, maxDuration)
+		}
+		o.reconnectMaxAttempts = maxAttempts
+		o.reconnectMaxDuration = maxDuration
+		return nil
+	}
+}
+
+// WithWriteQueue tells the client to queue the transactions submitted while it
+// is disconnected, including before the first Connect, instead of sending them
+// on whatever connection becomes available first. Queued transactions are
+// replayed in submission order once the client has connected and restarted its
+// monitors, and transactions submitted while the queue is being replayed are
+// queued behind it. A transaction whose context expires before it is sent is
+// dequeued.
+//
+// Each queued transaction is committed exactly once: when it commits, it
+// records a marker of its own as a key of the given column, a map of strings,
+// of every row of the given table, which must hold at least one row, like the
+// root table of the database. One whose reply is lost to a disconnect is
+// replayed on the next connection, and the replay is a no-op, failing with
+// ErrTransactionReplayed, if the marker shows it was committed already. The
+// marker is removed once the outcome of the transaction is known. The queued
+// transactions must not overwrite the column as a whole. It has no effect
+// unless WithReconnect is also used.
+func WithWriteQueue(table, column string) Option {
+	return func(o *options) error {
+		if table == "" || column == "" {
+			return fmt.Errorf("invalid write queue marker column %q of table %q", column, table)
+		}
+		o.writeQueue = true
+		o.writeQueueTable = table
+		o.writeQueueColumn = column
+		return nil
+	}
+}
+
+// WithWriteBuffer tells the client to buffer what it sends to the endpoints,
+// so that a burst of transactions is sent with fewer writes to the connection.
+// The buffer is flushed once it holds size bytes, or flushInterval after the
+// first message it holds was written, whichever comes first: a larger buffer
+// or interval trades latency for throughput.
+func WithWriteBuffer(size int, flushInterval time.Duration) Option {
+	return func(o *options) error {
+		if size <= 0 {
+			return fmt.Errorf("invalid write buffer size: %d", size)
+		}
+		if flushInterval <= 0 {
+			return fmt.Errorf("invalid write buffer flush interval: %s", flushInterval)
+		}
+		o.writeBufferSize = size
+		o.writeBufferInterval = flushInterval
+		return nil
+	}
+}
+
+// WithInactivityProbe tells the client to send an echo request to the server
+// every interval. If the server does not reply within interval, the connection
+// is considered dead and is closed, which triggers a reconnection when
+// WithReconnect is used.
+func WithInactivityProbe(interval time.Duration) Option {
+	return func(o *options) error {
+		if interval <= 0 {
+			return fmt.Errorf("invalid inactivity probe interval: %s", interval)
+		}
+		o.inactivityProbe = interval
+		return nil
+	}
+}
+
+// WithRejectDuplicateKeys tells the client to reject the transaction replies
+// holding a JSON object with the same key more than once, instead of silently
+// using the last value of the key. Such replies make Transact fail with an
+// error wrapping ovsdb.ErrDuplicateKey.
+func WithRejectDuplicateKeys() Option {
+	return func(o *options) error {
+		o.rejectDuplicateKeys = true
+		return nil
+	}
+}
+
+// WithVersionGuard makes the operations returned by Update and Mutate fail the
+// transaction if the row of the given model was modified since the model was
+// read, for instance from the cache, instead of silently overwriting the
+// concurrent change. A wait operation asserting that the row still has the
+// same version precedes them. It only applies to models holding both a _uuid
+// and a _version, the latter being mapped by a string field tagged with the
+// _version column. As only the row of the model is guarded, the operations
+// must select it by its _uuid, with Where and the model: Update and Mutate
+// fail rather than leave unguarded the other rows that WhereCache, or Where
+// with conditions, may select.
+func WithVersionGuard() Option {
+	return func(o *options) error {
+		o.versionGuard = true
+		return nil
+	}
+}
+
+// WithoutCache disables the cache of the database, for clients that only
+// issue transactions. No cache is allocated, so the operations can be built
+// with the API, but reading from the cache and monitoring the database fail
+// with ErrCacheDisabled. The _Server database used to follow the leader with
+// WithLeaderOnly, or the schema changes with WithSchemaChangeHandler, is still
+// cached.
+func WithoutCache() Option {
+	return func(o *options) error {
+		o.withoutCache = true
+		return nil
+	}
+}
+
+// WithColumnConverter registers a Converter for the given column of a table,
+// so the model field mapped to that column may be of the converter's
+// FieldType instead of the native type of the column. The converter is used
+// whenever the field is marshaled to or unmarshaled from the database.
+func WithColumnConverter(table, column string, converter mapper.Converter) Option {
+	return func(o *options) error {
+		if converter == nil {
+			return fmt.Errorf("invalid nil converter for table %s column %s", table, column)
+		}
+		if o.converters == nil {
+			o.converters = mapper.NewConverters()
+		}
+		o.converters.SetColumnConverter(table, column, converter)
+		return nil
+	}
+}
+
+// WithTypeConverter registers a Converter for every model field of the
+// converter's FieldType, whatever the column it is mapped to. Converters
+// registered with WithColumnConverter take precedence.
+func WithTypeConverter(converter mapper.Converter) Option {
+	return func(o *options) error {
+		if converter == nil {
+			return fmt.Errorf("invalid nil converter")
+		}
+		if o.converters == nil {
+			o.converters = mapper.NewConverters()
+		}
+		o.converters.SetTypeConverter(converter)
+		return nil
+	}
+}
+
+// WithTableFilter sets a filter discarding the monitor updates of the rows of
+// the given table for which it returns false, so they are neither cached nor
+// notified to the event handlers. It allows keeping rows out of the cache on
+// criteria that monitor conditions cannot express.
+func WithTableFilter(table string, filter cache.Filter) Option {
+	return func(o *options) error {
+		if filter == nil {
+			return fmt.Errorf("invalid nil filter for table %s", table)
+		}
+		if o.filters == nil {
+			o.filters = make(map[string]cache.Filter)
+		}
+		o.filters[table] = filter
+		return nil
+	}
+}
+
+// WithCompression compresses the traffic with the endpoints using the given
+// Compression. When the first exchange with an endpoint fails over the
+// compressed connection, the peer is deemed not to support it and the client
+// falls back to an uncompressed connection.
+func WithCompression(compression Compression) Option {
+	return func(o *options) error {
+		if compression == nil {
+			return fmt.Errorf("invalid nil compression")
+		}
+		o.compression = compression
+		return nil
+	}
+}
+
+// SchemaChangeHandler is called with the name of the database, and its former
+// and new schema versions, when the schema of the database changes
+type SchemaChangeHandler func(database, oldVersion, newVersion string)
+
+// WithSchemaChangeHandler tells the client to monitor the Database table of
+// the _Server database, and to call the handler whenever the version of the
+// schema of the client database changes, for instance after ovsdb-client
+// convert, so the caller can react, e.g. by reloading its models. The handler
+// is called from the cache event processing and should not block.
+func WithSchemaChangeHandler(handler SchemaChangeHandler) Option {
+	return func(o *options) error {
+		if handler == nil {
+			return fmt.Errorf("schema change handler cannot be nil")
+		}
+		o.schemaChangeHandler = handler
+		return nil
+	}
+}
+
+// WithCacheDivergencePolicy sets the action taken by VerifyCache when the
+// cache diverges from the server. Otherwise, the divergence is logged
+func WithCacheDivergencePolicy(policy CacheDivergencePolicy) Option {
+	return func(o *options) error {
+		switch policy {
+		case CacheDivergenceLog, CacheDivergenceResync, CacheDivergenceReturnError:
+		default:
+			return fmt.Errorf("unknown cache divergence policy %d", policy)
+		}
+		o.cacheDivergencePolicy = policy
+		return nil
+	}
+}
+
+// WithCacheDivergenceHandler sets a handler called with the divergence found
+// by VerifyCache, before the action of the policy set with
+// WithCacheDivergencePolicy is taken
+func WithCacheDivergenceHandler(handler CacheDivergenceHandler) Option {
+	return func(o *options) error {
+		if handler == nil {
+			return fmt.Errorf("cache divergence handler cannot be nil")
+		}
+		o.cacheDivergenceHandler = handler
+		return nil
+	}
+}
+
+// WithWireTap tells the client to call tap with the raw bytes of every message
+// it sends to and receives from the server, e.g. to debug wire-level issues.
+// The messages are those of the JSON-RPC protocol, before any compression.
+// The tap is called from a goroutine of its own, in the order of the
+// messages of each connection, so that it never blocks the client; the
+// messages are dropped while too many of them wait for a slow tap
+func WithWireTap(tap WireTap) Option {
+	return func(o *options) error {
+		if tap == nil {
+			return fmt.Errorf("wire tap cannot be nil")
+		}
+		o.wireTap = tap
+		return nil
+	}
+}
+
+// WithReadOnly tells the client to reject the transactions writing to the
+// database, with insert, update, delete or mutate operations, with a
+// *ReadOnlyError instead of sending them, e.g. for a process that should only
+// observe the database. The other operations, like select and wait, are
+// allowed
+func WithReadOnly() Option {
+	return func(o *options) error {
+		o.readOnly = true
+		return nil
+	}
+}
+
+// WithClock sets the Clock used by the client to measure time. Otherwise, the
+// real clock is used. It is meant for tests, which can then drive the
+// time-dependent behavior of the client without waiting.
+func WithClock(clock Clock) Option {
+	return func(o *options) error {
+		if clock == nil {
+			return fmt.Errorf("invalid nil clock")
+		}
+		o.clock = clock
 		return nil
 	}
 }
@@ -106,6 +106,19 @@ func TestWithEndpoint(t *testing.T) {
 	}
 }
 
+func TestWithEndpointPriority(t *testing.T) {
+	opts := &options{}
+	err := WithEndpoint("unix:/tmp/low.sock")(opts)
+	require.NoError(t, err)
+	err = WithEndpointPriority("unix:", 10)(opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"unix:/tmp/low.sock", defaultUnixEndpoint}, opts.endpoints)
+	assert.Equal(t, map[string]int{defaultUnixEndpoint: 10}, opts.endpointPriorities)
+
+	err = WithEndpointPriority("foo : ", 10)(opts)
+	assert.Error(t, err)
+}
+
 func TestWithReconnect(t *testing.T) {
 	timeout := 2 * time.Second
 	opts := &options{}
@@ -116,3 +129,93 @@ func TestWithReconnect(t *testing.T) {
 	assert.Equal(t, true, opts.reconnect)
 	assert.Equal(t, &backoff.ZeroBackOff{}, opts.backoff)
 }
+
+func TestWithReconnectStrategy(t *testing.T) {
+	opts := &options{}
+	err := WithReconnect(time.Second, nil)(opts)
+	require.NoError(t, err)
+	assert.Equal(t, &strategyBackOff{strategy: defaultBackoffStrategy}, opts.backoff)
+
+	strategy := ExponentialJitterBackoff{Initial: time.Second, Max: time.Minute}
+	opts = &options{}
+	err = WithReconnectStrategy(2*time.Second, strategy)(opts)
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, opts.timeout)
+	assert.Equal(t, true, opts.reconnect)
+	assert.Equal(t, &strategyBackOff{strategy: strategy}, opts.backoff)
+}
+
+func TestWithReconnectLimit(t *testing.T) {
+	opts := &options{}
+	fn := WithReconnectLimit(3, time.Minute)
+	err := fn(opts)
+	require.NoError(t, err)
+	assert.Equal(t, 3, opts.reconnectMaxAttempts)
+	assert.Equal(t, time.Minute, opts.reconnectMaxDuration)
+
+	fn = WithReconnectLimit(-1, 0)
+	err = fn(opts)
+	assert.Error(t, err)
+}
+
+func TestWithWriteQueue(t *testing.T) {
+	opts := &options{}
+	fn := WithWriteQueue("Open_vSwitch", "external_ids")
+	err := fn(opts)
+	require.NoError(t, err)
+	assert.Equal(t, true, opts.writeQueue)
+	assert.Equal(t, "Open_vSwitch", opts.writeQueueTable)
+	assert.Equal(t, "external_ids", opts.writeQueueColumn)
+
+	fn = WithWriteQueue("", "external_ids")
+	err = fn(&options{})
+	assert.Error(t, err)
+}
+
+func TestWithReadOnly(t *testing.T) {
+	opts := &options{}
+	fn := WithReadOnly()
+	err := fn(opts)
+	require.NoError(t, err)
+	assert.Equal(t, true, opts.readOnly)
+}
+
+func TestWithWriteBuffer(t *testing.T) {
+	opts := &options{}
+	err := WithWriteBuffer(4096, time.Millisecond)(opts)
+	require.NoError(t, err)
+	assert.Equal(t, 4096, opts.writeBufferSize)
+	assert.Equal(t, time.Millisecond, opts.writeBufferInterval)
+
+	err = WithWriteBuffer(0, time.Millisecond)(opts)
+	assert.Error(t, err)
+	err = WithWriteBuffer(4096, 0)(opts)
+	assert.Error(t, err)
+}
+
+func TestWithInactivityProbe(t *testing.T) {
+	opts := &options{}
+	err := WithInactivityProbe(time.Second)(opts)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, opts.inactivityProbe)
+
+	err = WithInactivityProbe(0)(opts)
+	assert.Error(t, err)
+}
+
+func TestWithClock(t *testing.T) {
+	clock := newFakeClock()
+	opts, err := newOptions(WithClock(clock))
+	require.NoError(t, err)
+	assert.Equal(t, clock, opts.clock)
+
+	_, err = newOptions(WithClock(nil))
+	assert.Error(t, err)
+}
+
+func TestWithRejectDuplicateKeys(t *testing.T) {
+	opts := &options{}
+	err := WithRejectDuplicateKeys()(opts)
+	require.NoError(t, err)
+	assert.True(t, opts.rejectDuplicateKeys)
+}
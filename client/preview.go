@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/ovn-org/libovsdb/cache"
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb"
+)
+
+// RowDelta is the change a transaction would cause to a row of the cache. Old
+// is nil for an added row and New is nil for a deleted one
+type RowDelta struct {
+	Table string
+	UUID  string
+	Old   model.Model
+	New   model.Model
+}
+
+// PreviewTransaction simulates the operations against a copy of the cache and
+// returns the rows they would add, update or delete, in the order they are
+// first changed, without changing the cache or contacting the server. Inserted
+// rows are identified by their uuid-name, if they have one. Operations that do
+// not change rows are ignored, and mutations are not supported
+func (a api) PreviewTransaction(ctx context.Context, operations ...ovsdb.Operation) ([]RowDelta, error) {
+	if a.cache == nil {
+		return nil, ErrCacheDisabled
+	}
+	dbModel := a.cache.DatabaseModel()
+	data := make(cache.Data)
+	for _, table := range a.cache.Tables() {
+		data[table] = a.cache.Table(table).Rows()
+	}
+	snapshot, err := cache.NewTableCache(dbModel, data, a.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var deltas []RowDelta
+	positions := make(map[string]int)
+	record := func(table, uuid string, old, new model.Model) {
+		key := table + "/" + uuid
+		if i, ok := positions[key]; ok {
+			deltas[i].New = new
+			return
+		}
+		positions[key] = len(deltas)
+		deltas = append(deltas, RowDelta{Table: table, UUID: uuid, Old: old, New: new})
+	}
+
+	for i, op := range operations {
+		opErr := func(err error) error {
+			return fmt.Errorf("operation %d (%s on %s): %w", i, op.Op, op.Table, err)
+		}
+		switch op.Op {
+		case ovsdb.OperationInsert, ovsdb.OperationUpdate, ovsdb.OperationDelete:
+		case ovsdb.OperationMutate:
+			return nil, opErr(fmt.Errorf("mutations cannot be previewed"))
+		default:
+			continue
+		}
+		rows := snapshot.Table(op.Table)
+		if rows == nil {
+			return nil, opErr(fmt.Errorf("table not found"))
+		}
+		if op.Op == ovsdb.OperationInsert {
+			rowUUID := op.UUIDName
+			if rowUUID == "" {
+				rowUUID = uuid.NewString()
+			}
+			m, err := snapshot.CreateModel(op.Table, &op.Row, rowUUID)
+			if err != nil {
+				return nil, opErr(err)
+			}
+			if err := rows.Create(rowUUID, m, true); err != nil {
+				return nil, opErr(err)
+			}
+			record(op.Table, rowUUID, nil, m)
+			continue
+		}
+		matches, err := rowsByConditions(rows, op.Where)
+		if err != nil {
+			return nil, opErr(err)
+		}
+		uuids := make([]string, 0, len(matches))
+		for rowUUID := range matches {
+			uuids = append(uuids, rowUUID)
+		}
+		sort.Strings(uuids)
+		for _, rowUUID := range uuids {
+			old := matches[rowUUID]
+			if op.Op == ovsdb.OperationDelete {
+				if err := rows.Delete(rowUUID); err != nil {
+					return nil, opErr(err)
+				}
+				record(op.Table, rowUUID, old, nil)
+				continue
+			}
+			new := model.Clone(old)
+			info, err := dbModel.NewModelInfo(new)
+			if err != nil {
+				return nil, opErr(err)
+			}
+			if err := dbModel.Mapper.GetRowData(&op.Row, info); err != nil {
+				return nil, opErr(err)
+			}
+			if err := rows.Update(rowUUID, new, true); err != nil {
+				return nil, opErr(err)
+			}
+			record(op.Table, rowUUID, old, new)
+		}
+	}
+
+	// drop the rows that end up as they were, such as the rows inserted and
+	// then deleted by the transaction
+	result := []RowDelta{}
+	for _, delta := range deltas {
+		if delta.Old == nil && delta.New == nil {
+			continue
+		}
+		if delta.Old != nil && delta.New != nil && model.Equal(delta.Old, delta.New) {
+			continue
+		}
+		result = append(result, delta)
+	}
+	return result, nil
+}
+
+// rowsByConditions returns the rows of the cache matching all the conditions
+func rowsByConditions(rows *cache.RowCache, conditions []ovsdb.Condition) (map[string]model.Model, error) {
+	if len(conditions) == 0 {
+		return rows.Rows(), nil
+	}
+	matches, err := rows.RowsByCondition(conditions[:1])
+	if err != nil {
+		return nil, err
+	}
+	for _, condition := range conditions[1:] {
+		others, err := rows.RowsByCondition([]ovsdb.Condition{condition})
+		if err != nil {
+			return nil, err
+		}
+		for rowUUID := range matches {
+			if _, ok := others[rowUUID]; !ok {
+				delete(matches, rowUUID)
+			}
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ovn-org/libovsdb/cache"
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIPreviewTransaction(t *testing.T) {
+	ls := &testLogicalSwitch{
+		UUID: aUUID0,
+		Name: "ls0",
+	}
+	lsp := &testLogicalSwitchPort{
+		UUID: aUUID1,
+		Name: "lsp0",
+	}
+	tcache := apiTestCache(t, cache.Data{
+		"Logical_Switch":      map[string]model.Model{aUUID0: ls},
+		"Logical_Switch_Port": map[string]model.Model{aUUID1: lsp},
+	})
+	api := newAPI(tcache, &discardLogger)
+
+	insertOps, err := api.Create(&testLogicalSwitchPort{
+		UUID: "lsp1",
+		Name: "lsp1",
+	})
+	require.NoError(t, err)
+	update := &testLogicalSwitch{
+		UUID:  aUUID0,
+		Ports: []string{"lsp1"},
+	}
+	updateOps, err := api.Where(update).Update(update, &update.Ports)
+	require.NoError(t, err)
+	deleteOps, err := api.Where(&testLogicalSwitchPort{UUID: aUUID1}).Delete()
+	require.NoError(t, err)
+	ops := append(append(insertOps, updateOps...), deleteOps...)
+	ops = append(ops, ovsdb.Operation{Op: ovsdb.OperationComment, Comment: new(string)})
+
+	deltas, err := api.PreviewTransaction(context.Background(), ops...)
+	require.NoError(t, err)
+	assert.Equal(t, []RowDelta{
+		{
+			Table: "Logical_Switch_Port",
+			UUID:  "lsp1",
+			New:   &testLogicalSwitchPort{UUID: "lsp1", Name: "lsp1"},
+		},
+		{
+			Table: "Logical_Switch",
+			UUID:  aUUID0,
+			Old:   ls,
+			New:   &testLogicalSwitch{UUID: aUUID0, Name: "ls0", Ports: []string{"lsp1"}},
+		},
+		{
+			Table: "Logical_Switch_Port",
+			UUID:  aUUID1,
+			Old:   lsp,
+		},
+	}, deltas)
+
+	// the cache is left untouched
+	assert.Equal(t, ls, tcache.Table("Logical_Switch").Row(aUUID0))
+	assert.Equal(t, lsp, tcache.Table("Logical_Switch_Port").Row(aUUID1))
+	assert.Nil(t, tcache.Table("Logical_Switch_Port").Row("lsp1"))
+
+	t.Run("insert then delete", func(t *testing.T) {
+		ops := append(insertOps, ovsdb.Operation{
+			Op:    ovsdb.OperationDelete,
+			Table: "Logical_Switch_Port",
+			Where: []ovsdb.Condition{ovsdb.NewCondition("_uuid", ovsdb.ConditionEqual, ovsdb.UUID{GoUUID: "lsp1"})},
+		})
+		deltas, err := api.PreviewTransaction(context.Background(), ops...)
+		require.NoError(t, err)
+		assert.Empty(t, deltas)
+	})
+
+	t.Run("index conflict", func(t *testing.T) {
+		ops, err := api.Create(&testLogicalSwitchPort{Name: "lsp0"})
+		require.NoError(t, err)
+		_, err = api.PreviewTransaction(context.Background(), ops...)
+		assert.Error(t, err)
+	})
+
+	t.Run("mutate", func(t *testing.T) {
+		mutated := &testLogicalSwitch{UUID: aUUID0}
+		ops, err := api.Where(mutated).Mutate(mutated, model.Mutation{
+			Field:   &mutated.Ports,
+			Mutator: ovsdb.MutateOperationInsert,
+			Value:   []string{aUUID1},
+		})
+		require.NoError(t, err)
+		_, err = api.PreviewTransaction(context.Background(), ops...)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,303 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/ovn-org/libovsdb/ovsdb"
+)
+
+// ErrTransactionQueued is returned by Transact when the context expires while
+// the transaction is still waiting in the write queue. The transaction is
+// dequeued, and is never sent to the server
+var ErrTransactionQueued = errors.New("transaction queued")
+
+// ErrTransactionReplayed is returned by Transact when a queued transaction,
+// whose reply was lost to a disconnect, is found to have been committed when it
+// is replayed. The replay is a no-op, and the results of the transaction are
+// lost
+var ErrTransactionReplayed = errors.New("queued transaction already committed")
+
+// writeQueueMarkerPrefix prefixes the keys recording the markers of the
+// replayed transactions in the marker column
+const writeQueueMarkerPrefix = "libovsdb-write-queue-"
+
+// queuedTransaction is a transaction submitted while the client was
+// disconnected. The marker identifies the transaction, and is recorded in the
+// database when the transaction commits, so that a replay of a transaction
+// that was already committed is a no-op
+type queuedTransaction struct {
+	marker     string
+	operations []ovsdb.Operation
+	// sent is set, under the mutex of the write queue, once the replay has
+	// started sending the transaction, which can then no longer be canceled
+	sent    bool
+	results []ovsdb.OperationResult
+	err     error
+	done    chan struct{}
+	once    sync.Once
+}
+
+// complete sets the outcome of the transaction and wakes up its caller. Only
+// the first outcome is kept, as the queue may be failed on disconnect while
+// the transaction is being replayed
+func (txn *queuedTransaction) complete(results []ovsdb.OperationResult, err error) {
+	txn.once.Do(func() {
+		txn.results = results
+		txn.err = err
+		close(txn.done)
+	})
+}
+
+// writeQueue holds the transactions waiting to be replayed once connected
+type writeQueue struct {
+	transactions []*queuedTransaction
+	mutex        sync.Mutex
+	// replayMutex ensures only one replay of the queue runs at any given time
+	replayMutex sync.Mutex
+}
+
+func (q *writeQueue) len() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return len(q.transactions)
+}
+
+// remove dequeues the given transaction, if still queued. The caller must hold
+// the mutex of the queue
+func (q *writeQueue) remove(txn *queuedTransaction) {
+	for i, queued := range q.transactions {
+		if queued == txn {
+			q.transactions = append(q.transactions[:i], q.transactions[i+1:]...)
+			return
+		}
+	}
+}
+
+// enqueueTransaction queues the given operations if the client is disconnected
+// or older transactions are still waiting to be replayed. It returns nil if the
+// operations can be sent right away
+func (o *ovsdbClient) enqueueTransaction(operation []ovsdb.Operation) *queuedTransaction {
+	// the rpcMutex is always locked before the mutex of the queue
+	o.rpcMutex.RLock()
+	defer o.rpcMutex.RUnlock()
+	connected := o.rpcClient != nil && o.connected
+	o.writeQueue.mutex.Lock()
+	defer o.writeQueue.mutex.Unlock()
+	if len(o.writeQueue.transactions) == 0 && connected {
+		return nil
+	}
+	txn := &queuedTransaction{
+		marker:     writeQueueMarkerPrefix + uuid.NewString(),
+		operations: operation,
+		done:       make(chan struct{}),
+	}
+	o.writeQueue.transactions = append(o.writeQueue.transactions, txn)
+	o.logger.V(5).Info("queued transaction until reconnected", "marker", txn.marker,
+		"operations", fmt.Sprintf("%+v", operation))
+	return txn
+}
+
+// waitQueuedTransaction waits until the queued transaction has been replayed
+// and returns its results. If the context expires first, the transaction is
+// dequeued unless it has already been sent, in which case its outcome is
+// unknown to the caller, and it stays queued until its replay is known to
+// have committed or not
+func (o *ovsdbClient) waitQueuedTransaction(ctx context.Context, txn *queuedTransaction) ([]ovsdb.OperationResult, error) {
+	select {
+	case <-ctx.Done():
+	case <-txn.done:
+		return txn.results, txn.err
+	}
+	o.writeQueue.mutex.Lock()
+	sent := txn.sent
+	if !sent {
+		o.writeQueue.remove(txn)
+	}
+	o.writeQueue.mutex.Unlock()
+	if sent {
+		return nil, ctx.Err()
+	}
+	err := fmt.Errorf("%w: %v", ErrTransactionQueued, ctx.Err())
+	txn.complete(nil, err)
+	return nil, err
+}
+
+// markerOperations returns the operations guarding the replay of the given
+// transaction: the first ones fail the transaction if its marker is already
+// recorded in the marker column, or if the marker table has no row to record
+// it in, and the last one records it
+func (o *ovsdbClient) markerOperations(txn *queuedTransaction) ([]ovsdb.Operation, ovsdb.Operation, error) {
+	table, column := o.options.writeQueueTable, o.options.writeQueueColumn
+	db := o.databases[o.primaryDBName]
+	db.modelMutex.RLock()
+	tableSchema := db.model.Schema.Table(table)
+	db.modelMutex.RUnlock()
+	if tableSchema == nil {
+		return nil, ovsdb.Operation{}, fmt.Errorf("write queue marker table %s not found", table)
+	}
+	columnSchema := tableSchema.Column(column)
+	if columnSchema == nil || columnSchema.Type != ovsdb.TypeMap ||
+		columnSchema.TypeObj.Key.Type != ovsdb.TypeString || columnSchema.TypeObj.Value.Type != ovsdb.TypeString {
+		return nil, ovsdb.Operation{}, fmt.Errorf("write queue marker column %s of table %s is not a map of strings", column, table)
+	}
+	marker := ovsdb.OvsMap{GoMap: map[interface{}]interface{}{txn.marker: ""}}
+	timeout := 0
+	guards := []ovsdb.Operation{
+		{
+			Op:      ovsdb.OperationWait,
+			Table:   table,
+			Timeout: &timeout,
+			Where:   []ovsdb.Condition{ovsdb.NewCondition(column, ovsdb.ConditionIncludes, marker)},
+			Columns: []string{},
+			Until:   string(ovsdb.WaitConditionEqual),
+			Rows:    []ovsdb.Row{},
+		},
+		{
+			Op:      ovsdb.OperationWait,
+			Table:   table,
+			Timeout: &timeout,
+			Where:   []ovsdb.Condition{},
+			Columns: []string{},
+			Until:   string(ovsdb.WaitConditionNotEqual),
+			Rows:    []ovsdb.Row{},
+		},
+	}
+	record := ovsdb.Operation{
+		Op:        ovsdb.OperationMutate,
+		Table:     table,
+		Where:     []ovsdb.Condition{},
+		Mutations: []ovsdb.Mutation{*ovsdb.NewMutation(column, ovsdb.MutateOperationInsert, marker)},
+	}
+	return guards, record, nil
+}
+
+// replayWriteQueue sends the queued transactions in order. It is started at the
+// end of every successful connect, and stops as soon as the connection is lost,
+// leaving the remaining transactions queued for the next one. A transaction
+// whose reply is lost stays queued, and is replayed on the next connection:
+// the marker it records when it commits makes the replay a no-op if it was
+// committed already, so that it is committed exactly once
+func (o *ovsdbClient) replayWriteQueue() {
+	o.writeQueue.replayMutex.Lock()
+	defer o.writeQueue.replayMutex.Unlock()
+	for {
+		o.rpcMutex.RLock()
+		if o.rpcClient == nil || !o.connected {
+			o.rpcMutex.RUnlock()
+			return
+		}
+		o.writeQueue.mutex.Lock()
+		if len(o.writeQueue.transactions) == 0 {
+			o.writeQueue.mutex.Unlock()
+			o.rpcMutex.RUnlock()
+			return
+		}
+		// the transaction stays queued while it is sent, so that transactions
+		// submitted meanwhile are queued behind it
+		txn := o.writeQueue.transactions[0]
+		txn.sent = true
+		o.writeQueue.mutex.Unlock()
+
+		guards, record, err := o.markerOperations(txn)
+		if err != nil {
+			o.rpcMutex.RUnlock()
+			o.completeQueuedTransaction(txn, nil, err)
+			continue
+		}
+		operation := append(append(guards, txn.operations...), record)
+		o.logger.V(3).Info("replaying queued transaction", "marker", txn.marker)
+		results, err := o.transact(context.Background(), o.primaryDBName, operation...)
+		o.rpcMutex.RUnlock()
+		if errors.Is(err, ErrNotConnected) {
+			// the transaction may have been committed, it is replayed on the
+			// next connection
+			o.logger.V(3).Info("lost the reply of a queued transaction", "marker", txn.marker)
+			return
+		}
+		if err != nil {
+			o.completeQueuedTransaction(txn, nil, err)
+			continue
+		}
+		replayed, err := checkMarkerGuards(results, guards)
+		if replayed {
+			o.removeMarker(txn)
+			o.completeQueuedTransaction(txn, nil, ErrTransactionReplayed)
+			continue
+		}
+		if err != nil {
+			o.completeQueuedTransaction(txn, nil, err)
+			continue
+		}
+		results = results[len(guards):]
+		if len(results) > len(txn.operations) {
+			// drop the result of the recording of the marker, keeping the error
+			// of the commit that may follow it
+			results = append(results[:len(txn.operations):len(txn.operations)], results[len(txn.operations)+1:]...)
+		}
+		if _, err := ovsdb.CheckOperationResults(results, txn.operations); err == nil {
+			o.removeMarker(txn)
+		}
+		o.completeQueuedTransaction(txn, results, nil)
+	}
+}
+
+// checkMarkerGuards checks the results of the operations guarding the replay of
+// a transaction. It reports whether the marker of the transaction is already
+// recorded, in which case the transaction was committed by an earlier replay
+func checkMarkerGuards(results []ovsdb.OperationResult, guards []ovsdb.Operation) (bool, error) {
+	if len(results) < len(guards) {
+		return false, fmt.Errorf("no result for the guards of the queued transaction")
+	}
+	errs, _ := ovsdb.CheckOperationResults(results[:len(guards)], guards)
+	for i, err := range errs {
+		var timedOut *ovsdb.TimedOut
+		if !errors.As(err, &timedOut) {
+			return false, err
+		}
+		if i == 0 {
+			return true, nil
+		}
+		return false, fmt.Errorf("no row in write queue marker table %s", guards[i].Table)
+	}
+	return false, nil
+}
+
+// removeMarker removes the marker of a transaction that is known to have been
+// committed, so that the markers do not pile up in the marker column. It is
+// left behind if the removal fails
+func (o *ovsdbClient) removeMarker(txn *queuedTransaction) {
+	keys := ovsdb.OvsSet{GoSet: []interface{}{txn.marker}}
+	o.rpcMutex.RLock()
+	defer o.rpcMutex.RUnlock()
+	_, err := o.transact(context.Background(), o.primaryDBName, ovsdb.Operation{
+		Op:        ovsdb.OperationMutate,
+		Table:     o.options.writeQueueTable,
+		Where:     []ovsdb.Condition{},
+		Mutations: []ovsdb.Mutation{*ovsdb.NewMutation(o.options.writeQueueColumn, ovsdb.MutateOperationDelete, keys)},
+	})
+	if err != nil {
+		o.logger.V(3).Info("failed to remove the marker of a queued transaction", "marker", txn.marker, "error", err.Error())
+	}
+}
+
+// failWriteQueue dequeues all the queued transactions with the given error
+func (o *ovsdbClient) failWriteQueue(err error) {
+	o.writeQueue.mutex.Lock()
+	transactions := o.writeQueue.transactions
+	o.writeQueue.transactions = nil
+	o.writeQueue.mutex.Unlock()
+	for _, txn := range transactions {
+		txn.complete(nil, err)
+	}
+}
+
+func (o *ovsdbClient) completeQueuedTransaction(txn *queuedTransaction, results []ovsdb.OperationResult, err error) {
+	o.writeQueue.mutex.Lock()
+	o.writeQueue.remove(txn)
+	o.writeQueue.mutex.Unlock()
+	txn.complete(results, err)
+}
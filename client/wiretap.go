@@ -0,0 +1,145 @@
+package client
+
+import (
+	"net"
+	"sync"
+)
+
+// Direction is the direction of a message exchanged with the server
+type Direction int
+
+const (
+	// Inbound is a message received from the server
+	Inbound Direction = iota
+	// Outbound is a message sent to the server
+	Outbound
+)
+
+func (d Direction) String() string {
+	if d == Inbound {
+		return "inbound"
+	}
+	return "outbound"
+}
+
+// WireTap is called with the raw bytes of a message exchanged with the
+// server. It is set with WithWireTap
+type WireTap func(direction Direction, data []byte)
+
+// wireTapBuffer is the number of messages waiting to be delivered to the
+// WireTap beyond which the messages are dropped
+const wireTapBuffer = 256
+
+type tappedMessage struct {
+	direction Direction
+	data      []byte
+}
+
+// tapConn is a connection delivering the messages read from and written to
+// it to a WireTap. The messages are delivered by a goroutine of their own, so
+// that a slow tap never blocks the reads and writes
+type tapConn struct {
+	net.Conn
+	messages  chan tappedMessage
+	done      chan struct{}
+	closeOnce sync.Once
+	inbound   messageSplitter
+	outbound  messageSplitter
+	// the splitters are guarded by their own mutex, reads and writes being
+	// concurrent
+	readMutex  sync.Mutex
+	writeMutex sync.Mutex
+}
+
+func newTapConn(conn net.Conn, tap WireTap) *tapConn {
+	c := &tapConn{
+		Conn:     conn,
+		messages: make(chan tappedMessage, wireTapBuffer),
+		done:     make(chan struct{}),
+	}
+	go c.deliver(tap)
+	return c
+}
+
+func (c *tapConn) deliver(tap WireTap) {
+	for {
+		select {
+		case m := <-c.messages:
+			tap(m.direction, m.data)
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// tap queues a message for the WireTap, dropping it if the tap lags behind
+func (c *tapConn) tap(direction Direction, data []byte) {
+	select {
+	case c.messages <- tappedMessage{direction: direction, data: data}:
+	default:
+	}
+}
+
+func (c *tapConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.readMutex.Lock()
+		c.inbound.feed(b[:n], func(data []byte) { c.tap(Inbound, data) })
+		c.readMutex.Unlock()
+	}
+	return n, err
+}
+
+func (c *tapConn) Write(b []byte) (int, error) {
+	c.writeMutex.Lock()
+	c.outbound.feed(b, func(data []byte) { c.tap(Outbound, data) })
+	c.writeMutex.Unlock()
+	return c.Conn.Write(b)
+}
+
+func (c *tapConn) Close() error {
+	c.closeOnce.Do(func() { close(c.done) })
+	return c.Conn.Close()
+}
+
+// messageSplitter splits a stream of JSON-RPC messages into the messages it
+// holds, which may span several reads and writes, by tracking the nesting of
+// the JSON values
+type messageSplitter struct {
+	message  []byte
+	depth    int
+	inString bool
+	escaped  bool
+}
+
+// feed consumes b, calling emit with a copy of every message it completes
+func (s *messageSplitter) feed(b []byte, emit func([]byte)) {
+	for _, c := range b {
+		if s.depth == 0 && c != '{' && c != '[' {
+			// whitespace between the messages
+			continue
+		}
+		s.message = append(s.message, c)
+		switch {
+		case s.escaped:
+			s.escaped = false
+		case s.inString:
+			switch c {
+			case '\\':
+				s.escaped = true
+			case '"':
+				s.inString = false
+			}
+		case c == '"':
+			s.inString = true
+		case c == '{' || c == '[':
+			s.depth++
+		case c == '}' || c == ']':
+			s.depth--
+			if s.depth == 0 {
+				emit(s.message)
+				s.message = nil
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageSplitter(t *testing.T) {
+	var messages []string
+	emit := func(data []byte) {
+		messages = append(messages, string(data))
+	}
+	var s messageSplitter
+	stream := `{"id":1,"method":"echo","params":["}\"{",[]]}` + "\n" + `{"id":2,"result":{"a":[1,2]},"error":null}`
+	// messages spanning several chunks
+	for i := 0; i < len(stream); i += 7 {
+		end := i + 7
+		if end > len(stream) {
+			end = len(stream)
+		}
+		s.feed([]byte(stream[i:end]), emit)
+	}
+	assert.Equal(t, []string{
+		`{"id":1,"method":"echo","params":["}\"{",[]]}`,
+		`{"id":2,"result":{"a":[1,2]},"error":null}`,
+	}, messages)
+}
+
+func TestClientWireTap(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	var defSchema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(schema), &defSchema)
+	require.NoError(t, err)
+	_, sock := newOVSDBServer(t, defDB, defSchema)
+
+	type message struct {
+		direction Direction
+		data      []byte
+	}
+	var messages []message
+	var mutex sync.Mutex
+	ovs, err := newOVSDBClient(defDB,
+		WithEndpoint(fmt.Sprintf("unix:%s", sock)),
+		WithWireTap(func(direction Direction, data []byte) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			messages = append(messages, message{direction, data})
+		}))
+	require.NoError(t, err)
+	err = ovs.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(ovs.Close)
+
+	// the list_dbs request sent on connection, and its reply
+	type rpc struct {
+		ID     interface{}     `json:"id"`
+		Method string          `json:"method"`
+		Result json.RawMessage `json:"result"`
+	}
+	assert.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		var request *rpc
+		for _, m := range messages {
+			var r rpc
+			if err := json.Unmarshal(m.data, &r); err != nil {
+				t.Errorf("invalid message %s: %v", m.data, err)
+				return false
+			}
+			switch {
+			case m.direction == Outbound && r.Method == "list_dbs":
+				request = &r
+			case m.direction == Inbound && request != nil && r.ID == request.ID:
+				var dbs []string
+				if err := json.Unmarshal(r.Result, &dbs); err != nil {
+					t.Errorf("invalid reply %s: %v", m.data, err)
+					return false
+				}
+				return assert.Contains(t, dbs, defDB.Name())
+			}
+		}
+		return false
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestWithWireTap(t *testing.T) {
+	opts := &options{}
+	err := WithWireTap(func(Direction, []byte) {})(opts)
+	require.NoError(t, err)
+	assert.NotNil(t, opts.wireTap)
+
+	err = WithWireTap(nil)(opts)
+	assert.Error(t, err)
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/ovn-org/libovsdb/modelgen"
 	"github.com/ovn-org/libovsdb/ovsdb"
@@ -25,12 +26,56 @@ var (
 	pkgNameP = flag.String("p", "ovsmodel", "Package name")
 	dryRun   = flag.Bool("d", false, "Dry run")
 	extended = flag.Bool("extended", false, "Generates additional code like deep-copy methods, etc.")
+	split    = flag.Bool("split", false, "Splits the code of each table into types, methods and helpers files")
+	single   = flag.Bool("single", false, "Generates the code of all the tables into a single file, tables.go")
+	jsonTags = flag.Bool("json", false, "Adds json tags named after the columns to the struct fields")
+	deepCopy = flag.Bool("deepcopy", false, "Generates DeepCopy methods, which --extended also does")
+	equals   = flag.Bool("equals", false, "Generates Equals methods, which --extended also does")
+	enumStr  = flag.Bool("enum-stringer", false, "Generates the enums as defined types with a String method instead of aliases")
+	initials = flag.String("initialisms", "", "Comma-separated list of additional initialisms kept upper case in the names, like VTEP,GRE")
+	removed  = flag.String("remove-initialisms", "", "Comma-separated list of common initialisms not kept upper case in the names, like ID")
+	prefix   = flag.String("prefix", "", "Prefix of the names of the table structs, like Nb, to avoid collisions between databases")
+	buildTag = flag.String("build-tag", "", "Build tag of the files holding the methods of the -tagged templates")
+	tagged   = flag.String("tagged", "", "Comma-separated list of templates whose methods are generated behind -build-tag, like stringer,builder")
 )
 
+// generators are the flags turning on a single generator each, on top of the
+// ones turned on by --extended
+var generators = []struct {
+	name   string
+	usage  string
+	option func() modelgen.Option
+}{
+	{"column-schema", "Generates a ColumnSchema method returning the schema of a column", modelgen.WithColumnSchema},
+	{"builder", "Generates builders constructing the models with method chaining", modelgen.WithBuilder},
+	{"field-column-maps", "Generates maps from the struct field names to the column names, and back", modelgen.WithFieldColumnMaps},
+	{"apply-patch", "Generates an ApplyPatch method copying the fields set in a patch model, which requires --extended", modelgen.WithApplyPatch},
+	{"stringer", "Generates a String method printing the fields of each model", modelgen.WithStringer},
+	{"text-marshaler", "Generates MarshalText and UnmarshalText methods rendering and parsing the UUID of each model", modelgen.WithTextMarshaler},
+	{"insert-row-minimal", "Generates an InsertRowMinimal method leaving out the columns holding their default value", modelgen.WithInsertRowMinimal},
+	{"enum-exhaustiveness", "Generates a map holding all the members of each enum", modelgen.WithEnumExhaustiveness},
+	{"enum-validation", "Generates a function reporting whether a value is a member of each enum", modelgen.WithEnumValidation},
+	{"column-types", "Generates a map holding the type of each column", modelgen.WithColumnTypes},
+	{"enum-columns", "Generates a map holding the members of each enum column", modelgen.WithEnumColumns},
+	{"copy-common-fields", "Generates a CopyCommonFields method copying the fields of a model into a model of another table, which requires --extended", modelgen.WithCopyCommonFields},
+	{"enum-predicates", "Generates predicates reporting whether the single valued string enum fields hold each member", modelgen.WithEnumPredicates},
+	{"model-methods", "Generates GetUUID and Table methods returning the UUID of a model and the name of its table", modelgen.WithModelMethods},
+	{"map-merge", "Generates methods setting keys in the map fields while keeping their other keys", modelgen.WithMapMerge},
+	{"optional-getters", "Generates getters returning the value of the optional fields and whether they are set", modelgen.WithOptionalGetters},
+	{"cardinality-validation", "Generates a Validate method checking the number of elements of the set and map fields", modelgen.WithCardinalityValidation},
+	{"from-map", "Generates functions building the models from the values of their columns, without reflection", modelgen.WithFromMap},
+	{"columns", "Generates a Columns method returning the names of all the columns of the table", modelgen.WithColumns},
+	{"reference-validation", "Generates a ValidateReferences method checking the references of each model against a cache", modelgen.WithReferenceValidation},
+}
+
 func main() {
 	log.SetFlags(0)
 	log.SetPrefix("modelgen: ")
 	flag.Usage = usage
+	enabled := make([]*bool, len(generators))
+	for i, g := range generators {
+		enabled[i] = flag.Bool(g.name, false, g.usage)
+	}
 	flag.Parse()
 	outDir := *outDirP
 	pkgName := *pkgNameP
@@ -49,6 +94,12 @@ func main() {
 		flag.Usage()
 		os.Exit(2)
 	}
+	if *split && *single {
+		log.Fatal("-split and -single are mutually exclusive")
+	}
+	if (*buildTag == "") != (*tagged == "") {
+		log.Fatal("-build-tag and -tagged must be used together")
+	}
 
 	schemaFile, err := os.Open(flag.Args()[0])
 	if err != nil {
@@ -66,6 +117,18 @@ func main() {
 		log.Fatal(err)
 	}
 
+	initialisms := map[string]bool{}
+	if *initials != "" {
+		for _, initialism := range strings.Split(*initials, ",") {
+			initialisms[strings.TrimSpace(initialism)] = true
+		}
+	}
+	if *removed != "" {
+		for _, initialism := range strings.Split(*removed, ",") {
+			initialisms[strings.TrimSpace(initialism)] = false
+		}
+	}
+
 	genOpts := []modelgen.Option{}
 	if *dryRun {
 		genOpts = append(genOpts, modelgen.WithDryRun())
@@ -74,17 +137,108 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
-	for name, table := range dbSchema.Tables {
-		tmpl := modelgen.NewTableTemplate()
-		args := modelgen.GetTableTemplateData(pkgName, name, &table)
-		args.WithExtendedGen(*extended)
-		if err := gen.Generate(filepath.Join(outDir, modelgen.FileName(name)), tmpl, args); err != nil {
+	nameOpts := []modelgen.Option{modelgen.WithStructNamePrefix(*prefix), modelgen.WithInitialisms(initialisms)}
+	tableOpts := append([]modelgen.Option{}, nameOpts...)
+	if *extended {
+		tableOpts = append(tableOpts, modelgen.WithExtendedGen())
+	}
+	for i, g := range generators {
+		if *enabled[i] {
+			tableOpts = append(tableOpts, g.option())
+		}
+	}
+	if *jsonTags {
+		tableOpts = append(tableOpts, modelgen.WithJSONTags())
+	}
+	if *deepCopy {
+		tableOpts = append(tableOpts, modelgen.WithDeepCopy())
+	}
+	if *equals {
+		tableOpts = append(tableOpts, modelgen.WithEquals())
+	}
+	if *enumStr {
+		tableOpts = append(tableOpts, modelgen.WithEnumStringer())
+	}
+	if *buildTag != "" {
+		taggable := map[string]bool{}
+		for _, name := range modelgen.TaggableTemplates() {
+			taggable[name] = true
+		}
+		var templates []string
+		for _, name := range strings.Split(*tagged, ",") {
+			name = strings.TrimSpace(name)
+			if !taggable[name] {
+				log.Fatalf("template %s cannot be tagged, use one of %s", name, strings.Join(modelgen.TaggableTemplates(), ","))
+			}
+			templates = append(templates, name)
+		}
+		tableOpts = append(tableOpts, modelgen.WithBuildTag(*buildTag, templates...))
+	}
+	if *single {
+		tmpl := modelgen.NewTablesTemplate()
+		args, err := modelgen.GetTablesTemplateData(pkgName, dbSchema, tableOpts...)
+		if err != nil {
 			log.Fatal(err)
 		}
+		if err := gen.Generate(filepath.Join(outDir, "tables.go"), tmpl, args); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		generateTables(gen, outDir, pkgName, dbSchema, tableOpts)
+	}
+	if !*split {
+		generateTaggedParts(gen, outDir, pkgName, dbSchema, tableOpts)
 	}
 	dbTemplate := modelgen.NewDBTemplate()
-	dbArgs := modelgen.GetDBTemplateData(pkgName, dbSchema)
+	dbArgs, err := modelgen.GetDBTemplateData(pkgName, dbSchema, nameOpts...)
+	if err != nil {
+		log.Fatal(err)
+	}
 	if err := gen.Generate(filepath.Join(outDir, "model.go"), dbTemplate, dbArgs); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// generateTables generates the code of each table into its own files
+func generateTables(gen modelgen.Generator, outDir, pkgName string, dbSchema ovsdb.DatabaseSchema, tableOpts []modelgen.Option) {
+	for name, table := range dbSchema.Tables {
+		tmpl := modelgen.NewTableTemplate()
+		args, err := modelgen.GetTableTemplateData(pkgName, name, &table, tableOpts...)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !*split {
+			if err := gen.Generate(filepath.Join(outDir, modelgen.FileName(name)), tmpl, args); err != nil {
+				log.Fatal(err)
+			}
+			continue
+		}
+		for _, part := range args.Parts() {
+			args.WithPart(part)
+			if err := gen.Generate(filepath.Join(outDir, modelgen.PartFileName(name, part)), tmpl, args); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+}
+
+// generateTaggedParts generates the tagged part of the code of each table
+// into its own file, when the code of the tables is not split
+func generateTaggedParts(gen modelgen.Generator, outDir, pkgName string, dbSchema ovsdb.DatabaseSchema, tableOpts []modelgen.Option) {
+	for name, table := range dbSchema.Tables {
+		tmpl := modelgen.NewTableTemplate()
+		args, err := modelgen.GetTableTemplateData(pkgName, name, &table, tableOpts...)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, part := range args.Parts() {
+			if part != modelgen.TableTaggedPart {
+				continue
+			}
+			args.WithPart(part)
+			if err := gen.Generate(filepath.Join(outDir, modelgen.PartFileName(name, part)), tmpl, args); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+}
@@ -1,3 +1,3 @@
 package vswitchd
 
-//go:generate ../../bin/modelgen --extended -p vswitchd -o . ovs.ovsschema
+//go:generate ../../bin/modelgen --extended --column-schema --builder --field-column-maps --apply-patch --stringer --text-marshaler --insert-row-minimal --enum-exhaustiveness --enum-validation --column-types --enum-columns --copy-common-fields --enum-predicates --model-methods --map-merge --optional-getters --cardinality-validation --from-map --columns --reference-validation -p vswitchd -o . ovs.ovsschema
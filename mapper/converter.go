@@ -0,0 +1,59 @@
+package mapper
+
+import (
+	"reflect"
+)
+
+// Converter converts between the native type of a column and the type of
+// the model field it is mapped to, allowing models to hold columns in types
+// that are more convenient than the native ones, such as a net.IPNet for a
+// string column holding a CIDR
+type Converter interface {
+	// FieldType returns the type of the model fields the converter handles
+	FieldType() reflect.Type
+	// ToNative converts a field value to the native type of the column
+	ToNative(value interface{}) (interface{}, error)
+	// FromNative converts a native value of the column to the field type
+	FromNative(value interface{}) (interface{}, error)
+}
+
+// Converters is a registry of Converters, either bound to a specific column
+// of a table or to every field of a given type. Column converters take
+// precedence over type converters
+type Converters struct {
+	columns map[string]map[string]Converter
+	types   map[reflect.Type]Converter
+}
+
+// NewConverters returns an empty registry of Converters
+func NewConverters() *Converters {
+	return &Converters{
+		columns: make(map[string]map[string]Converter),
+		types:   make(map[reflect.Type]Converter),
+	}
+}
+
+// SetColumnConverter registers a Converter for the given column of a table
+func (c *Converters) SetColumnConverter(table, column string, converter Converter) {
+	if _, ok := c.columns[table]; !ok {
+		c.columns[table] = make(map[string]Converter)
+	}
+	c.columns[table][column] = converter
+}
+
+// SetTypeConverter registers a Converter for every field of its FieldType
+func (c *Converters) SetTypeConverter(converter Converter) {
+	c.types[converter.FieldType()] = converter
+}
+
+// lookup returns the Converter to use for a field of the given type mapped to
+// the given column, or nil if there is none
+func (c *Converters) lookup(table, column string, fieldType reflect.Type) Converter {
+	if c == nil {
+		return nil
+	}
+	if converter, ok := c.columns[table][column]; ok {
+		return converter
+	}
+	return c.types[fieldType]
+}
@@ -16,9 +16,10 @@ type Info struct {
 
 // Metadata represents the information needed to know how to map OVSDB columns into an objetss fields
 type Metadata struct {
-	Fields      map[string]string  // Map of ColumnName -> FieldName
-	TableSchema *ovsdb.TableSchema // TableSchema associated
-	TableName   string             // Table name
+	Fields      map[string]string    // Map of ColumnName -> FieldName
+	TableSchema *ovsdb.TableSchema   // TableSchema associated
+	TableName   string               // Table name
+	Converters  map[string]Converter // Map of ColumnName -> Converter, for fields not of the native type
 }
 
 // FieldByColumn returns the field value that corresponds to a column
@@ -27,7 +28,15 @@ func (i *Info) FieldByColumn(column string) (interface{}, error) {
 	if !ok {
 		return nil, fmt.Errorf("FieldByColumn: column %s not found in orm info", column)
 	}
-	return reflect.ValueOf(i.Obj).Elem().FieldByName(fieldName).Interface(), nil
+	value := reflect.ValueOf(i.Obj).Elem().FieldByName(fieldName).Interface()
+	if converter, ok := i.Metadata.Converters[column]; ok {
+		native, err := converter.ToNative(value)
+		if err != nil {
+			return nil, fmt.Errorf("FieldByColumn: column %s: %w", column, err)
+		}
+		return native, nil
+	}
+	return value, nil
 }
 
 // FieldByColumn returns the field value that corresponds to a column
@@ -44,6 +53,14 @@ func (i *Info) SetField(column string, value interface{}) error {
 	}
 	fieldValue := reflect.ValueOf(i.Obj).Elem().FieldByName(fieldName)
 
+	if converter, ok := i.Metadata.Converters[column]; ok {
+		converted, err := converter.FromNative(value)
+		if err != nil {
+			return fmt.Errorf("SetField: column %s: %w", column, err)
+		}
+		value = converted
+	}
+
 	if !fieldValue.Type().AssignableTo(reflect.TypeOf(value)) {
 		return fmt.Errorf("column %s: native value %v (%s) is not assignable to field %s (%s)",
 			column, value, reflect.TypeOf(value), fieldName, fieldValue.Type())
@@ -52,6 +69,42 @@ func (i *Info) SetField(column string, value interface{}) error {
 	return nil
 }
 
+// ovsByColumn returns the OVS value of the field that corresponds to a column.
+// Unless withDefault is set, fields holding the default value of the column
+// are skipped, and false is returned
+func (i *Info) ovsByColumn(column string, schema *ovsdb.ColumnSchema, withDefault bool) (interface{}, bool, error) {
+	if converter, ok := i.Metadata.Converters[column].(*integerConverter); ok {
+		// the integers are read from the field itself, as they may not fit in
+		// the native int of 32-bit platforms
+		value := reflect.ValueOf(i.Obj).Elem().FieldByName(i.Metadata.Fields[column]).Interface()
+		if !withDefault && converter.isDefault(schema, value) {
+			return nil, false, nil
+		}
+		ovsElem, err := converter.toOvs(schema, value)
+		return ovsElem, err == nil, err
+	}
+	nativeElem, err := i.FieldByColumn(column)
+	if err != nil {
+		return nil, false, err
+	}
+	if !withDefault && ovsdb.IsDefaultValue(schema, nativeElem) {
+		return nil, false, nil
+	}
+	ovsElem, err := ovsdb.NativeToOvs(schema, nativeElem)
+	return ovsElem, err == nil, err
+}
+
+// integersToNative converts a value of the type of a field holding the
+// integers of a column as int64 to the native type of the column, so it can
+// be used in conditions and mutations. Other values are returned as is
+func (i *Info) integersToNative(column string, value interface{}) (interface{}, error) {
+	converter, ok := i.Metadata.Converters[column].(*integerConverter)
+	if !ok || value == nil || reflect.TypeOf(value) != converter.FieldType() {
+		return value, nil
+	}
+	return converter.ToNative(value)
+}
+
 // ColumnByPtr returns the column name that corresponds to the field by the field's pointer
 func (i *Info) ColumnByPtr(fieldPtr interface{}) (string, error) {
 	fieldPtrVal := reflect.ValueOf(fieldPtr)
@@ -107,6 +160,14 @@ OUTER:
 
 // NewInfo creates a MapperInfo structure around an object based on a given table schema
 func NewInfo(tableName string, table *ovsdb.TableSchema, obj interface{}) (*Info, error) {
+	return NewInfoWithConverters(tableName, table, obj, nil)
+}
+
+// NewInfoWithConverters is like NewInfo, but fields whose type differs from the
+// native type of their column are accepted if the registry has a Converter for
+// them, which is then used to access those fields. Fields holding the integers
+// of a column as int64 instead of int are accepted without a Converter
+func NewInfoWithConverters(tableName string, table *ovsdb.TableSchema, obj interface{}, converters *Converters) (*Info, error) {
 	objPtrVal := reflect.ValueOf(obj)
 	if objPtrVal.Type().Kind() != reflect.Ptr {
 		return nil, ovsdb.NewErrWrongType("NewMapperInfo", "pointer to a struct", obj)
@@ -118,6 +179,7 @@ func NewInfo(tableName string, table *ovsdb.TableSchema, obj interface{}) (*Info
 	objType := objVal.Type()
 
 	fields := make(map[string]string, objType.NumField())
+	var columnConverters map[string]Converter
 	for i := 0; i < objType.NumField(); i++ {
 		field := objType.Field(i)
 		colName := field.Tag.Get("ovsdb")
@@ -138,7 +200,26 @@ func NewInfo(tableName string, table *ovsdb.TableSchema, obj interface{}) (*Info
 
 		// Perform schema-based type checking
 		expType := ovsdb.NativeType(column)
-		if expType != field.Type {
+		if converter := converters.lookup(tableName, colName, field.Type); converter != nil {
+			if converter.FieldType() != field.Type {
+				return nil, &ErrMapper{
+					objType:   objType.String(),
+					field:     field.Name,
+					fieldType: field.Type.String(),
+					fieldTag:  colName,
+					reason:    fmt.Sprintf("Wrong type, column converter expects %s", converter.FieldType()),
+				}
+			}
+			if columnConverters == nil {
+				columnConverters = make(map[string]Converter)
+			}
+			columnConverters[colName] = converter
+		} else if converter := newIntegerConverter(expType, field.Type); converter != nil {
+			if columnConverters == nil {
+				columnConverters = make(map[string]Converter)
+			}
+			columnConverters[colName] = converter
+		} else if expType != field.Type {
 			return nil, &ErrMapper{
 				objType:   objType.String(),
 				field:     field.Name,
@@ -156,6 +237,7 @@ func NewInfo(tableName string, table *ovsdb.TableSchema, obj interface{}) (*Info
 			Fields:      fields,
 			TableSchema: table,
 			TableName:   tableName,
+			Converters:  columnConverters,
 		},
 	}, nil
 }
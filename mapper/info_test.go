@@ -3,6 +3,8 @@ package mapper
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/ovn-org/libovsdb/ovsdb"
@@ -369,3 +371,69 @@ func TestOrmGetIndex(t *testing.T) {
 		})
 	}
 }
+
+// quotedConverter maps a string column to a field holding the quoted string
+type quotedConverter struct{}
+
+type quoted string
+
+func (quotedConverter) FieldType() reflect.Type {
+	return reflect.TypeOf(quoted(""))
+}
+
+func (quotedConverter) ToNative(value interface{}) (interface{}, error) {
+	return strconv.Unquote(string(value.(quoted)))
+}
+
+func (quotedConverter) FromNative(value interface{}) (interface{}, error) {
+	return quoted(strconv.Quote(value.(string))), nil
+}
+
+func TestNewMapperInfoWithConverters(t *testing.T) {
+	var table ovsdb.TableSchema
+	err := json.Unmarshal(sampleTable, &table)
+	assert.Nil(t, err)
+
+	type obj struct {
+		AString quoted `ovsdb:"aString"`
+		AInt    int    `ovsdb:"aInteger"`
+	}
+
+	_, err = NewInfo("Test", &table, &obj{})
+	assert.NotNil(t, err)
+
+	for name, converters := range map[string]func() *Converters{
+		"column": func() *Converters {
+			c := NewConverters()
+			c.SetColumnConverter("Test", "aString", quotedConverter{})
+			return c
+		},
+		"type": func() *Converters {
+			c := NewConverters()
+			c.SetTypeConverter(quotedConverter{})
+			return c
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			o := &obj{AString: `"foo"`}
+			info, err := NewInfoWithConverters("Test", &table, o, converters())
+			assert.Nil(t, err)
+
+			value, err := info.FieldByColumn("aString")
+			assert.Nil(t, err)
+			assert.Equal(t, "foo", value)
+
+			err = info.SetField("aString", "bar")
+			assert.Nil(t, err)
+			assert.Equal(t, quoted(`"bar"`), o.AString)
+		})
+	}
+
+	t.Run("wrong field type", func(t *testing.T) {
+		c := NewConverters()
+		c.SetTypeConverter(quotedConverter{})
+		c.SetColumnConverter("Test", "aInteger", quotedConverter{})
+		_, err := NewInfoWithConverters("Test", &table, &obj{}, c)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,322 @@
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+)
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// integerConverter maps the integers of a column, whose native type is int,
+// to a field holding them as int64, the size of the OVSDB integers, along with
+// the pointers, slices, arrays and maps of them. It also maps the values of a
+// column to a field of a type defined over their native type implementing
+// fmt.Stringer, like the enum types modelgen generates with WithEnumStringer.
+// It is used for such fields without being registered. The rows are decoded
+// into and encoded from the field directly, so that integers that do not fit
+// in the int of 32-bit platforms are not truncated
+type integerConverter struct {
+	fieldType  reflect.Type
+	nativeType reflect.Type
+}
+
+// newIntegerConverter returns an integerConverter between the native type of
+// a column and the type of a field, or nil unless the field type only differs
+// from the native type by holding int64 where the native type holds int, or
+// types implementing fmt.Stringer defined over the types the native type holds
+func newIntegerConverter(nativeType, fieldType reflect.Type) *integerConverter {
+	if nativeType == fieldType || !integerCompatible(nativeType, fieldType) {
+		return nil
+	}
+	return &integerConverter{fieldType: fieldType, nativeType: nativeType}
+}
+
+func integerCompatible(nativeType, fieldType reflect.Type) bool {
+	if nativeType == fieldType {
+		return true
+	}
+	if nativeType.Kind() != fieldType.Kind() {
+		return nativeType.Kind() == reflect.Int && fieldType.Kind() == reflect.Int64
+	}
+	switch nativeType.Kind() {
+	case reflect.Ptr, reflect.Slice:
+		return integerCompatible(nativeType.Elem(), fieldType.Elem())
+	case reflect.Array:
+		return nativeType.Len() == fieldType.Len() && integerCompatible(nativeType.Elem(), fieldType.Elem())
+	case reflect.Map:
+		return integerCompatible(nativeType.Key(), fieldType.Key()) && integerCompatible(nativeType.Elem(), fieldType.Elem())
+	case reflect.String, reflect.Int, reflect.Float64, reflect.Bool:
+		// a type defined over the native type, which opts in by implementing
+		// fmt.Stringer, unlike the types needing a Converter
+		return fieldType.Implements(stringerType)
+	}
+	return false
+}
+
+func (c *integerConverter) FieldType() reflect.Type {
+	return c.fieldType
+}
+
+// ToNative converts a field value to the native type of the column. It fails
+// if an integer does not fit in an int
+func (c *integerConverter) ToNative(value interface{}) (interface{}, error) {
+	native, err := convertIntegers(reflect.ValueOf(value), c.nativeType)
+	if err != nil {
+		return nil, err
+	}
+	return native.Interface(), nil
+}
+
+// FromNative converts a native value of the column to the field type. Values
+// already of the field type are returned as is
+func (c *integerConverter) FromNative(value interface{}) (interface{}, error) {
+	field, err := convertIntegers(reflect.ValueOf(value), c.fieldType)
+	if err != nil {
+		return nil, err
+	}
+	return field.Interface(), nil
+}
+
+// convertIntegers converts a value to a type holding the same elements, with
+// integers of another size
+func convertIntegers(value reflect.Value, to reflect.Type) (reflect.Value, error) {
+	if value.Type() == to {
+		return value, nil
+	}
+	switch to.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return reflect.Zero(to), nil
+		}
+		elem, err := convertIntegers(value.Elem(), to.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(to.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	case reflect.Slice:
+		if value.IsNil() {
+			return reflect.Zero(to), nil
+		}
+		slice := reflect.MakeSlice(to, value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			elem, err := convertIntegers(value.Index(i), to.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			slice.Index(i).Set(elem)
+		}
+		return slice, nil
+	case reflect.Array:
+		array := reflect.New(to).Elem()
+		for i := 0; i < value.Len(); i++ {
+			elem, err := convertIntegers(value.Index(i), to.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			array.Index(i).Set(elem)
+		}
+		return array, nil
+	case reflect.Map:
+		if value.IsNil() {
+			return reflect.Zero(to), nil
+		}
+		m := reflect.MakeMapWithSize(to, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			k, err := convertIntegers(iter.Key(), to.Key())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			v, err := convertIntegers(iter.Value(), to.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			m.SetMapIndex(k, v)
+		}
+		return m, nil
+	case reflect.Int:
+		if reflect.Zero(to).OverflowInt(value.Int()) {
+			return reflect.Value{}, fmt.Errorf("integer %d does not fit in %s", value.Int(), to)
+		}
+	}
+	return value.Convert(to), nil
+}
+
+// isDefault returns whether a field value is the default value of the column
+func (c *integerConverter) isDefault(column *ovsdb.ColumnSchema, value interface{}) bool {
+	native, err := c.ToNative(value)
+	if err != nil {
+		// only integers other than 0 do not fit in an int
+		return false
+	}
+	return ovsdb.IsDefaultValue(column, native)
+}
+
+// fromOvs converts an OVS value of the column to the field type
+func (c *integerConverter) fromOvs(column *ovsdb.ColumnSchema, ovsElem interface{}) (interface{}, error) {
+	keyType, valueType := columnBaseTypes(column)
+	switch c.fieldType.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		elems := []interface{}{ovsElem}
+		if ovsSet, ok := ovsElem.(ovsdb.OvsSet); ok {
+			elems = ovsSet.GoSet
+		}
+		switch c.fieldType.Kind() {
+		case reflect.Ptr:
+			if len(elems) > 1 {
+				return nil, fmt.Errorf("expected a slice of len =< 1, but got a slice with %d elements", len(elems))
+			}
+			if len(elems) == 0 {
+				return reflect.Zero(c.fieldType).Interface(), nil
+			}
+			elem, err := integerFromOvs(keyType, elems[0], c.fieldType.Elem())
+			if err != nil {
+				return nil, err
+			}
+			ptr := reflect.New(c.fieldType.Elem())
+			ptr.Elem().Set(elem)
+			return ptr.Interface(), nil
+		case reflect.Array:
+			if len(elems) > c.fieldType.Len() {
+				return nil, fmt.Errorf("expected a slice of len =< %d, but got a slice with %d elements", c.fieldType.Len(), len(elems))
+			}
+			array := reflect.New(c.fieldType).Elem()
+			for i, e := range elems {
+				elem, err := integerFromOvs(keyType, e, c.fieldType.Elem())
+				if err != nil {
+					return nil, err
+				}
+				array.Index(i).Set(elem)
+			}
+			return array.Interface(), nil
+		default:
+			slice := reflect.MakeSlice(c.fieldType, 0, len(elems))
+			for _, e := range elems {
+				elem, err := integerFromOvs(keyType, e, c.fieldType.Elem())
+				if err != nil {
+					return nil, err
+				}
+				slice = reflect.Append(slice, elem)
+			}
+			return slice.Interface(), nil
+		}
+	case reflect.Map:
+		ovsMap, ok := ovsElem.(ovsdb.OvsMap)
+		if !ok {
+			return nil, ovsdb.NewErrWrongType("fromOvs", "OvsMap", ovsElem)
+		}
+		m := reflect.MakeMapWithSize(c.fieldType, len(ovsMap.GoMap))
+		for k, v := range ovsMap.GoMap {
+			key, err := integerFromOvs(keyType, k, c.fieldType.Key())
+			if err != nil {
+				return nil, err
+			}
+			value, err := integerFromOvs(valueType, v, c.fieldType.Elem())
+			if err != nil {
+				return nil, err
+			}
+			m.SetMapIndex(key, value)
+		}
+		return m.Interface(), nil
+	default:
+		elem, err := integerFromOvs(keyType, ovsElem, c.fieldType)
+		if err != nil {
+			return nil, err
+		}
+		return elem.Interface(), nil
+	}
+}
+
+// integerFromOvs converts an OVS atom of the given base type to the given
+// type, either int64 for integers or the native type of the base type, or a
+// type defined over them
+func integerFromOvs(baseType string, ovsElem interface{}, to reflect.Type) (reflect.Value, error) {
+	if to.Kind() != reflect.Int64 {
+		native, err := ovsdb.OvsToNativeAtomic(baseType, ovsElem)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(native).Convert(to), nil
+	}
+	// Default decoding of numbers is float64, convert them to int64
+	value := reflect.ValueOf(ovsElem)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+		return value.Convert(to), nil
+	}
+	return reflect.Value{}, ovsdb.NewErrWrongType("fromOvs", fmt.Sprintf("Convertible to %s", to), ovsElem)
+}
+
+// toOvs converts a field value to an OVS value of the column
+func (c *integerConverter) toOvs(column *ovsdb.ColumnSchema, value interface{}) (interface{}, error) {
+	keyType, valueType := columnBaseTypes(column)
+	switch c.fieldType.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		v := reflect.ValueOf(value)
+		elems := []interface{}{}
+		if v.Kind() == reflect.Ptr {
+			// a nil pointer is an unset optional value, i.e. an empty set
+			if !v.IsNil() {
+				elem, err := integerToOvs(keyType, v.Elem())
+				if err != nil {
+					return nil, err
+				}
+				elems = append(elems, elem)
+			}
+			return ovsdb.OvsSet{GoSet: elems}, nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			elem, err := integerToOvs(keyType, v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, elem)
+		}
+		return ovsdb.OvsSet{GoSet: elems}, nil
+	case reflect.Map:
+		v := reflect.ValueOf(value)
+		ovsMap := make(map[interface{}]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := integerToOvs(keyType, iter.Key())
+			if err != nil {
+				return nil, err
+			}
+			value, err := integerToOvs(valueType, iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			ovsMap[key] = value
+		}
+		return ovsdb.OvsMap{GoMap: ovsMap}, nil
+	default:
+		return integerToOvs(keyType, reflect.ValueOf(value))
+	}
+}
+
+// integerToOvs converts an int64 or a native value of the given base type, or
+// a value of a type defined over them, to an OVS atom
+func integerToOvs(baseType string, value reflect.Value) (interface{}, error) {
+	if value.Kind() == reflect.Int64 {
+		return value.Int(), nil
+	}
+	if native := ovsdb.NativeTypeFromAtomic(baseType); value.Type() != native && value.Type().ConvertibleTo(native) {
+		value = value.Convert(native)
+	}
+	return ovsdb.NativeToOvsAtomic(baseType, value.Interface())
+}
+
+// columnBaseTypes returns the types of the keys and of the values of a column
+func columnBaseTypes(column *ovsdb.ColumnSchema) (string, string) {
+	if column.TypeObj == nil || column.TypeObj.Key == nil {
+		return column.Type, ""
+	}
+	if column.TypeObj.Value == nil {
+		return column.TypeObj.Key.Type, ""
+	}
+	return column.TypeObj.Key.Type, column.TypeObj.Value.Type
+}
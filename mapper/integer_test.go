@@ -0,0 +1,218 @@
+package mapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var integerSchema = []byte(`{
+  "name": "TestSchema",
+  "tables": {
+    "TestTable": {
+      "columns": {
+        "anInt": {
+          "type": "integer"
+        },
+        "anOptionalInt": {
+          "type": {"key": "integer", "min": 0, "max": 1}
+        },
+        "anIntSet": {
+          "type": {"key": "integer", "min": 0, "max": "unlimited"}
+        },
+        "anIntArray": {
+          "type": {"key": "integer", "min": 0, "max": 3}
+        },
+        "aMap": {
+          "type": {"key": "string", "value": "integer", "min": 0, "max": "unlimited"}
+        },
+        "aUUIDMap": {
+          "type": {"key": {"type": "uuid", "refTable": "TestTable"}, "value": "integer", "min": 0, "max": "unlimited"}
+        }
+      }
+    }
+  }
+}`)
+
+type int64TestType struct {
+	AnInt         int64            `ovsdb:"anInt"`
+	AnOptionalInt *int64           `ovsdb:"anOptionalInt"`
+	AnIntSet      []int64          `ovsdb:"anIntSet"`
+	AnIntArray    [3]int64         `ovsdb:"anIntArray"`
+	AMap          map[string]int64 `ovsdb:"aMap"`
+	AUUIDMap      map[string]int64 `ovsdb:"aUUIDMap"`
+}
+
+func TestMapperInt64Fields(t *testing.T) {
+	var schema ovsdb.DatabaseSchema
+	err := json.Unmarshal(integerSchema, &schema)
+	require.NoError(t, err)
+	mapper := NewMapper(schema)
+
+	// beyond the int of 32-bit platforms
+	big := int64(1) << 40
+	obj := int64TestType{
+		AnInt:         big,
+		AnOptionalInt: &big,
+		AnIntSet:      []int64{big, -big},
+		AnIntArray:    [3]int64{big, 1, 2},
+		AMap:          map[string]int64{"big": big},
+		AUUIDMap:      map[string]int64{aUUID0: big},
+	}
+	info, err := NewInfo("TestTable", schema.Table("TestTable"), &obj)
+	require.NoError(t, err)
+	row, err := mapper.NewRow(info)
+	require.NoError(t, err)
+	assert.Equal(t, ovsdb.OvsMap{GoMap: map[interface{}]interface{}{ovsdb.UUID{GoUUID: aUUID0}: big}}, row["aUUIDMap"])
+
+	// marshaled and unmarshaled as sent to and received from the server
+	b, err := json.Marshal(row)
+	require.NoError(t, err)
+	var decoded ovsdb.Row
+	err = json.Unmarshal(b, &decoded)
+	require.NoError(t, err)
+	var result int64TestType
+	resultInfo, err := NewInfo("TestTable", schema.Table("TestTable"), &result)
+	require.NoError(t, err)
+	err = mapper.GetRowData(&decoded, resultInfo)
+	require.NoError(t, err)
+	assert.Equal(t, obj, result)
+
+	// default values are skipped
+	info, err = NewInfo("TestTable", schema.Table("TestTable"), &int64TestType{})
+	require.NoError(t, err)
+	row, err = mapper.NewRow(info)
+	require.NoError(t, err)
+	assert.NotContains(t, row, "anInt")
+	assert.NotContains(t, row, "anOptionalInt")
+	assert.NotContains(t, row, "aMap")
+
+	// conditions and mutations accept values of the field type
+	cond, err := mapper.NewCondition(resultInfo, &result.AnInt, ovsdb.ConditionEqual, int64(42))
+	require.NoError(t, err)
+	assert.Equal(t, 42, cond.Value)
+	mutation, err := mapper.NewMutation(resultInfo, "anIntSet", ovsdb.MutateOperationInsert, []int64{42})
+	require.NoError(t, err)
+	assert.Equal(t, ovsdb.OvsSet{GoSet: []interface{}{42}}, mutation.Value)
+}
+
+func TestNewInfoIntegerFields(t *testing.T) {
+	var schema ovsdb.DatabaseSchema
+	err := json.Unmarshal(integerSchema, &schema)
+	require.NoError(t, err)
+
+	// the native int is still accepted
+	_, err = NewInfo("TestTable", schema.Table("TestTable"), &struct {
+		AnInt    int            `ovsdb:"anInt"`
+		AnIntSet []int          `ovsdb:"anIntSet"`
+		AMap     map[string]int `ovsdb:"aMap"`
+	}{})
+	assert.NoError(t, err)
+
+	// other integer sizes are not
+	_, err = NewInfo("TestTable", schema.Table("TestTable"), &struct {
+		AnInt int32 `ovsdb:"anInt"`
+	}{})
+	assert.Error(t, err)
+	_, err = NewInfo("TestTable", schema.Table("TestTable"), &struct {
+		AMap map[int64]int64 `ovsdb:"aMap"`
+	}{})
+	assert.Error(t, err)
+}
+
+var enumSchema = []byte(`{
+  "name": "TestSchema",
+  "tables": {
+    "TestTable": {
+      "columns": {
+        "protocol": {
+          "type": {"key": {"type": "string", "enum": ["set", ["tcp", "udp"]]}}
+        },
+        "anOptionalProtocol": {
+          "type": {"key": {"type": "string", "enum": ["set", ["tcp", "udp"]]}, "min": 0, "max": 1}
+        },
+        "protocols": {
+          "type": {"key": {"type": "string", "enum": ["set", ["tcp", "udp"]]}, "min": 0, "max": "unlimited"}
+        },
+        "protocolPair": {
+          "type": {"key": {"type": "string", "enum": ["set", ["tcp", "udp"]]}, "min": 0, "max": 2}
+        },
+        "level": {
+          "type": {"key": {"type": "integer", "enum": ["set", [1, 2]]}}
+        }
+      }
+    }
+  }
+}`)
+
+// testProtocol and testLevel are enum types like the ones modelgen generates
+// with WithEnumStringer
+type testProtocol string
+
+func (p testProtocol) String() string {
+	return string(p)
+}
+
+type testLevel int64
+
+func (l testLevel) String() string {
+	return fmt.Sprintf("level%d", l)
+}
+
+type enumTestType struct {
+	Protocol           testProtocol    `ovsdb:"protocol"`
+	AnOptionalProtocol *testProtocol   `ovsdb:"anOptionalProtocol"`
+	Protocols          []testProtocol  `ovsdb:"protocols"`
+	ProtocolPair       [2]testProtocol `ovsdb:"protocolPair"`
+	Level              testLevel       `ovsdb:"level"`
+}
+
+func TestMapperEnumFields(t *testing.T) {
+	var schema ovsdb.DatabaseSchema
+	err := json.Unmarshal(enumSchema, &schema)
+	require.NoError(t, err)
+	mapper := NewMapper(schema)
+
+	udp := testProtocol("udp")
+	obj := enumTestType{
+		Protocol:           "tcp",
+		AnOptionalProtocol: &udp,
+		Protocols:          []testProtocol{"tcp", "udp"},
+		ProtocolPair:       [2]testProtocol{"udp", "tcp"},
+		Level:              2,
+	}
+	info, err := NewInfo("TestTable", schema.Table("TestTable"), &obj)
+	require.NoError(t, err)
+	row, err := mapper.NewRow(info)
+	require.NoError(t, err)
+	// the values are sent in their native type
+	assert.Equal(t, "tcp", row["protocol"])
+	assert.Equal(t, ovsdb.OvsSet{GoSet: []interface{}{"udp"}}, row["anOptionalProtocol"])
+	assert.Equal(t, ovsdb.OvsSet{GoSet: []interface{}{"tcp", "udp"}}, row["protocols"])
+	assert.Equal(t, int64(2), row["level"])
+
+	// marshaled and unmarshaled as sent to and received from the server
+	b, err := json.Marshal(row)
+	require.NoError(t, err)
+	var decoded ovsdb.Row
+	err = json.Unmarshal(b, &decoded)
+	require.NoError(t, err)
+	var result enumTestType
+	resultInfo, err := NewInfo("TestTable", schema.Table("TestTable"), &result)
+	require.NoError(t, err)
+	err = mapper.GetRowData(&decoded, resultInfo)
+	require.NoError(t, err)
+	assert.Equal(t, obj, result)
+
+	// conditions and mutations accept values of the field type
+	cond, err := mapper.NewCondition(resultInfo, &result.Protocols, ovsdb.ConditionIncludes, []testProtocol{"udp"})
+	require.NoError(t, err)
+	assert.Equal(t, ovsdb.OvsSet{GoSet: []interface{}{"udp"}}, cond.Value)
+	mutation, err := mapper.NewMutation(resultInfo, "protocols", ovsdb.MutateOperationDelete, []testProtocol{"udp"})
+	require.NoError(t, err)
+	assert.Equal(t, ovsdb.OvsSet{GoSet: []interface{}{"udp"}}, mutation.Value)
+}
@@ -45,6 +45,8 @@ func NewMapper(schema ovsdb.DatabaseSchema) Mapper {
 
 // GetRowData transforms a Row to a struct based on its tags
 // The result object must be given as pointer to an object with the right tags
+// The columns of the row that the struct does not map, e.g. those added by a
+// newer schema of the server, are ignored
 func (m Mapper) GetRowData(row *ovsdb.Row, result *Info) error {
 	if row == nil {
 		return nil
@@ -64,11 +66,21 @@ func (m Mapper) getData(ovsData ovsdb.Row, result *Info) error {
 
 		ovsElem, ok := ovsData[name]
 		if !ok {
-			// Ignore missing columns
+			// Ignore missing columns, the field keeps its value, e.g. an
+			// optional column is left unset, a nil pointer, in a new model
 			continue
 		}
 
-		nativeElem, err := ovsdb.OvsToNative(column, ovsElem)
+		var nativeElem interface{}
+		var err error
+		if converter, ok := result.Metadata.Converters[name].(*integerConverter); ok {
+			// the integers are decoded in the type of the field, which SetField
+			// keeps as is, as they may not fit in the native int of 32-bit
+			// platforms
+			nativeElem, err = converter.fromOvs(column, ovsElem)
+		} else {
+			nativeElem, err = ovsdb.OvsToNative(column, ovsElem)
+		}
 		if err != nil {
 			return fmt.Errorf("table %s, column %s: failed to extract native element: %s",
 				result.Metadata.TableName, name, err.Error())
@@ -78,6 +90,17 @@ func (m Mapper) getData(ovsData ovsdb.Row, result *Info) error {
 			return err
 		}
 	}
+	// the _version column is not part of the schema, but it can be mapped
+	if ovsElem, ok := ovsData["_version"]; ok && result.hasColumn("_version") {
+		nativeElem, err := ovsdb.OvsToNative(&ovsdb.VersionColumn, ovsElem)
+		if err != nil {
+			return fmt.Errorf("table %s, column _version: failed to extract native element: %s",
+				result.Metadata.TableName, err.Error())
+		}
+		if err := result.SetField("_version", nativeElem); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -90,10 +113,10 @@ func (m Mapper) NewRow(data *Info, fields ...interface{}) (ovsdb.Row, error) {
 		columns[k] = v
 	}
 	columns["_uuid"] = &ovsdb.UUIDColumn
+	columns["_version"] = &ovsdb.VersionColumn
 	ovsRow := make(map[string]interface{}, len(columns))
 	for name, column := range columns {
-		nativeElem, err := data.FieldByColumn(name)
-		if err != nil {
+		if !data.hasColumn(name) {
 			// If provided struct does not have a field to hold this value, skip it
 			continue
 		}
@@ -115,13 +138,13 @@ func (m Mapper) NewRow(data *Info, fields ...interface{}) (ovsdb.Row, error) {
 				continue
 			}
 		}
-		if len(fields) == 0 && ovsdb.IsDefaultValue(column, nativeElem) {
-			continue
-		}
-		ovsElem, err := ovsdb.NativeToOvs(column, nativeElem)
+		ovsElem, ok, err := data.ovsByColumn(name, column, len(fields) > 0)
 		if err != nil {
 			return nil, fmt.Errorf("table %s, column %s: failed to generate ovs element. %s", data.Metadata.TableName, name, err.Error())
 		}
+		if !ok {
+			continue
+		}
 		ovsRow[name] = ovsElem
 	}
 	return ovsRow, nil
@@ -198,6 +221,9 @@ func (m Mapper) EqualFields(one, other *Info, fields ...interface{}) (bool, erro
 }
 
 // NewCondition returns a ovsdb.Condition based on the model
+// For set and map columns, a nil value (or a nil pointer for optional columns)
+// stands for the empty set or map, so a condition checking whether an optional
+// column is unset is encoded as [column, "==", ["set", []]]
 func (m Mapper) NewCondition(data *Info, field interface{}, function ovsdb.ConditionFunction, value interface{}) (*ovsdb.Condition, error) {
 	column, err := data.ColumnByPtr(field)
 	if err != nil {
@@ -209,6 +235,12 @@ func (m Mapper) NewCondition(data *Info, field interface{}, function ovsdb.Condi
 	if columnSchema == nil {
 		return nil, fmt.Errorf("column %s not found", column)
 	}
+	if value == nil && (columnSchema.Type == ovsdb.TypeSet || columnSchema.Type == ovsdb.TypeMap) {
+		value = reflect.Zero(ovsdb.NativeType(columnSchema)).Interface()
+	}
+	if value, err = data.integersToNative(column, value); err != nil {
+		return nil, err
+	}
 	if err := ovsdb.ValidateCondition(columnSchema, function, value); err != nil {
 		return nil, err
 	}
@@ -236,12 +268,15 @@ func (m Mapper) NewMutation(data *Info, column string, mutator ovsdb.Mutator, va
 	if columnSchema == nil {
 		return nil, fmt.Errorf("column %s not found", column)
 	}
+	value, err := data.integersToNative(column, value)
+	if err != nil {
+		return nil, err
+	}
 	if err := ovsdb.ValidateMutation(columnSchema, mutator, value); err != nil {
 		return nil, err
 	}
 
 	var ovsValue interface{}
-	var err error
 	// Usually a mutation value is of the same type of the value being mutated
 	// except for delete mutation of maps where it can also be a list of same type of
 	// keys (rfc7047 5.1). Handle this special case here.
@@ -333,6 +368,9 @@ func (m *Mapper) NewMonitorRequest(data *Info, fields []interface{}) (*ovsdb.Mon
 		for c := range data.Metadata.TableSchema.Columns {
 			columns = append(columns, c)
 		}
+		if data.hasColumn("_version") {
+			columns = append(columns, "_version")
+		}
 	}
 	return &ovsdb.MonitorRequest{Columns: columns, Select: ovsdb.NewDefaultMonitorSelect()}, nil
 }
@@ -239,6 +239,30 @@ func TestMapperGetData(t *testing.T) {
 	assert.Equal(t, expected, test)
 }
 
+func TestMapperGetDataUnknownColumns(t *testing.T) {
+	// a model generated from an older schema, that lacks most of the columns
+	// of the server schema
+	type olderTestType struct {
+		AString string `ovsdb:"aString"`
+		AUUID   string `ovsdb:"aUUID"`
+	}
+	var schema ovsdb.DatabaseSchema
+	err := json.Unmarshal(testSchema, &schema)
+	require.NoError(t, err)
+
+	ovsRow := getOvsTestRow(t)
+	// a column that is not even part of the schema
+	ovsRow["aNewColumn"] = ovsdb.OvsSet{GoSet: []interface{}{"foo"}}
+
+	mapper := NewMapper(schema)
+	test := olderTestType{}
+	info, err := NewInfo("TestTable", schema.Table("TestTable"), &test)
+	require.NoError(t, err)
+	err = mapper.GetRowData(&ovsRow, info)
+	require.NoError(t, err)
+	assert.Equal(t, olderTestType{AString: aString, AUUID: aUUID0}, test)
+}
+
 func TestMapperNewRow(t *testing.T) {
 	var schema ovsdb.DatabaseSchema
 	if err := json.Unmarshal(testSchema, &schema); err != nil {
@@ -451,6 +475,134 @@ func TestMapperNewRowFields(t *testing.T) {
 	}
 }
 
+func TestMapperOptionalRoundTrip(t *testing.T) {
+	var schema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(`{
+		"name": "TestDB",
+		"tables": {
+			"TestTable": {
+				"columns": {
+					"aString": {"type": {"key": "string", "min": 0, "max": 1}},
+					"anInt": {"type": {"key": "integer", "min": 0, "max": 1}},
+					"aUUID": {"type": {"key": "uuid", "min": 0, "max": 1}}
+				}
+			}
+		}
+	}`), &schema)
+	require.NoError(t, err)
+	mapper := NewMapper(schema)
+
+	type optionalType struct {
+		AString *string `ovsdb:"aString"`
+		AnInt   *int    `ovsdb:"anInt"`
+		AUUID   *string `ovsdb:"aUUID"`
+	}
+	zeroString := ""
+	zeroInt := 0
+	anInt := 42
+	tests := []struct {
+		name  string
+		model optionalType
+		wire  string
+	}{
+		{
+			"set",
+			optionalType{AString: &aString, AnInt: &anInt, AUUID: &aUUID0},
+			`{"aString":"foo","aUUID":["uuid","` + aUUID0 + `"],"anInt":42}`,
+		},
+		{
+			"explicitly zero",
+			optionalType{AString: &zeroString, AnInt: &zeroInt},
+			`{"aString":"","aUUID":["set",[]],"anInt":0}`,
+		},
+		{
+			"unset",
+			optionalType{},
+			`{"aString":["set",[]],"aUUID":["set",[]],"anInt":["set",[]]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := tt.model
+			info, err := NewInfo("TestTable", schema.Table("TestTable"), &model)
+			require.NoError(t, err)
+			row, err := mapper.NewRow(info, &model.AString, &model.AnInt, &model.AUUID)
+			require.NoError(t, err)
+			wire, err := json.Marshal(row)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.wire, string(wire))
+
+			var decodedRow ovsdb.Row
+			err = json.Unmarshal(wire, &decodedRow)
+			require.NoError(t, err)
+			decoded := optionalType{}
+			decodedInfo, err := NewInfo("TestTable", schema.Table("TestTable"), &decoded)
+			require.NoError(t, err)
+			err = mapper.GetRowData(&decodedRow, decodedInfo)
+			require.NoError(t, err)
+			assert.Equal(t, tt.model, decoded)
+
+			// the unset columns are left out of the row by default, and the
+			// columns missing from a row are left unset
+			row, err = mapper.NewRow(info)
+			require.NoError(t, err)
+			wire, err = json.Marshal(row)
+			require.NoError(t, err)
+			err = json.Unmarshal(wire, &decodedRow)
+			require.NoError(t, err)
+			decoded = optionalType{}
+			err = mapper.GetRowData(&decodedRow, decodedInfo)
+			require.NoError(t, err)
+			assert.Equal(t, tt.model, decoded)
+		})
+	}
+}
+
+func TestMapperRealEnumRoundTrip(t *testing.T) {
+	var schema ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(`{
+		"name": "TestDB",
+		"tables": {
+			"TestTable": {
+				"columns": {
+					"ratio": {"type": {"key": {"type": "real", "enum": ["set", [0.1, 2.0]]}}},
+					"anOptionalRatio": {"type": {"key": {"type": "real", "enum": ["set", [0.1, 2.0]]}, "min": 0, "max": 1}},
+					"ratios": {"type": {"key": {"type": "real", "enum": ["set", [0.1, 2.0]]}, "min": 0, "max": "unlimited"}}
+				}
+			}
+		}
+	}`), &schema)
+	require.NoError(t, err)
+	mapper := NewMapper(schema)
+
+	// the types modelgen generates for the columns
+	type ratio = float64
+	type realEnumType struct {
+		Ratio           ratio   `ovsdb:"ratio"`
+		AnOptionalRatio *ratio  `ovsdb:"anOptionalRatio"`
+		Ratios          []ratio `ovsdb:"ratios"`
+	}
+	two := ratio(2.0)
+	model := realEnumType{Ratio: 0.1, AnOptionalRatio: &two, Ratios: []ratio{0.1, 2.0}}
+	info, err := NewInfo("TestTable", schema.Table("TestTable"), &model)
+	require.NoError(t, err)
+	row, err := mapper.NewRow(info)
+	require.NoError(t, err)
+	wire, err := json.Marshal(row)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ratio":0.1,"anOptionalRatio":2,"ratios":["set",[0.1,2]]}`, string(wire))
+
+	var decodedRow ovsdb.Row
+	err = json.Unmarshal(wire, &decodedRow)
+	require.NoError(t, err)
+	decoded := realEnumType{}
+	decodedInfo, err := NewInfo("TestTable", schema.Table("TestTable"), &decoded)
+	require.NoError(t, err)
+	err = mapper.GetRowData(&decodedRow, decodedInfo)
+	require.NoError(t, err)
+	assert.Equal(t, model, decoded)
+}
+
 func TestMapperCondition(t *testing.T) {
 
 	var testSchema = []byte(`{
@@ -613,6 +765,124 @@ func TestMapperCondition(t *testing.T) {
 	}
 }
 
+func TestMapperNewConditionEmpty(t *testing.T) {
+	var testSchema = []byte(`{
+  "cksum": "223619766 22548",
+  "name": "TestSchema",
+  "tables": {
+    "TestTable": {
+      "columns": {
+        "name": {
+          "type": "string"
+        },
+        "optional": {
+          "type": {
+            "key": "string",
+            "min": 0,
+            "max": 1
+          }
+        },
+        "optional_uuid": {
+          "type": {
+            "key": "uuid",
+            "min": 0,
+            "max": 1
+          }
+        },
+        "set": {
+          "type": {
+            "key": "string",
+            "min": 0,
+            "max": "unlimited"
+          }
+        },
+        "config": {
+          "type": {
+            "key": "string",
+            "value": "string",
+            "min": 0,
+            "max": "unlimited"
+          }
+        }
+      }
+    }
+  }
+}`)
+	type testType struct {
+		ID           string            `ovsdb:"_uuid"`
+		Name         string            `ovsdb:"name"`
+		Optional     *string           `ovsdb:"optional"`
+		OptionalUUID *string           `ovsdb:"optional_uuid"`
+		Set          []string          `ovsdb:"set"`
+		Config       map[string]string `ovsdb:"config"`
+	}
+
+	var schema ovsdb.DatabaseSchema
+	err := json.Unmarshal(testSchema, &schema)
+	require.NoError(t, err)
+	mapper := NewMapper(schema)
+	testObj := testType{}
+	info, err := NewInfo("TestTable", schema.Table("TestTable"), &testObj)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		field    interface{}
+		value    interface{}
+		expected string
+	}{
+		{
+			name:     "nil optional",
+			field:    &testObj.Optional,
+			value:    nil,
+			expected: `["optional","==",["set",[]]]`,
+		},
+		{
+			name:     "nil pointer optional",
+			field:    &testObj.Optional,
+			value:    (*string)(nil),
+			expected: `["optional","==",["set",[]]]`,
+		},
+		{
+			name:     "nil optional uuid",
+			field:    &testObj.OptionalUUID,
+			value:    nil,
+			expected: `["optional_uuid","==",["set",[]]]`,
+		},
+		{
+			name:     "nil set",
+			field:    &testObj.Set,
+			value:    nil,
+			expected: `["set","==",["set",[]]]`,
+		},
+		{
+			name:     "empty set",
+			field:    &testObj.Set,
+			value:    []string{},
+			expected: `["set","==",["set",[]]]`,
+		},
+		{
+			name:     "nil map",
+			field:    &testObj.Config,
+			value:    nil,
+			expected: `["config","==",["map",[]]]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cond, err := mapper.NewCondition(info, tt.field, ovsdb.ConditionEqual, tt.value)
+			require.NoError(t, err)
+			b, err := json.Marshal(cond)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(b))
+		})
+	}
+
+	// nil is not a valid value for atomic columns
+	_, err = mapper.NewCondition(info, &testObj.Name, ovsdb.ConditionEqual, nil)
+	assert.Error(t, err)
+}
+
 func TestMapperEqualIndexes(t *testing.T) {
 
 	var testSchema = []byte(`{
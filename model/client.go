@@ -10,8 +10,9 @@ import (
 
 // ClientDBModel contains the client information needed to build a DatabaseModel
 type ClientDBModel struct {
-	name  string
-	types map[string]reflect.Type
+	name       string
+	types      map[string]reflect.Type
+	converters *mapper.Converters
 }
 
 // NewModel returns a new instance of a model from a specific string
@@ -29,6 +30,18 @@ func (db ClientDBModel) Name() string {
 	return db.name
 }
 
+// WithConverters returns a copy of the ClientDBModel whose models may hold
+// columns in the non-native field types handled by the given Converters
+func (db ClientDBModel) WithConverters(converters *mapper.Converters) ClientDBModel {
+	db.converters = converters
+	return db
+}
+
+// Converters returns the Converters of the ClientDBModel, if any
+func (db ClientDBModel) Converters() *mapper.Converters {
+	return db.converters
+}
+
 // Validate validates the DatabaseModel against the input schema
 // Returns all the errors detected
 func (db ClientDBModel) validate(schema ovsdb.DatabaseSchema) []error {
@@ -49,7 +62,7 @@ func (db ClientDBModel) validate(schema ovsdb.DatabaseSchema) []error {
 			errors = append(errors, err)
 			continue
 		}
-		if _, err := mapper.NewInfo(tableName, tableSchema, model); err != nil {
+		if _, err := mapper.NewInfoWithConverters(tableName, tableSchema, model, db.converters); err != nil {
 			errors = append(errors, err)
 		}
 	}
@@ -29,7 +29,7 @@ func NewDatabaseModel(schema ovsdb.DatabaseSchema, client ClientDBModel) (Databa
 	}
 	dbModel.Mapper = mapper.NewMapper(schema)
 	var metadata map[reflect.Type]mapper.Metadata
-	metadata, errs = generateModelInfo(schema, client.types)
+	metadata, errs = generateModelInfo(schema, client.types, client.converters)
 	if len(errs) > 0 {
 		return DatabaseModel{}, errs
 	}
@@ -84,7 +84,7 @@ func (db DatabaseModel) FindTable(mType reflect.Type) string {
 
 // generateModelMetadata creates metadata objects from all models included in the
 // database and caches them for future re-use
-func generateModelInfo(dbSchema ovsdb.DatabaseSchema, modelTypes map[string]reflect.Type) (map[reflect.Type]mapper.Metadata, []error) {
+func generateModelInfo(dbSchema ovsdb.DatabaseSchema, modelTypes map[string]reflect.Type, converters *mapper.Converters) (map[reflect.Type]mapper.Metadata, []error) {
 	errors := []error{}
 	metadata := make(map[reflect.Type]mapper.Metadata, len(modelTypes))
 	for tableName, tType := range modelTypes {
@@ -95,7 +95,7 @@ func generateModelInfo(dbSchema ovsdb.DatabaseSchema, modelTypes map[string]refl
 		}
 
 		obj := reflect.New(tType.Elem()).Interface().(Model)
-		info, err := mapper.NewInfo(tableName, tableSchema, obj)
+		info, err := mapper.NewInfoWithConverters(tableName, tableSchema, obj, converters)
 		if err != nil {
 			errors = append(errors, err)
 			continue
@@ -3,12 +3,16 @@ package modelgen
 import (
 	"encoding/json"
 	"sort"
+	"strconv"
+	"strings"
 	"text/template"
 
 	"github.com/ovn-org/libovsdb/ovsdb"
 )
 
-// NewDBTemplate returns a new ClientDBModel template. It includes the following
+// NewDBTemplate returns a new ClientDBModel template. The generated
+// FullDatabaseModel registers the struct of every table of the package under
+// the Table<StructName> constant of its table. It includes the following
 // other templates that can be overridden to customize the generated file:
 //
 //   - `header`: to include a comment as a header before package definition
@@ -45,11 +49,19 @@ package {{ index . "PackageName" }}
 // FullDatabaseModel returns the DatabaseModel object to be used in libovsdb
 func FullDatabaseModel() (model.ClientDBModel, error) {
 	return model.NewClientDBModel("{{ index . "DatabaseName" }}", map[string]model.Model{
-    {{ range index . "Tables" }} "{{ .TableName }}" : &{{ .StructName }}{}, 
+    {{ range index . "Tables" }} Table{{ .StructName }} : &{{ .StructName }}{}, 
     {{ end }}
 	})
 }
 
+// TableNames returns the names of the tables of the database, sorted
+func TableNames() []string {
+	return []string{
+    {{ range index . "Tables" }} "{{ .TableName }}",
+    {{ end }}
+	}
+}
+
 var schema = {{ index . "Schema" | escape }}
 
 func Schema() ovsdb.DatabaseSchema {
@@ -77,7 +89,14 @@ type TableInfo struct {
 //   - `DatabaseName`: (string) the database name
 //   - `PackageName`: (string) the package name
 //   - `Tables`: []Table list of Tables that form the Model
-func GetDBTemplateData(pkg string, schema ovsdb.DatabaseSchema) map[string]interface{} {
+//
+// Only the WithStructNamePrefix and WithInitialisms options apply to the
+// database model, but the other options are validated too
+func GetDBTemplateData(pkg string, schema ovsdb.DatabaseSchema, opts ...Option) (map[string]interface{}, error) {
+	o, err := newOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
 	data := map[string]interface{}{}
 	data["DatabaseName"] = schema.Name
 	data["PackageName"] = pkg
@@ -94,13 +113,18 @@ func GetDBTemplateData(pkg string, schema ovsdb.DatabaseSchema) map[string]inter
 	for _, tableName := range order {
 		tables = append(tables, TableInfo{
 			TableName:  tableName,
-			StructName: StructName(tableName),
+			StructName: o.structNamePrefix + structName(tableName, o.nameInitialisms()),
 		})
 	}
 	data["Tables"] = tables
-	return data
+	return data, nil
 }
 
+// escape returns a Go string literal holding s, a raw one unless s holds a
+// backtick, which a raw string literal cannot
 func escape(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
 	return "`" + s + "`"
 }
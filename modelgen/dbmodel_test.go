@@ -2,9 +2,13 @@ package modelgen
 
 import (
 	"encoding/json"
+	"reflect"
+	"sort"
 	"testing"
 	"text/template"
 
+	"github.com/ovn-org/libovsdb/example/vswitchd"
+	"github.com/ovn-org/libovsdb/model"
 	"github.com/ovn-org/libovsdb/ovsdb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -61,9 +65,16 @@ import (
 // FullDatabaseModel returns the DatabaseModel object to be used in libovsdb
 func FullDatabaseModel() (model.ClientDBModel, error) {
 	return model.NewClientDBModel("AtomicDB", map[string]model.Model{
-		"atomicTable": &AtomicTable{},
+		TableAtomicTable: &AtomicTable{},
 	})
 }
+
+// TableNames returns the names of the tables of the database, sorted
+func TableNames() []string {
+	return []string{
+		"atomicTable",
+	}
+}
 ` + `
 var schema = ` + "`" + `{
   "name": "AtomicDB",
@@ -129,7 +140,8 @@ func Schema() ovsdb.DatabaseSchema {
 	for _, tt := range test {
 		t.Run(tt.name, func(t *testing.T) {
 			tmpl := NewDBTemplate()
-			data := GetDBTemplateData("test", schema)
+			data, err := GetDBTemplateData("test", schema)
+			require.NoError(t, err)
 			if tt.err {
 				assert.NotNil(t, err)
 			} else {
@@ -146,3 +158,58 @@ func Schema() ovsdb.DatabaseSchema {
 		})
 	}
 }
+
+func TestDbModelTableNames(t *testing.T) {
+	schema := vswitchd.Schema()
+	expected := []string{}
+	for tableName := range schema.Tables {
+		expected = append(expected, tableName)
+	}
+	sort.Strings(expected)
+	assert.Equal(t, expected, vswitchd.TableNames())
+}
+
+func TestDbModelTemplateTwoTables(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"name": "NB",
+		"version": "0.0.0",
+		"tables": {
+			"Logical_Switch": {
+				"columns": {
+					"name": {"type": "string"}
+				}
+			},
+			"ACL": {
+				"columns": {
+					"priority": {"type": "integer"}
+				}
+			}
+		}
+	}`)
+	var schema ovsdb.DatabaseSchema
+	err := json.Unmarshal(rawSchema, &schema)
+	require.NoError(t, err)
+	data, err := GetDBTemplateData("test", schema)
+	require.NoError(t, err)
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	b, err := g.Format(NewDBTemplate(), data)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `	return model.NewClientDBModel("NB", map[string]model.Model{
+		TableACL:           &ACL{},
+		TableLogicalSwitch: &LogicalSwitch{},
+	})`)
+}
+
+func TestDbModelFullDatabaseModel(t *testing.T) {
+	clientDBModel, err := vswitchd.FullDatabaseModel()
+	require.NoError(t, err)
+	dbModel, errs := model.NewDatabaseModel(vswitchd.Schema(), clientDBModel)
+	require.Empty(t, errs)
+	// every table of the schema is registered by its name
+	types := dbModel.Types()
+	assert.Len(t, types, len(vswitchd.Schema().Tables))
+	assert.Equal(t, reflect.TypeOf(&vswitchd.Bridge{}), types[vswitchd.TableBridge])
+	assert.Equal(t, reflect.TypeOf(&vswitchd.Interface{}), types[vswitchd.TableInterface])
+}
@@ -0,0 +1,160 @@
+package modelgen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+)
+
+// Column identifies a column of a table
+type Column struct {
+	Table  string
+	Column string
+}
+
+// TypeChange describes a column whose type changed between two versions of a
+// schema. The types are the ones of the model fields the column is generated
+// into
+type TypeChange struct {
+	Table   string
+	Column  string
+	OldType string
+	NewType string
+}
+
+// SchemaDiff describes the differences between two versions of a database
+// schema. Every list is sorted, and the columns of added or removed tables are
+// not listed as added or removed columns
+type SchemaDiff struct {
+	AddedTables    []string
+	RemovedTables  []string
+	AddedColumns   []Column
+	RemovedColumns []Column
+	TypeChanges    []TypeChange
+}
+
+// Empty returns whether the schemas do not differ
+func (d SchemaDiff) Empty() bool {
+	return len(d.AddedTables) == 0 && len(d.RemovedTables) == 0 &&
+		len(d.AddedColumns) == 0 && len(d.RemovedColumns) == 0 &&
+		len(d.TypeChanges) == 0
+}
+
+// String returns a report of the differences, one per line
+func (d SchemaDiff) String() string {
+	var b strings.Builder
+	for _, table := range d.AddedTables {
+		fmt.Fprintf(&b, "+ table %s\n", table)
+	}
+	for _, table := range d.RemovedTables {
+		fmt.Fprintf(&b, "- table %s\n", table)
+	}
+	for _, column := range d.AddedColumns {
+		fmt.Fprintf(&b, "+ column %s.%s\n", column.Table, column.Column)
+	}
+	for _, column := range d.RemovedColumns {
+		fmt.Fprintf(&b, "- column %s.%s\n", column.Table, column.Column)
+	}
+	for _, change := range d.TypeChanges {
+		fmt.Fprintf(&b, "~ column %s.%s: %s -> %s\n", change.Table, change.Column, change.OldType, change.NewType)
+	}
+	return b.String()
+}
+
+// DiffSchemas compares two versions of a database schema, reporting the
+// tables and columns added and removed by the new one, as well as the columns
+// whose generated model field type changed
+func DiffSchemas(old, new ovsdb.DatabaseSchema) SchemaDiff {
+	var diff SchemaDiff
+	for _, tableName := range sortedTables(old) {
+		if _, ok := new.Tables[tableName]; !ok {
+			diff.RemovedTables = append(diff.RemovedTables, tableName)
+		}
+	}
+	for _, tableName := range sortedTables(new) {
+		newTable := new.Tables[tableName]
+		oldTable, ok := old.Tables[tableName]
+		if !ok {
+			diff.AddedTables = append(diff.AddedTables, tableName)
+			continue
+		}
+		for _, columnName := range sortedColumns(oldTable) {
+			if _, ok := newTable.Columns[columnName]; !ok {
+				diff.RemovedColumns = append(diff.RemovedColumns, Column{Table: tableName, Column: columnName})
+			}
+		}
+		for _, columnName := range sortedColumns(newTable) {
+			newColumn := newTable.Columns[columnName]
+			oldColumn, ok := oldTable.Columns[columnName]
+			if !ok {
+				diff.AddedColumns = append(diff.AddedColumns, Column{Table: tableName, Column: columnName})
+				continue
+			}
+			oldType := FieldType(tableName, columnName, oldColumn)
+			newType := FieldType(tableName, columnName, newColumn)
+			if oldType != newType {
+				diff.TypeChanges = append(diff.TypeChanges, TypeChange{
+					Table:   tableName,
+					Column:  columnName,
+					OldType: oldType,
+					NewType: newType,
+				})
+			}
+		}
+	}
+	return diff
+}
+
+func sortedTables(schema ovsdb.DatabaseSchema) []string {
+	var tables sort.StringSlice
+	for tableName := range schema.Tables {
+		tables = append(tables, tableName)
+	}
+	tables.Sort()
+	return tables
+}
+
+func sortedColumns(table ovsdb.TableSchema) []string {
+	var columns sort.StringSlice
+	for columnName := range table.Columns {
+		columns = append(columns, columnName)
+	}
+	columns.Sort()
+	return columns
+}
+
+// Compatibility classifies the change between two versions of a database
+// schema. A change only adding tables and columns is compatible with the
+// models generated from the old schema. Removing a table or a column, or
+// changing the type of a column, is not, and is listed in the Reasons
+type Compatibility struct {
+	Compatible bool
+	Reasons    []string
+}
+
+// String returns "compatible", or "incompatible" followed by the reasons
+func (c Compatibility) String() string {
+	if c.Compatible {
+		return "compatible"
+	}
+	return "incompatible: " + strings.Join(c.Reasons, ", ")
+}
+
+// ClassifySchemaChange classifies the change between two versions of a
+// database schema, based on their differences (see DiffSchemas)
+func ClassifySchemaChange(old, new ovsdb.DatabaseSchema) Compatibility {
+	diff := DiffSchemas(old, new)
+	var reasons []string
+	for _, table := range diff.RemovedTables {
+		reasons = append(reasons, fmt.Sprintf("table %s removed", table))
+	}
+	for _, column := range diff.RemovedColumns {
+		reasons = append(reasons, fmt.Sprintf("column %s.%s removed", column.Table, column.Column))
+	}
+	for _, change := range diff.TypeChanges {
+		reasons = append(reasons, fmt.Sprintf("column %s.%s retyped from %s to %s", change.Table, change.Column, change.OldType, change.NewType))
+	}
+	return Compatibility{Compatible: len(reasons) == 0, Reasons: reasons}
+}
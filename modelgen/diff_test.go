@@ -0,0 +1,154 @@
+package modelgen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	var old, new ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(`
+	{
+		"name": "TestDB",
+		"version": "1.0.0",
+		"tables": {
+			"Bridge": {
+				"columns": {
+					"name": {"type": "string"},
+					"ports": {"type": {"key": {"type": "uuid"}, "min": 0, "max": "unlimited"}}
+				}
+			},
+			"Legacy": {
+				"columns": {
+					"name": {"type": "string"}
+				}
+			}
+		}
+	}`), &old)
+	require.NoError(t, err)
+	err = json.Unmarshal([]byte(`
+	{
+		"name": "TestDB",
+		"version": "2.0.0",
+		"tables": {
+			"Bridge": {
+				"columns": {
+					"name": {"type": "string"},
+					"ports": {"type": {"key": {"type": "uuid"}, "min": 0, "max": "unlimited"}},
+					"external_ids": {"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}}
+				}
+			}
+		}
+	}`), &new)
+	require.NoError(t, err)
+
+	diff := DiffSchemas(old, new)
+	assert.Equal(t, SchemaDiff{
+		RemovedTables: []string{"Legacy"},
+		AddedColumns:  []Column{{Table: "Bridge", Column: "external_ids"}},
+	}, diff)
+	assert.False(t, diff.Empty())
+	assert.Equal(t, "- table Legacy\n+ column Bridge.external_ids\n", diff.String())
+
+	t.Run("type changes", func(t *testing.T) {
+		changed := ovsdb.DatabaseSchema{Tables: map[string]ovsdb.TableSchema{}}
+		for name, table := range new.Tables {
+			changed.Tables[name] = table
+		}
+		var ports ovsdb.ColumnSchema
+		err := json.Unmarshal([]byte(`{"type": {"key": {"type": "uuid"}, "min": 0, "max": 1}}`), &ports)
+		require.NoError(t, err)
+		changed.Tables["Bridge"] = ovsdb.TableSchema{Columns: map[string]*ovsdb.ColumnSchema{
+			"name":         new.Tables["Bridge"].Columns["name"],
+			"ports":        &ports,
+			"external_ids": new.Tables["Bridge"].Columns["external_ids"],
+		}}
+		diff := DiffSchemas(new, changed)
+		assert.Equal(t, SchemaDiff{
+			TypeChanges: []TypeChange{{
+				Table:   "Bridge",
+				Column:  "ports",
+				OldType: "[]string",
+				NewType: "*string",
+			}},
+		}, diff)
+		assert.Equal(t, "~ column Bridge.ports: []string -> *string\n", diff.String())
+	})
+
+	assert.True(t, DiffSchemas(new, new).Empty())
+}
+
+func TestClassifySchemaChange(t *testing.T) {
+	var old, added, retyped ovsdb.DatabaseSchema
+	err := json.Unmarshal([]byte(`
+	{
+		"name": "TestDB",
+		"version": "1.0.0",
+		"tables": {
+			"Bridge": {
+				"columns": {
+					"name": {"type": "string"},
+					"ports": {"type": {"key": {"type": "uuid"}, "min": 0, "max": "unlimited"}}
+				}
+			}
+		}
+	}`), &old)
+	require.NoError(t, err)
+	err = json.Unmarshal([]byte(`
+	{
+		"name": "TestDB",
+		"version": "1.1.0",
+		"tables": {
+			"Bridge": {
+				"columns": {
+					"name": {"type": "string"},
+					"ports": {"type": {"key": {"type": "uuid"}, "min": 0, "max": "unlimited"}},
+					"external_ids": {"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}}
+				}
+			},
+			"Port": {
+				"columns": {
+					"name": {"type": "string"}
+				}
+			}
+		}
+	}`), &added)
+	require.NoError(t, err)
+	err = json.Unmarshal([]byte(`
+	{
+		"name": "TestDB",
+		"version": "2.0.0",
+		"tables": {
+			"Bridge": {
+				"columns": {
+					"name": {"type": "integer"}
+				}
+			}
+		}
+	}`), &retyped)
+	require.NoError(t, err)
+
+	compatibility := ClassifySchemaChange(old, added)
+	assert.Equal(t, Compatibility{Compatible: true}, compatibility)
+	assert.Equal(t, "compatible", compatibility.String())
+
+	compatibility = ClassifySchemaChange(old, retyped)
+	assert.Equal(t, Compatibility{
+		Reasons: []string{
+			"column Bridge.ports removed",
+			"column Bridge.name retyped from string to int64",
+		},
+	}, compatibility)
+	assert.Equal(t, "incompatible: column Bridge.ports removed, column Bridge.name retyped from string to int64", compatibility.String())
+
+	// the change back is not compatible either
+	compatibility = ClassifySchemaChange(added, old)
+	assert.False(t, compatibility.Compatible)
+	assert.Equal(t, []string{"table Port removed", "column Bridge.external_ids removed"}, compatibility.Reasons)
+
+	assert.True(t, ClassifySchemaChange(old, old).Compatible)
+}
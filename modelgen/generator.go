@@ -3,9 +3,15 @@ package modelgen
 import (
 	"bytes"
 	"fmt"
+	"go/ast"
 	"go/format"
+	"go/parser"
+	"go/token"
 	"io/ioutil"
 	"log"
+	"sort"
+	"strconv"
+	"strings"
 	"text/template"
 )
 
@@ -19,7 +25,12 @@ type generator struct {
 	dryRun bool
 }
 
-// Format returns a formatted byte slice by executing the template with the given args
+// Format returns a formatted byte slice by executing the template with the given args.
+// The imports of the code of several tables, generated by the template
+// returned by NewTablesTemplate, are merged into one declaration without
+// duplicates, and the imports of the custom types of a table are removed from
+// the parts of its code not using them, as are all the unused imports of a
+// table generated with a build tag (see WithBuildTag)
 func (g *generator) Format(tmpl *template.Template, args interface{}) ([]byte, error) {
 	buffer := bytes.Buffer{}
 	err := tmpl.Execute(&buffer, args)
@@ -27,13 +38,167 @@ func (g *generator) Format(tmpl *template.Template, args interface{}) ([]byte, e
 		return nil, err
 	}
 
-	src, err := format.Source(buffer.Bytes())
+	src := buffer.Bytes()
+	if data, ok := args.(TableTemplateData); ok {
+		if tag, _ := data["BuildTag"].(string); tag != "" {
+			// the imports of the methods moved to the tagged part are left in
+			// the parts not using them, and the other way around
+			src, err = removeUnusedImports(src, nil)
+			if err != nil {
+				return nil, err
+			}
+		} else if paths, ok := data["CustomImports"].([]string); ok && len(paths) > 0 {
+			src, err = removeUnusedImports(src, paths)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if tmpl.Name() == tablesTemplateName {
+		if data, ok := args.(map[string]interface{}); ok {
+			if tables, _ := data["Tables"].([]TableTemplateData); len(tables) > 0 && tables[0]["BuildTag"] != "" {
+				src, err = removeUnusedImports(src, nil)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	// the templates of the tables, and the several tables of a single file,
+	// each import the packages they use
+	src, err = mergeImports(src)
+	if err != nil {
+		return nil, err
+	}
+	src, err = format.Source(src)
 	if err != nil {
 		return nil, err
 	}
 	return src, nil
 }
 
+// mergeImports replaces the import declarations of the source by a single one
+// holding each of their imports once, the standard library first. A source
+// with a single import declaration is returned as is
+func mergeImports(src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ImportsOnly)
+	if err != nil {
+		return nil, err
+	}
+	var decls []*ast.GenDecl
+	for _, decl := range file.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+			decls = append(decls, genDecl)
+		}
+	}
+	if len(decls) < 2 {
+		return src, nil
+	}
+	seen := map[string]bool{}
+	var std, others []string
+	for _, spec := range file.Imports {
+		imp := spec.Path.Value
+		if spec.Name != nil {
+			imp = spec.Name.Name + " " + imp
+		}
+		if seen[imp] {
+			continue
+		}
+		seen[imp] = true
+		if strings.Contains(strings.SplitN(spec.Path.Value, "/", 2)[0], ".") {
+			others = append(others, imp)
+		} else {
+			std = append(std, imp)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(others)
+	var merged bytes.Buffer
+	merged.WriteString("import (\n")
+	for _, imp := range std {
+		fmt.Fprintf(&merged, "\t%s\n", imp)
+	}
+	if len(std) > 0 && len(others) > 0 {
+		merged.WriteString("\n")
+	}
+	for _, imp := range others {
+		fmt.Fprintf(&merged, "\t%s\n", imp)
+	}
+	merged.WriteString(")\n")
+
+	// the merged declaration replaces the first one, the others are removed
+	var out bytes.Buffer
+	last := 0
+	for i, decl := range decls {
+		start := fset.Position(decl.Pos()).Offset
+		end := fset.Position(decl.End()).Offset
+		out.Write(src[last:start])
+		if i == 0 {
+			out.Write(merged.Bytes())
+		}
+		last = end
+	}
+	out.Write(src[last:])
+	return out.Bytes(), nil
+}
+
+// removeUnusedImports removes the imports of the given paths, or of any path
+// if nil, whose package, named after the last element of its path, is not
+// referenced by the source. An import declaration left empty is removed
+func removeUnusedImports(src []byte, paths []string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return nil, err
+	}
+	used := map[string]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+	removable := map[string]bool{}
+	for _, path := range paths {
+		removable[strconv.Quote(path)] = true
+	}
+	unused := func(spec *ast.ImportSpec) bool {
+		path := spec.Path.Value
+		pkg := strings.Trim(path[strings.LastIndex(path, "/")+1:], `"`)
+		return spec.Name == nil && (paths == nil || removable[path]) && !used[pkg]
+	}
+	var out bytes.Buffer
+	last := 0
+	cut := func(start, end token.Pos) {
+		out.Write(src[last:fset.Position(start).Offset])
+		last = fset.Position(end).Offset
+	}
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT {
+			continue
+		}
+		var removed []*ast.ImportSpec
+		for _, spec := range genDecl.Specs {
+			if unused(spec.(*ast.ImportSpec)) {
+				removed = append(removed, spec.(*ast.ImportSpec))
+			}
+		}
+		if len(removed) == len(genDecl.Specs) {
+			cut(genDecl.Pos(), genDecl.End())
+			continue
+		}
+		for _, spec := range removed {
+			cut(spec.Pos(), spec.End())
+		}
+	}
+	out.Write(src[last:])
+	return out.Bytes(), nil
+}
+
 // Generate generates the code and writes it to specified file path
 func (g *generator) Generate(filename string, tmpl *template.Template, args interface{}) error {
 	src, err := g.Format(tmpl, args)
@@ -0,0 +1,57 @@
+package modelgen
+
+import (
+	"go/format"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeImports(t *testing.T) {
+	src := []byte(`package test
+
+import "github.com/ovn-org/libovsdb/model"
+
+func A() {}
+`)
+	merged, err := mergeImports(src)
+	require.NoError(t, err)
+	assert.Equal(t, string(src), string(merged))
+
+	src = []byte(`package test
+
+import (
+	"sort"
+
+	"github.com/ovn-org/libovsdb/model"
+)
+
+import "fmt"
+
+import (
+	"sort"
+
+	"github.com/ovn-org/libovsdb/model"
+	ovs "github.com/ovn-org/libovsdb/ovsdb"
+)
+
+func A() {}
+`)
+	merged, err = mergeImports(src)
+	require.NoError(t, err)
+	merged, err = format.Source(merged)
+	require.NoError(t, err)
+	assert.Equal(t, `package test
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ovn-org/libovsdb/model"
+	ovs "github.com/ovn-org/libovsdb/ovsdb"
+)
+
+func A() {}
+`, string(merged))
+}
@@ -1,9 +1,41 @@
 package modelgen
 
+import (
+	"fmt"
+	"go/token"
+	"strings"
+)
+
 type options struct {
 	dryRun bool
+	// tableFlags holds the TableTemplateData flags set by the table options
+	tableFlags map[string]bool
+	// customTypes holds the custom types of the fields, by table and column
+	customTypes map[string]map[string]string
+	// fieldNames holds the names of the fields, by table and column
+	fieldNames map[string]map[string]string
+	// buildTag is the build tag of the methods of taggedTemplates
+	buildTag        string
+	taggedTemplates []string
+	// structNamePrefix is the prefix of the names of the table structs
+	structNamePrefix string
+	// initialisms are the initialisms kept upper case in the names, the
+	// common ones if nil
+	initialisms map[string]bool
+}
+
+// nameInitialisms returns the initialisms kept upper case in the names
+func (o *options) nameInitialisms() map[string]bool {
+	if o.initialisms == nil {
+		return defaultInitialisms
+	}
+	return o.initialisms
 }
 
+// Option configures the generator, or the code generated for the tables when
+// given to GetTableTemplateData. There is no option for the optional columns,
+// those with at most one element, as their fields are always pointers, nil
+// when the column is empty
 type Option func(o *options) error
 
 func newOptions(opts ...Option) (*options, error) {
@@ -22,3 +54,281 @@ func WithDryRun() Option {
 		return nil
 	}
 }
+
+// withTableFlag sets a flag of the TableTemplateData
+func withTableFlag(flag string, val bool) Option {
+	return func(o *options) error {
+		if o.tableFlags == nil {
+			o.tableFlags = make(map[string]bool)
+		}
+		o.tableFlags[flag] = val
+		return nil
+	}
+}
+
+// WithBuildTag generates the methods of the given templates, among
+// TaggableTemplates, in the tagged part of the code of the tables only (see
+// TableTaggedPart), whose file is constrained by the build tag, e.g.
+// libovsdb_helpers. This way the builds without the tag do not include them.
+// The templates still need to be enabled by their own option, e.g. the
+// stringer template by WithStringer
+func WithBuildTag(tag string, templates ...string) Option {
+	return func(o *options) error {
+		o.buildTag = tag
+		o.taggedTemplates = append([]string{}, templates...)
+		return nil
+	}
+}
+
+// WithInitialisms changes the initialisms kept upper case in the generated
+// names, on top of the common ones like ID or IP: the initialisms mapped to
+// true are added, like VTEP, and the ones mapped to false are removed, so that
+// they are title-cased like any other word, e.g. with ID removed id_string
+// becomes IdString. The initialisms are case-insensitive and also apply to
+// their plural, like VTEPs. Like WithStructNamePrefix, it must be given to
+// both GetTableTemplateData, or GetTablesTemplateData, and GetDBTemplateData
+func WithInitialisms(initialisms map[string]bool) Option {
+	return func(o *options) error {
+		changed := make(map[string]bool, len(o.nameInitialisms())+len(initialisms))
+		for initialism := range o.nameInitialisms() {
+			changed[initialism] = true
+		}
+		for initialism, val := range initialisms {
+			if val {
+				changed[strings.ToUpper(initialism)] = true
+			} else {
+				delete(changed, strings.ToUpper(initialism))
+			}
+		}
+		o.initialisms = changed
+		return nil
+	}
+}
+
+// WithStructNamePrefix prefixes the names of the table structs, and so the
+// names derived from them, like the enum types and the constants, to generate
+// the models of several databases sharing table names without collisions, e.g.
+// with the Nb prefix the struct of the Logical_Switch table is
+// NbLogicalSwitch. It must be given to both GetTableTemplateData, or
+// GetTablesTemplateData, and GetDBTemplateData
+func WithStructNamePrefix(prefix string) Option {
+	return func(o *options) error {
+		o.structNamePrefix = title(prefix)
+		return nil
+	}
+}
+
+// WithCustomTypeMapping generates the fields of the given columns, by table
+// and column, with a custom type instead of the one derived from their schema,
+// e.g. a time.Time for a string column holding a timestamp. A type of another
+// package is given with its import path, e.g. encoding/json.RawMessage, whose
+// package name must be the last element. The mapper only knows how to read
+// and write the native types of the columns: a model with a custom type must
+// be used with a mapper.Converter of the type, registered with the
+// WithColumnConverter or WithTypeConverter client options. The code generated
+// by WithExtendedGen handles a custom type like a value of the column type, so
+// for instance it must be comparable
+func WithCustomTypeMapping(mapping map[string]map[string]string) Option {
+	return func(o *options) error {
+		if o.customTypes == nil {
+			o.customTypes = make(map[string]map[string]string)
+		}
+		for table, columns := range mapping {
+			if o.customTypes[table] == nil {
+				o.customTypes[table] = make(map[string]string)
+			}
+			for column, typ := range columns {
+				o.customTypes[table][column] = typ
+			}
+		}
+		return nil
+	}
+}
+
+// WithFieldNameOverrides generates the fields of the given columns, by table
+// and column, with the given names instead of the ones derived from the column
+// names by FieldName, e.g. IPAddress instead of IP for an ip column. The names
+// must be exported identifiers, and the UUID field of the _uuid column cannot
+// be renamed. The ovsdb tags of the fields still hold the column names
+func WithFieldNameOverrides(overrides map[string]map[string]string) Option {
+	return func(o *options) error {
+		if o.fieldNames == nil {
+			o.fieldNames = make(map[string]map[string]string)
+		}
+		for table, columns := range overrides {
+			if o.fieldNames[table] == nil {
+				o.fieldNames[table] = make(map[string]string)
+			}
+			for column, name := range columns {
+				if column == "_uuid" {
+					return fmt.Errorf("the field of the _uuid column of table %s cannot be renamed", table)
+				}
+				if !token.IsIdentifier(name) || !token.IsExported(name) {
+					return fmt.Errorf("invalid name %q for the field of column %s of table %s, it must be an exported identifier", name, column, table)
+				}
+				o.fieldNames[table][column] = name
+			}
+		}
+		return nil
+	}
+}
+
+// WithoutEnumTypes generates enum columns with their base type instead of a
+// type alias with a const for each possible value
+func WithoutEnumTypes() Option {
+	return withTableFlag("WithEnumTypes", false)
+}
+
+// WithExtendedGen generates code to deep copy, compare and normalize models
+func WithExtendedGen() Option {
+	return withTableFlag("WithExtendedGen", true)
+}
+
+// WithDeepCopy generates the DeepCopy and DeepCopyInto methods without the rest
+// of the code generated by WithExtendedGen
+func WithDeepCopy() Option {
+	return withTableFlag("WithDeepCopy", true)
+}
+
+// WithEquals generates the Equals method without the rest of the code
+// generated by WithExtendedGen
+func WithEquals() Option {
+	return withTableFlag("WithEquals", true)
+}
+
+// WithColumnSchema generates a ColumnSchema method returning the schema of a
+// column of the table
+func WithColumnSchema() Option {
+	return withTableFlag("WithColumnSchema", true)
+}
+
+// WithBuilder generates a builder to construct models with method chaining
+func WithBuilder() Option {
+	return withTableFlag("WithBuilder", true)
+}
+
+// WithFieldColumnMaps generates maps from the struct field names to the column
+// names of the table, and back
+func WithFieldColumnMaps() Option {
+	return withTableFlag("WithFieldColumnMaps", true)
+}
+
+// WithApplyPatch generates an ApplyPatch method copying the fields set in a
+// patch model into a model. It requires WithExtendedGen
+func WithApplyPatch() Option {
+	return withTableFlag("WithApplyPatch", true)
+}
+
+// WithCopyCommonFields generates a CopyCommonFields method copying the fields
+// of a model into a model of another table. It requires WithExtendedGen
+func WithCopyCommonFields() Option {
+	return withTableFlag("WithCopyCommonFields", true)
+}
+
+// WithEnumExhaustiveness generates, for each enum, a map literal holding all
+// of its members so that linters can check switches over the enum
+func WithEnumExhaustiveness() Option {
+	return withTableFlag("WithEnumExhaustiveness", true)
+}
+
+// WithEnumValidation generates, for each enum, a function reporting whether a
+// value is one of its members
+func WithEnumValidation() Option {
+	return withTableFlag("WithEnumValidation", true)
+}
+
+// WithEnumStringer generates the enums as types defined over their base type
+// rather than aliases of it, with a String method returning the value of a
+// string enum, or the name of the variable holding a member of another enum.
+// The enums then implement fmt.Stringer, which the mapper requires to map
+// them without a Converter, and their values of the base type need a
+// conversion, e.g. BridgeFailMode(mode)
+func WithEnumStringer() Option {
+	return withTableFlag("WithEnumStringer", true)
+}
+
+// WithStringer generates a String method printing the fields of a model
+func WithStringer() Option {
+	return withTableFlag("WithStringer", true)
+}
+
+// WithTextMarshaler generates MarshalText and UnmarshalText methods rendering
+// and parsing the UUID of a model
+func WithTextMarshaler() Option {
+	return withTableFlag("WithTextMarshaler", true)
+}
+
+// WithEnumPredicates generates, for each member of the single valued string
+// enum columns, a predicate reporting whether the field holds that member
+func WithEnumPredicates() Option {
+	return withTableFlag("WithEnumPredicates", true)
+}
+
+// WithModelMethods generates GetUUID and Table methods returning the UUID of
+// a model and the name of its table
+func WithModelMethods() Option {
+	return withTableFlag("WithModelMethods", true)
+}
+
+// WithMapMerge generates, for each map column, a method setting the given keys
+// in the map of a model while keeping its other keys
+func WithMapMerge() Option {
+	return withTableFlag("WithMapMerge", true)
+}
+
+// WithOptionalGetters generates, for each optional column, a getter returning
+// its value and whether it is set
+func WithOptionalGetters() Option {
+	return withTableFlag("WithOptionalGetters", true)
+}
+
+// WithCardinalityValidation generates a Validate method checking the number
+// of elements of the set and map columns against their min and max bounds
+func WithCardinalityValidation() Option {
+	return withTableFlag("WithCardinalityValidation", true)
+}
+
+// WithFromMap generates a New<StructName>FromMap function building a model
+// from the values of its columns, by column name, without reflection
+func WithFromMap() Option {
+	return withTableFlag("WithFromMap", true)
+}
+
+// WithColumns generates a Columns method returning the names of all the
+// columns of the table, including _uuid, e.g. for the columns to select in a
+// monitor request
+func WithColumns() Option {
+	return withTableFlag("WithColumns", true)
+}
+
+// WithReferenceValidation generates a ValidateReferences method checking that
+// the UUIDs held by the reference columns are the ones of rows of the tables
+// they reference in a cache, see cache.TableCache.CheckReference
+func WithReferenceValidation() Option {
+	return withTableFlag("WithReferenceValidation", true)
+}
+
+// WithInsertRowMinimal generates an InsertRowMinimal method returning the row
+// inserting a model without the columns holding their default value
+func WithInsertRowMinimal() Option {
+	return withTableFlag("WithInsertRowMinimal", true)
+}
+
+// WithColumnTypes generates a map holding the type of each column of the
+// table
+func WithColumnTypes() Option {
+	return withTableFlag("WithColumnTypes", true)
+}
+
+// WithEnumColumns generates a map holding the members of each enum column of
+// the table
+func WithEnumColumns() Option {
+	return withTableFlag("WithEnumColumns", true)
+}
+
+// WithJSONTags adds a json tag named after the column to the struct fields,
+// next to their ovsdb tag, so that models serialize to JSON with the column
+// names (see JSONTag)
+func WithJSONTags() Option {
+	return withTableFlag("WithJSONTags", true)
+}
@@ -3,6 +3,10 @@ package modelgen
 import (
 	"reflect"
 	"testing"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWithDryRun(t *testing.T) {
@@ -72,3 +76,119 @@ func Test_newOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestTableOptions(t *testing.T) {
+	table := tableSchema(t, `{
+		"str": {
+			"type": "string"
+		},
+		"external_ids": {
+			"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}
+		},
+		"protocol": {
+			"type": {"key": {"type": "string",
+					 "enum": ["set", ["tcp", "udp", "sctp"]]},
+					 "min": 0, "max": 1}}
+	}`)
+	base := generateTable(t, "atomicTable", table)
+
+	tests := []struct {
+		flag string
+		opt  Option
+		val  bool
+	}{
+		{"WithEnumTypes", WithoutEnumTypes(), false},
+		{"WithExtendedGen", WithExtendedGen(), true},
+		{"WithDeepCopy", WithDeepCopy(), true},
+		{"WithEquals", WithEquals(), true},
+		{"WithEnumPredicates", WithEnumPredicates(), true},
+		{"WithModelMethods", WithModelMethods(), true},
+		{"WithMapMerge", WithMapMerge(), true},
+		{"WithOptionalGetters", WithOptionalGetters(), true},
+		{"WithCardinalityValidation", WithCardinalityValidation(), true},
+		{"WithFromMap", WithFromMap(), true},
+		{"WithEnumStringer", WithEnumStringer(), true},
+		{"WithColumns", WithColumns(), true},
+		{"WithReferenceValidation", WithReferenceValidation(), true},
+		{"WithColumnSchema", WithColumnSchema(), true},
+		{"WithBuilder", WithBuilder(), true},
+		{"WithFieldColumnMaps", WithFieldColumnMaps(), true},
+		{"WithEnumExhaustiveness", WithEnumExhaustiveness(), true},
+		{"WithEnumValidation", WithEnumValidation(), true},
+		{"WithStringer", WithStringer(), true},
+		{"WithTextMarshaler", WithTextMarshaler(), true},
+		{"WithInsertRowMinimal", WithInsertRowMinimal(), true},
+		{"WithColumnTypes", WithColumnTypes(), true},
+		{"WithEnumColumns", WithEnumColumns(), true},
+		{"WithJSONTags", WithJSONTags(), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.flag, func(t *testing.T) {
+			data := tableTemplateData(t, "atomicTable", table, tt.opt)
+			assert.Equal(t, tt.val, data[tt.flag])
+			assert.NotEqual(t, base, formatTable(t, data))
+		})
+	}
+
+	// the options depending on WithExtendedGen generate code only with it
+	for _, tt := range []struct {
+		flag string
+		opt  Option
+	}{
+		{"WithApplyPatch", WithApplyPatch()},
+		{"WithCopyCommonFields", WithCopyCommonFields()},
+	} {
+		t.Run(tt.flag, func(t *testing.T) {
+			extended := generateTable(t, "atomicTable", table, WithExtendedGen())
+			data := tableTemplateData(t, "atomicTable", table, WithExtendedGen(), tt.opt)
+			assert.Equal(t, true, data[tt.flag])
+			assert.NotEqual(t, extended, formatTable(t, data))
+		})
+	}
+
+	t.Run("json tags", func(t *testing.T) {
+		code := generateTable(t, "atomicTable", table, WithJSONTags())
+		assert.Contains(t, code, "`ovsdb:\"str\" json:\"str,omitempty\"`")
+		assert.Contains(t, code, "`ovsdb:\"_uuid\" json:\"uuid,omitempty\"`")
+	})
+}
+
+func TestWithBuildTag(t *testing.T) {
+	table := ovsdb.TableSchema{Columns: map[string]*ovsdb.ColumnSchema{}}
+	data := tableTemplateData(t, "atomicTable", &table)
+	assert.Equal(t, "", data["BuildTag"])
+	assert.Empty(t, data["TaggedTemplates"])
+
+	data = tableTemplateData(t, "atomicTable", &table, WithBuildTag("helpers", "builder", "stringer"))
+	assert.Equal(t, "helpers", data["BuildTag"])
+	assert.Equal(t, map[string]bool{"builder": true, "stringer": true}, data["TaggedTemplates"])
+	assert.Contains(t, TaggableTemplates(), "builder")
+	assert.Contains(t, TaggableTemplates(), "stringer")
+}
+
+func TestWithFieldNameOverrides(t *testing.T) {
+	o, err := newOptions(
+		WithFieldNameOverrides(map[string]map[string]string{"table": {"ip": "IPAddress"}}),
+		WithFieldNameOverrides(map[string]map[string]string{"table": {"mac": "MACAddress"}}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{"table": {"ip": "IPAddress", "mac": "MACAddress"}}, o.fieldNames)
+
+	for _, name := range []string{"ipAddress", "IP Address", "2IP", ""} {
+		_, err = newOptions(WithFieldNameOverrides(map[string]map[string]string{"table": {"ip": name}}))
+		assert.Error(t, err, name)
+	}
+	_, err = newOptions(WithFieldNameOverrides(map[string]map[string]string{"table": {"_uuid": "ID"}}))
+	assert.Error(t, err)
+
+	// the template data is not returned with invalid options
+	invalid := WithFieldNameOverrides(map[string]map[string]string{"table": {"ip": "ip"}})
+	table := ovsdb.TableSchema{Columns: map[string]*ovsdb.ColumnSchema{"ip": {Type: ovsdb.TypeString}}}
+	schema := ovsdb.DatabaseSchema{Name: "AtomicDB", Tables: map[string]ovsdb.TableSchema{"table": table}}
+	_, err = GetTableTemplateData("test", "table", &table, invalid)
+	assert.Error(t, err)
+	_, err = GetTablesTemplateData("test", schema, invalid)
+	assert.Error(t, err)
+	_, err = GetDBTemplateData("test", schema, invalid)
+	assert.Error(t, err)
+}
@@ -1,10 +1,13 @@
 package modelgen
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
+	"unicode"
 
 	"github.com/ovn-org/libovsdb/ovsdb"
 )
@@ -16,22 +19,51 @@ var extendedGenTemplate = `
 {{- define "equalExtraFields" }}{{ end }}
 {{- define "extendedGenImports" }}
 {{- if index . "WithExtendedGen" }}
+{{- $tableName := index . "TableName" }}
+{{- $sort := false }}
+{{- range $field := index . "Fields" }}
+{{- $type := "" }}
+{{- if index $ "WithEnumTypes" }}
+{{- $type = FieldTypeWithEnum $tableName $field.Column $field.Schema $field.EnumType }}
+{{- else }}
+{{- $type = FieldType $tableName $field.Column $field.Schema }}
+{{- end }}
+{{- with $field.Type }}{{ $type = . }}{{ end }}
+{{- if eq (index $type 0) '[' }}
+{{- $sort = true }}
+{{- end }}
+{{- end }}
+{{- if $sort }}
+import (
+	"sort"
+
+	"github.com/ovn-org/libovsdb/model"
+)
+{{- else }}
 import "github.com/ovn-org/libovsdb/model"
 {{- end }}
 {{- end }}
+{{- end }}
 {{- define "extendedGen" }}
-{{- if index . "WithExtendedGen" }}
+{{- template "extendedGenHelpers" . }}
+{{- template "extendedGenMethods" . }}
+{{- end }}
+{{- define "extendedGenHelpers" }}
+{{- if or (index . "WithExtendedGen") (index . "WithDeepCopy") (index . "WithEquals") }}
 {{- $tableName := index . "TableName" }}
 {{- $structName := index . "StructName" }}
 {{- range $field := index . "Fields" }}
-{{- $fieldName := FieldName $field.Column }}
+{{- $fieldName := or $field.Name (FieldName $field.Column) }}
 {{- $type := "" }}
 {{- if index $ "WithEnumTypes" }}
-{{- $type = FieldTypeWithEnums $tableName $field.Column $field.Schema }}
+{{- $type = FieldTypeWithEnum $tableName $field.Column $field.Schema $field.EnumType }}
 {{- else }}
 {{- $type = FieldType $tableName $field.Column $field.Schema }}
 {{- end }}
-{{- if or (eq (index $type 0) '*') (eq (slice $type 0 2) "[]") (eq (slice $type 0 3) "map") }}
+{{- with $field.Type }}{{ $type = . }}{{ end }}
+{{- $array := and (eq (index $type 0) '[') (ne (slice $type 0 2) "[]") }}
+{{- if or (eq (index $type 0) '*') (eq (index $type 0) '[') (eq (slice $type 0 3) "map") }}
+{{- if and (not $array) (or (index $ "WithExtendedGen") (index $ "WithDeepCopy")) }}
 func copy{{ $structName }}{{ $fieldName }}(a {{ $type }}) {{ $type }} {
 	if a == nil {
 		return nil
@@ -51,24 +83,57 @@ func copy{{ $structName }}{{ $fieldName }}(a {{ $type }}) {{ $type }} {
 	return b
 	{{- end }}
 }
-
+{{ end }}
+{{- if or (index $ "WithExtendedGen") (index $ "WithEquals") }}
 func equal{{ $structName }}{{ $fieldName }}(a, b {{ $type }}) bool {
+	{{- if eq (index $type 0) '*' }}
 	if (a == nil) != (b == nil) {
 		return false
 	}
-	{{- if eq (index $type 0) '*' }}
 	if a == b {
 		return true
 	}
 	return *a == *b
+	{{- else if $array }}
+	if a == b {
+		return true
+	}
+	// sets are unordered
+	counts := make(map[{{ ElemType $type }}]int, len(a))
+	for _, v := range a {
+		counts[v]++
+	}
+	for _, v := range b {
+		if counts[v] == 0 {
+			return false
+		}
+		counts[v]--
+	}
+	return true
 	{{- else if eq (slice $type 0 2) "[]" }}
 	if len(a) != len(b) {
 		return false
 	}
+	ordered := true
 	for i, v := range a {
 		if b[i] != v {
+			ordered = false
+			break
+		}
+	}
+	if ordered {
+		return true
+	}
+	// sets are unordered
+	counts := make(map[{{ ElemType $type }}]int, len(a))
+	for _, v := range a {
+		counts[v]++
+	}
+	for _, v := range b {
+		if counts[v] == 0 {
 			return false
 		}
+		counts[v]--
 	}
 	return true
 	{{- else }}
@@ -83,20 +148,71 @@ func equal{{ $structName }}{{ $fieldName }}(a, b {{ $type }}) bool {
 	return true
 	{{- end }}
 }
+{{ end }}
+{{ end }}
+{{- if and (index $ "WithExtendedGen") (eq (index $type 0) '[') }}
+func normalize{{ $structName }}{{ $fieldName }}(a {{ $type }}) {{ $type }} {
+	{{- if $array }}
+	// the distinct elements come first, in order, followed by zero values
+	s := a[:]
+	sort.Slice(s, func(i, j int) bool {
+		{{- if eq (ElemType $type) "bool" }}
+		return !s[i] && s[j]
+		{{- else }}
+		return s[i] < s[j]
+		{{- end }}
+	})
+	var b {{ $type }}
+	j := 0
+	for i, v := range s {
+		if i == 0 || v != s[i-1] {
+			b[j] = v
+			j++
+		}
+	}
+	return b
+	{{- else }}
+	if len(a) == 0 {
+		return {{ $type }}{}
+	}
+	sort.Slice(a, func(i, j int) bool {
+		{{- if eq (ElemType $type) "bool" }}
+		return !a[i] && a[j]
+		{{- else }}
+		return a[i] < a[j]
+		{{- end }}
+	})
+	j := 0
+	for i := 1; i < len(a); i++ {
+		if a[i] != a[j] {
+			j++
+			a[j] = a[i]
+		}
+	}
+	return a[:j+1]
+	{{- end }}
+}
 
 {{ end }}
 {{- end }}
+{{- end }}
+{{- end }}
+{{- define "deepCopy" }}
+{{- if or (index . "WithExtendedGen") (index . "WithDeepCopy") }}
+{{- $tableName := index . "TableName" }}
+{{- $structName := index . "StructName" }}
 
 func (a *{{ $structName }}) DeepCopyInto(b *{{ $structName }}) {
 	*b = *a
 	{{- range $field := index . "Fields" }}
-	{{- $fieldName := FieldName $field.Column }}
+	{{- $fieldName := or $field.Name (FieldName $field.Column) }}
 	{{- $type := "" }}
 	{{- if index $ "WithEnumTypes" }}
-	{{- $type = FieldTypeWithEnums $tableName $field.Column $field.Schema }}
+	{{- $type = FieldTypeWithEnum $tableName $field.Column $field.Schema $field.EnumType }}
 	{{- else }}
 	{{- $type = FieldType $tableName $field.Column $field.Schema }}
 	{{- end }}
+	{{- with $field.Type }}{{ $type = . }}{{ end }}
 	{{- if or (eq (index $type 0) '*') (eq (slice $type 0 2) "[]") (eq (slice $type 0 3) "map") }}
 	b.{{ $fieldName }} = copy{{ $structName }}{{ $fieldName }}(a.{{ $fieldName }})
 	{{- end }}
@@ -109,28 +225,26 @@ func (a *{{ $structName }}) DeepCopy() *{{ $structName }} {
 	a.DeepCopyInto(b)
 	return b
 }
-
-func (a *{{ $structName }}) CloneModelInto(b model.Model) {
-	c := b.(*{{ $structName }})
-	a.DeepCopyInto(c)
-}
-
-func (a *{{ $structName }}) CloneModel() model.Model {
-	return a.DeepCopy()
-}
+{{- end }}
+{{- end }}
+{{- define "equals" }}
+{{- if or (index . "WithExtendedGen") (index . "WithEquals") }}
+{{- $tableName := index . "TableName" }}
+{{- $structName := index . "StructName" }}
 
 func (a *{{ $structName }}) Equals(b *{{ $structName }}) bool {
 	{{- range $i, $field := index . "Fields" }}
-	{{- $fieldName := FieldName $field.Column }}
+	{{- $fieldName := or $field.Name (FieldName $field.Column) }}
 	{{- $type := "" }}
 	{{- if index $ "WithEnumTypes" }}
-	{{- $type = FieldTypeWithEnums $tableName $field.Column $field.Schema }}
+	{{- $type = FieldTypeWithEnum $tableName $field.Column $field.Schema $field.EnumType }}
 	{{- else }}
 	{{- $type = FieldType $tableName $field.Column $field.Schema }}
 	{{- end }}
+	{{- with $field.Type }}{{ $type = . }}{{ end }}
 	{{- if $i }}&&
 	{{ else }}return {{ end }}
-	{{- if or (eq (index $type 0) '*') (eq (slice $type 0 2) "[]") (eq (slice $type 0 3) "map") -}}
+	{{- if or (eq (index $type 0) '*') (eq (index $type 0) '[') (eq (slice $type 0 3) "map") -}}
 	equal{{ $structName }}{{ $fieldName }}(a.{{ $fieldName }}, b.{{ $fieldName }})
 	{{- else -}}
 	a.{{ $fieldName }} == b.{{ $fieldName }}
@@ -138,16 +252,742 @@ func (a *{{ $structName }}) Equals(b *{{ $structName }}) bool {
 	{{- end }}
 	{{- template "equalExtraFields" . }}
 }
+{{- end }}
+{{- end }}
+{{- define "extendedGenMethods" }}
+{{- template "deepCopy" . }}
+{{- if index . "WithExtendedGen" }}
+{{- $tableName := index . "TableName" }}
+{{- $structName := index . "StructName" }}
+
+func (a *{{ $structName }}) CloneModelInto(b model.Model) {
+	c := b.(*{{ $structName }})
+	a.DeepCopyInto(c)
+}
+
+func (a *{{ $structName }}) CloneModel() model.Model {
+	return a.DeepCopy()
+}
+{{- end }}
+{{- template "equals" . }}
+{{- if index . "WithExtendedGen" }}
+{{- $tableName := index . "TableName" }}
+{{- $structName := index . "StructName" }}
 
 func (a *{{ $structName }}) EqualsModel(b model.Model) bool {
 	c := b.(*{{ $structName }})
 	return a.Equals(c)
 }
 
+func (a *{{ $structName }}) Normalize() {
+	{{- range $field := index . "Fields" }}
+	{{- $fieldName := or $field.Name (FieldName $field.Column) }}
+	{{- $type := "" }}
+	{{- if index $ "WithEnumTypes" }}
+	{{- $type = FieldTypeWithEnum $tableName $field.Column $field.Schema $field.EnumType }}
+	{{- else }}
+	{{- $type = FieldType $tableName $field.Column $field.Schema }}
+	{{- end }}
+	{{- with $field.Type }}{{ $type = . }}{{ end }}
+	{{- if eq (index $type 0) '[' }}
+	a.{{ $fieldName }} = normalize{{ $structName }}{{ $fieldName }}(a.{{ $fieldName }})
+	{{- else if eq (slice $type 0 3) "map" }}
+	if a.{{ $fieldName }} == nil {
+		a.{{ $fieldName }} = {{ $type }}{}
+	}
+	{{- end }}
+	{{- end }}
+}
+
+{{- if index . "WithApplyPatch" }}
+{{- $zero := false }}
+{{- range $field := index . "Fields" }}
+{{- $type := "" }}
+{{- if index $ "WithEnumTypes" }}
+{{- $type = FieldTypeWithEnum $tableName $field.Column $field.Schema $field.EnumType }}
+{{- else }}
+{{- $type = FieldType $tableName $field.Column $field.Schema }}
+{{- end }}
+{{- with $field.Type }}{{ $type = . }}{{ end }}
+{{- if not (or (eq $field.Column "_uuid") (eq (index $type 0) '*') (eq (slice $type 0 2) "[]") (eq (slice $type 0 3) "map")) }}
+{{- $zero = true }}
+{{- end }}
+{{- end }}
+
+// ApplyPatch copies the fields of patch that are set into a: the pointer, slice
+// and map fields that are not nil, and the other fields that do not hold their
+// zero value. The UUID is never copied
+func (a *{{ $structName }}) ApplyPatch(patch *{{ $structName }}) {
+	{{- if $zero }}
+	var zero {{ $structName }}
+	{{- end }}
+	{{- range $field := index . "Fields" }}
+	{{- if ne $field.Column "_uuid" }}
+	{{- $fieldName := or $field.Name (FieldName $field.Column) }}
+	{{- $type := "" }}
+	{{- if index $ "WithEnumTypes" }}
+	{{- $type = FieldTypeWithEnum $tableName $field.Column $field.Schema $field.EnumType }}
+	{{- else }}
+	{{- $type = FieldType $tableName $field.Column $field.Schema }}
+	{{- end }}
+	{{- with $field.Type }}{{ $type = . }}{{ end }}
+	{{- if or (eq (index $type 0) '*') (eq (slice $type 0 2) "[]") (eq (slice $type 0 3) "map") }}
+	if patch.{{ $fieldName }} != nil {
+		a.{{ $fieldName }} = copy{{ $structName }}{{ $fieldName }}(patch.{{ $fieldName }})
+	}
+	{{- else }}
+	if patch.{{ $fieldName }} != zero.{{ $fieldName }} {
+		a.{{ $fieldName }} = patch.{{ $fieldName }}
+	}
+	{{- end }}
+	{{- end }}
+	{{- end }}
+}
+{{- end }}
+{{- if index . "WithCopyCommonFields" }}
+
+// CopyCommonFields copies the fields of a into the fields of dst, a pointer to
+// a model of any table, that are mapped to columns of the same name and have
+// the same type. The other fields of dst are left untouched, and the UUID is
+// never copied
+func (a *{{ $structName }}) CopyCommonFields(dst interface{}) {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	v = v.Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+		switch v.Type().Field(i).Tag.Get("ovsdb") {
+		{{- range $field := index . "Fields" }}
+		{{- if ne $field.Column "_uuid" }}
+		{{- $fieldName := or $field.Name (FieldName $field.Column) }}
+		{{- $type := "" }}
+		{{- if index $ "WithEnumTypes" }}
+		{{- $type = FieldTypeWithEnum $tableName $field.Column $field.Schema $field.EnumType }}
+		{{- else }}
+		{{- $type = FieldType $tableName $field.Column $field.Schema }}
+		{{- end }}
+		{{- with $field.Type }}{{ $type = . }}{{ end }}
+		case "{{ $field.Column }}":
+			if field.Type() == reflect.TypeOf(a.{{ $fieldName }}) {
+				{{- if or (eq (index $type 0) '*') (eq (slice $type 0 2) "[]") (eq (slice $type 0 3) "map") }}
+				field.Set(reflect.ValueOf(copy{{ $structName }}{{ $fieldName }}(a.{{ $fieldName }})))
+				{{- else }}
+				field.Set(reflect.ValueOf(a.{{ $fieldName }}))
+				{{- end }}
+			}
+		{{- end }}
+		{{- end }}
+		}
+	}
+}
+{{- end }}
+
 var _ model.CloneableModel = &{{ $structName }}{}
 var _ model.ComparableModel = &{{ $structName }}{}
 {{- end }}
 {{- end }}
+{{- define "columnSchemaImports" }}
+{{- if or (index . "WithColumnSchema") (index . "WithColumnTypes") }}
+import (
+	"encoding/json"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+)
+{{- end }}
+{{- end }}
+{{- define "columnSchema" }}
+{{- template "columnSchemaHelpers" . }}
+{{- template "columnSchemaMethods" . }}
+{{- end }}
+{{- define "columnSchemaHelpers" }}
+{{- if index . "WithColumnSchema" }}
+{{- $structName := index . "StructName" }}
+var schema{{ $structName }} = func() ovsdb.TableSchema {
+	var s ovsdb.TableSchema
+	err := json.Unmarshal([]byte({{ JSONLiteral (index . "TableSchema") }}), &s)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}()
+{{- end }}
+{{- end }}
+{{- define "columnTypes" }}
+{{- if index . "WithColumnTypes" }}
+{{- $structName := index . "StructName" }}
+
+// {{ $structName }}ColumnTypes holds the type of each column of {{ $structName }}
+var {{ $structName }}ColumnTypes = func() map[string]ovsdb.ColumnType {
+	var t map[string]ovsdb.ColumnType
+	err := json.Unmarshal([]byte({{ JSONLiteral (index . "ColumnTypes") }}), &t)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}()
+{{- end }}
+{{- end }}
+{{- define "enumColumns" }}
+{{- if index . "WithEnumColumns" }}
+{{- $structName := index . "StructName" }}
+
+// {{ $structName }}EnumColumns holds the members of each enum column of
+// {{ $structName }}
+var {{ $structName }}EnumColumns = map[string][]string{
+{{- range $e := index . "Enums" }}
+	{{ printf "%q" $e.Column }}: {
+	{{- range $i, $member := $e.Sets }}{{ if $i }}, {{ end }}{{ printf "%q" (printf "%v" $member) }}{{ end -}}
+	},
+{{- end }}
+}
+{{- end }}
+{{- end }}
+{{- define "columnSchemaMethods" }}
+{{- if index . "WithColumnSchema" }}
+{{- $structName := index . "StructName" }}
+
+func (a {{ $structName }}) ColumnSchema(column string) (ovsdb.ColumnSchema, bool) {
+	c := schema{{ $structName }}.Column(column)
+	if c == nil {
+		return ovsdb.ColumnSchema{}, false
+	}
+	return *c, true
+}
+{{- end }}
+{{- end }}
+{{- define "builder" }}
+{{- if index . "WithBuilder" }}
+{{- $tableName := index . "TableName" }}
+{{- $structName := index . "StructName" }}
+
+// {{ $structName }}Builder builds a {{ $structName }} with method chaining
+type {{ $structName }}Builder struct {
+	model {{ $structName }}
+}
+
+// New{{ $structName }}Builder returns a new {{ $structName }}Builder
+func New{{ $structName }}Builder() *{{ $structName }}Builder {
+	return &{{ $structName }}Builder{}
+}
+{{ range $field := index . "Fields" }}
+{{- $fieldName := or $field.Name (FieldName $field.Column) }}
+{{- $type := "" }}
+{{- if index $ "WithEnumTypes" }}
+{{- $type = FieldTypeWithEnum $tableName $field.Column $field.Schema $field.EnumType }}
+{{- else }}
+{{- $type = FieldType $tableName $field.Column $field.Schema }}
+{{- end }}
+{{- with $field.Type }}{{ $type = . }}{{ end }}
+{{- if eq (index $type 0) '*' }}
+func (b *{{ $structName }}Builder) {{ $fieldName }}(v {{ slice $type 1 }}) *{{ $structName }}Builder {
+	b.model.{{ $fieldName }} = &v
+	return b
+}
+{{- else if eq (slice $type 0 2) "[]" }}
+func (b *{{ $structName }}Builder) {{ $fieldName }}(v ...{{ slice $type 2 }}) *{{ $structName }}Builder {
+	b.model.{{ $fieldName }} = v
+	return b
+}
+{{- else }}
+func (b *{{ $structName }}Builder) {{ $fieldName }}(v {{ $type }}) *{{ $structName }}Builder {
+	b.model.{{ $fieldName }} = v
+	return b
+}
+{{- end }}
+{{ end }}
+func (b *{{ $structName }}Builder) Build() *{{ $structName }} {
+	m := b.model
+	return &m
+}
+{{- end }}
+{{- end }}
+{{- define "fieldColumnMaps" }}
+{{- if index . "WithFieldColumnMaps" }}
+{{- $structName := index . "StructName" }}
+
+// {{ $structName }}FieldToColumn maps the fields of {{ $structName }} to their columns
+var {{ $structName }}FieldToColumn = map[string]string{
+{{- range $field := index . "Fields" }}
+	"{{ or $field.Name (FieldName $field.Column) }}": "{{ $field.Column }}",
+{{- end }}
+}
+
+// {{ $structName }}ColumnToField maps the columns of {{ $structName }} to their fields
+var {{ $structName }}ColumnToField = map[string]string{
+{{- range $field := index . "Fields" }}
+	"{{ $field.Column }}": "{{ or $field.Name (FieldName $field.Column) }}",
+{{- end }}
+}
+{{- end }}
+{{- end }}
+{{- define "stringerImports" }}
+{{- if index . "WithStringer" }}
+import "fmt"
+{{- end }}
+{{- end }}
+{{- define "stringer" }}
+{{- if index . "WithStringer" }}
+{{- $tableName := index . "TableName" }}
+{{- $structName := index . "StructName" }}
+
+// String returns a readable representation of all the fields of the
+// {{ $structName }} that is stable across runs: optional fields are
+// dereferenced and maps are printed with their keys sorted
+func (a *{{ $structName }}) String() string {
+	s := "{{ $structName }}{"
+	{{- range $i, $field := index . "Fields" }}
+	{{- $fieldName := or $field.Name (FieldName $field.Column) }}
+	{{- $type := "" }}
+	{{- if index $ "WithEnumTypes" }}
+	{{- $type = FieldTypeWithEnum $tableName $field.Column $field.Schema $field.EnumType }}
+	{{- else }}
+	{{- $type = FieldType $tableName $field.Column $field.Schema }}
+	{{- end }}
+	{{- with $field.Type }}{{ $type = . }}{{ end }}
+	{{- $sep := ", " }}
+	{{- if not $i }}
+	{{- $sep = "" }}
+	{{- end }}
+	{{- if eq (index $type 0) '*' }}
+	if a.{{ $fieldName }} == nil {
+		s += "{{ $sep }}{{ $fieldName }}: nil"
+	} else {
+		s += fmt.Sprintf("{{ $sep }}{{ $fieldName }}: {{ FormatVerb $field.Schema }}", *a.{{ $fieldName }})
+	}
+	{{- else }}
+	s += fmt.Sprintf("{{ $sep }}{{ $fieldName }}: {{ FormatVerb $field.Schema }}", a.{{ $fieldName }})
+	{{- end }}
+	{{- end }}
+	return s + "}"
+}
+{{- end }}
+{{- end }}
+{{- define "textMarshaler" }}
+{{- if index . "WithTextMarshaler" }}
+{{- $structName := index . "StructName" }}
+
+// MarshalText returns the identity of the {{ $structName }}, its UUID
+func (a *{{ $structName }}) MarshalText() ([]byte, error) {
+	return []byte(a.UUID), nil
+}
+
+// UnmarshalText sets the identity of the {{ $structName }}, its UUID, leaving
+// the other fields untouched
+func (a *{{ $structName }}) UnmarshalText(text []byte) error {
+	a.UUID = string(text)
+	return nil
+}
+{{- end }}
+{{- end }}
+{{- define "insertRowMinimalImports" }}
+{{- if index . "WithInsertRowMinimal" }}
+import "github.com/ovn-org/libovsdb/mapper"
+{{- if not (or (index . "WithColumnSchema") (index . "WithColumnTypes")) }}
+import "github.com/ovn-org/libovsdb/ovsdb"
+{{- end }}
+{{- end }}
+{{- end }}
+{{- define "insertRowMinimal" }}
+{{- if index . "WithInsertRowMinimal" }}
+{{- $structName := index . "StructName" }}
+{{- $tableName := index . "TableName" }}
+{{- $zero := false }}
+{{- range $field := index . "Fields" }}
+{{- $type := "" }}
+{{- if index $ "WithEnumTypes" }}
+{{- $type = FieldTypeWithEnum $tableName $field.Column $field.Schema $field.EnumType }}
+{{- else }}
+{{- $type = FieldType $tableName $field.Column $field.Schema }}
+{{- end }}
+{{- with $field.Type }}{{ $type = . }}{{ end }}
+{{- if not (or (eq $field.Column "_uuid") (eq (index $type 0) '*') (eq (slice $type 0 2) "[]") (eq (slice $type 0 3) "map")) }}
+{{- $zero = true }}
+{{- end }}
+{{- end }}
+
+// InsertRowMinimal returns the row inserting the {{ $structName }} with the
+// given schema of the {{ $tableName }} table. The columns holding the default
+// value of their type are omitted, as the server fills them in
+func (a *{{ $structName }}) InsertRowMinimal(schema *ovsdb.TableSchema) (ovsdb.Row, error) {
+	info, err := mapper.NewInfo("{{ $tableName }}", schema, a)
+	if err != nil {
+		return nil, err
+	}
+	row, err := mapper.Mapper{}.NewRow(info)
+	if err != nil {
+		return nil, err
+	}
+	delete(row, "_uuid")
+	delete(row, "_version")
+	{{- if $zero }}
+	var zero {{ $structName }}
+	{{- end }}
+	{{- range $field := index . "Fields" }}
+	{{- if ne $field.Column "_uuid" }}
+	{{- $type := "" }}
+	{{- if index $ "WithEnumTypes" }}
+	{{- $type = FieldTypeWithEnum $tableName $field.Column $field.Schema $field.EnumType }}
+	{{- else }}
+	{{- $type = FieldType $tableName $field.Column $field.Schema }}
+	{{- end }}
+	{{- with $field.Type }}{{ $type = . }}{{ end }}
+	{{- if not (or (eq (index $type 0) '*') (eq (slice $type 0 2) "[]") (eq (slice $type 0 3) "map")) }}
+	if a.{{ or $field.Name (FieldName $field.Column) }} == zero.{{ or $field.Name (FieldName $field.Column) }} {
+		delete(row, "{{ $field.Column }}")
+	}
+	{{- end }}
+	{{- end }}
+	{{- end }}
+	return row, nil
+}
+{{- end }}
+{{- end }}
+{{- define "enumPredicates" }}
+{{- if index . "WithEnumPredicates" }}
+{{- $tableName := index . "TableName" }}
+{{- $structName := index . "StructName" }}
+{{- range $field := index . "Fields" }}
+{{- $fieldName := or $field.Name (FieldName $field.Column) }}
+{{- $type := "" }}
+{{- if index $ "WithEnumTypes" }}
+{{- $type = FieldTypeWithEnum $tableName $field.Column $field.Schema $field.EnumType }}
+{{- else }}
+{{- $type = FieldType $tableName $field.Column $field.Schema }}
+{{- end }}
+{{- with $field.Type }}{{ $type = . }}{{ end }}
+{{- range $e := index $ "Enums" }}
+{{- if and (eq $e.Column $field.Column) (eq $e.Type "string") (ne (index $type 0) '[') }}
+{{- range $member := $e.Sets }}
+{{- $memberName := $e.MemberName $member }}
+{{- $val := PrintVal $member $e.Type }}
+{{- if index $ "WithEnumTypes" }}
+{{- $val = printf "%s%s" $e.Alias $memberName }}
+{{- end }}
+
+// Is{{ $fieldName }}{{ $memberName }} reports whether the {{ $fieldName }} of the
+// {{ $structName }} is {{ printf "%q" (printf "%v" $member) }}
+func (a *{{ $structName }}) Is{{ $fieldName }}{{ $memberName }}() bool {
+	{{- if eq (index $type 0) '*' }}
+	return a.{{ $fieldName }} != nil && *a.{{ $fieldName }} == {{ $val }}
+	{{- else }}
+	return a.{{ $fieldName }} == {{ $val }}
+	{{- end }}
+}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- define "modelMethods" }}
+{{- if index . "WithModelMethods" }}
+{{- $structName := index . "StructName" }}
+
+// GetUUID returns the UUID of the {{ $structName }}
+func (a *{{ $structName }}) GetUUID() string {
+	return a.{{ (index (index . "Fields") 0).Name }}
+}
+
+// Table returns the name of the table of the {{ $structName }}
+func (a *{{ $structName }}) Table() string {
+	return Table{{ $structName }}
+}
+{{- end }}
+{{- end }}
+{{- define "mapMerge" }}
+{{- if index . "WithMapMerge" }}
+{{- $tableName := index . "TableName" }}
+{{- $structName := index . "StructName" }}
+{{- range $field := index . "Fields" }}
+{{- $fieldName := or $field.Name (FieldName $field.Column) }}
+{{- $type := "" }}
+{{- if index $ "WithEnumTypes" }}
+{{- $type = FieldTypeWithEnum $tableName $field.Column $field.Schema $field.EnumType }}
+{{- else }}
+{{- $type = FieldType $tableName $field.Column $field.Schema }}
+{{- end }}
+{{- with $field.Type }}{{ $type = . }}{{ end }}
+{{- if eq (slice $type 0 3) "map" }}
+
+// Merge{{ $fieldName }} sets the keys of kv in the {{ $fieldName }} of the
+// {{ $structName }}, keeping its other keys
+func (a *{{ $structName }}) Merge{{ $fieldName }}(kv {{ $type }}) {
+	if len(kv) == 0 {
+		return
+	}
+	if a.{{ $fieldName }} == nil {
+		a.{{ $fieldName }} = make({{ $type }}, len(kv))
+	}
+	for k, v := range kv {
+		a.{{ $fieldName }}[k] = v
+	}
+}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- define "optionalGetters" }}
+{{- if index . "WithOptionalGetters" }}
+{{- $tableName := index . "TableName" }}
+{{- $structName := index . "StructName" }}
+{{- range $field := index . "Fields" }}
+{{- $fieldName := or $field.Name (FieldName $field.Column) }}
+{{- $type := "" }}
+{{- if index $ "WithEnumTypes" }}
+{{- $type = FieldTypeWithEnum $tableName $field.Column $field.Schema $field.EnumType }}
+{{- else }}
+{{- $type = FieldType $tableName $field.Column $field.Schema }}
+{{- end }}
+{{- with $field.Type }}{{ $type = . }}{{ end }}
+{{- if eq (slice $type 0 1) "*" }}
+
+// Get{{ $fieldName }} returns the {{ $fieldName }} of the {{ $structName }}, if it is set
+func (a *{{ $structName }}) Get{{ $fieldName }}() ({{ slice $type 1 }}, bool) {
+	if a.{{ $fieldName }} == nil {
+		var zero {{ slice $type 1 }}
+		return zero, false
+	}
+	return *a.{{ $fieldName }}, true
+}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- define "cardinalityValidationImports" }}
+{{- if and (index . "WithCardinalityValidation") (not (or (index . "WithStringer") (index . "WithFromMap"))) }}
+{{- $tableName := index . "TableName" }}
+{{- $bounded := false }}
+{{- range $field := index . "Fields" }}
+{{- $type := "" }}
+{{- if index $ "WithEnumTypes" }}
+{{- $type = FieldTypeWithEnum $tableName $field.Column $field.Schema $field.EnumType }}
+{{- else }}
+{{- $type = FieldType $tableName $field.Column $field.Schema }}
+{{- end }}
+{{- with $field.Type }}{{ $type = . }}{{ end }}
+{{- if and (or (eq (slice $type 0 2) "[]") (eq (slice $type 0 3) "map")) (or (gt $field.Min 0) (ne $field.Max -1)) }}
+{{- $bounded = true }}
+{{- end }}
+{{- end }}
+{{- if $bounded }}
+import "fmt"
+{{- end }}
+{{- end }}
+{{- end }}
+{{- define "cardinalityValidation" }}
+{{- if index . "WithCardinalityValidation" }}
+{{- $tableName := index . "TableName" }}
+{{- $structName := index . "StructName" }}
+
+// Validate checks that the number of elements of each set and map column of
+// the {{ $structName }} is within the bounds of the schema
+func (a *{{ $structName }}) Validate() error {
+{{- range $field := index . "Fields" }}
+{{- $fieldName := or $field.Name (FieldName $field.Column) }}
+{{- $type := "" }}
+{{- if index $ "WithEnumTypes" }}
+{{- $type = FieldTypeWithEnum $tableName $field.Column $field.Schema $field.EnumType }}
+{{- else }}
+{{- $type = FieldType $tableName $field.Column $field.Schema }}
+{{- end }}
+{{- with $field.Type }}{{ $type = . }}{{ end }}
+{{- if or (eq (slice $type 0 2) "[]") (eq (slice $type 0 3) "map") }}
+{{- if gt $field.Min 0 }}
+	if len(a.{{ $fieldName }}) < {{ $field.Min }} {
+		return fmt.Errorf("column {{ $field.Column }} of {{ $tableName }} requires at least {{ $field.Min }} elements, has %d", len(a.{{ $fieldName }}))
+	}
+{{- end }}
+{{- if ne $field.Max -1 }}
+	if len(a.{{ $fieldName }}) > {{ $field.Max }} {
+		return fmt.Errorf("column {{ $field.Column }} of {{ $tableName }} allows at most {{ $field.Max }} elements, has %d", len(a.{{ $fieldName }}))
+	}
+{{- end }}
+{{- end }}
+{{- end }}
+	return nil
+}
+{{- end }}
+{{- end }}
+{{- define "fromMapImports" }}
+{{- if and (index . "WithFromMap") (not (index . "WithStringer")) }}
+import "fmt"
+{{- end }}
+{{- end }}
+{{- define "fromMap" }}
+{{- if index . "WithFromMap" }}
+{{- $tableName := index . "TableName" }}
+{{- $structName := index . "StructName" }}
+
+// New{{ $structName }}FromMap returns a new {{ $structName }} holding the values
+// of the columns of m, by column name, which are of the types of the fields.
+// The columns missing from m are left unset
+func New{{ $structName }}FromMap(m map[string]interface{}) (*{{ $structName }}, error) {
+	a := &{{ $structName }}{}
+	for column, value := range m {
+		switch column {
+{{- range $field := index . "Fields" }}
+{{- $fieldName := or $field.Name (FieldName $field.Column) }}
+{{- $type := "" }}
+{{- if index $ "WithEnumTypes" }}
+{{- $type = FieldTypeWithEnum $tableName $field.Column $field.Schema $field.EnumType }}
+{{- else }}
+{{- $type = FieldType $tableName $field.Column $field.Schema }}
+{{- end }}
+{{- with $field.Type }}{{ $type = . }}{{ end }}
+		case {{ printf "%q" $field.Column }}:
+			switch v := value.(type) {
+			case {{ $type }}:
+				a.{{ $fieldName }} = v
+			default:
+				return nil, fmt.Errorf("column {{ $field.Column }} of {{ $tableName }} requires a value of type {{ $type }}, has %T", value)
+			}
+{{- end }}
+		default:
+			return nil, fmt.Errorf("unknown column %s of {{ $tableName }}", column)
+		}
+	}
+	return a, nil
+}
+{{- end }}
+{{- end }}
+{{- define "columns" }}
+{{- if index . "WithColumns" }}
+{{- $structName := index . "StructName" }}
+
+// Columns returns the names of all the columns of the {{ $structName }},
+// including _uuid, e.g. to select all of them in a monitor request. The slice
+// is a new one on each call
+func (a *{{ $structName }}) Columns() []string {
+	return []string{
+{{- range index . "Columns" }}
+		{{ printf "%q" .Column }},
+{{- end }}
+	}
+}
+{{- end }}
+{{- end }}
+{{- define "referenceValidationImports" }}
+{{- if index . "WithReferenceValidation" }}
+import "github.com/ovn-org/libovsdb/cache"
+{{- end }}
+{{- end }}
+{{- define "referenceValidation" }}
+{{- if index . "WithReferenceValidation" }}
+{{- $tableName := index . "TableName" }}
+{{- $structName := index . "StructName" }}
+
+// ValidateReferences returns a *cache.ErrMissingReference if a reference column
+// of the {{ $structName }} holds the UUID of a row missing from the table it
+// references in c. The named UUIDs, referencing the rows inserted by the same
+// transaction, are not checked
+func (a *{{ $structName }}) ValidateReferences(c *cache.TableCache) error {
+{{- range $field := index . "Fields" }}
+{{- if and (not $field.Type) (or $field.KeyRefTable $field.ValueRefTable) }}
+{{- $fieldName := or $field.Name (FieldName $field.Column) }}
+{{- $type := FieldType $tableName $field.Column $field.Schema }}
+{{- $args := printf "%q, %q" $tableName $field.Column }}
+{{- if eq (index $type 0) 'm' }}
+	for {{ if $field.KeyRefTable }}key{{ else }}_{{ end }}{{ if $field.ValueRefTable }}, value{{ end }} := range a.{{ $fieldName }} {
+{{- if $field.KeyRefTable }}
+		if err := c.CheckReference({{ $args }}, {{ printf "%q" $field.KeyRefTable }}, key); err != nil {
+			return err
+		}
+{{- end }}
+{{- if $field.ValueRefTable }}
+		if err := c.CheckReference({{ $args }}, {{ printf "%q" $field.ValueRefTable }}, value); err != nil {
+			return err
+		}
+{{- end }}
+	}
+{{- else if eq (index $type 0) '[' }}
+	for _, uuid := range a.{{ $fieldName }} {
+		if err := c.CheckReference({{ $args }}, {{ printf "%q" $field.KeyRefTable }}, uuid); err != nil {
+			return err
+		}
+	}
+{{- else if eq (index $type 0) '*' }}
+	if a.{{ $fieldName }} != nil {
+		if err := c.CheckReference({{ $args }}, {{ printf "%q" $field.KeyRefTable }}, *a.{{ $fieldName }}); err != nil {
+			return err
+		}
+	}
+{{- else }}
+	if err := c.CheckReference({{ $args }}, {{ printf "%q" $field.KeyRefTable }}, a.{{ $fieldName }}); err != nil {
+		return err
+	}
+{{- end }}
+{{- end }}
+{{- end }}
+	return nil
+}
+{{- end }}
+{{- end }}
+{{- define "copyCommonFieldsImports" }}
+{{- if and (index . "WithExtendedGen") (index . "WithCopyCommonFields") }}
+import "reflect"
+{{- end }}
+{{- end }}
+{{- define "methodsImports" }}
+{{- template "stringerImports" . }}
+{{- template "cardinalityValidationImports" . }}
+{{- template "fromMapImports" . }}
+{{- template "referenceValidationImports" . }}
+{{- template "copyCommonFieldsImports" . }}
+{{- if index . "WithInsertRowMinimal" }}
+import "github.com/ovn-org/libovsdb/mapper"
+{{- if not (index . "WithColumnSchema") }}
+import "github.com/ovn-org/libovsdb/ovsdb"
+{{- end }}
+{{- end }}
+{{- if and (index . "WithExtendedGen") (index . "WithColumnSchema") }}
+import (
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb"
+)
+{{- else if index . "WithExtendedGen" }}
+import "github.com/ovn-org/libovsdb/model"
+{{- else if index . "WithColumnSchema" }}
+import "github.com/ovn-org/libovsdb/ovsdb"
+{{- end }}
+{{- end }}
+{{- define "helpersImports" }}
+{{- $tableName := index . "TableName" }}
+{{- $sort := false }}
+{{- if index . "WithExtendedGen" }}
+{{- range $field := index . "Fields" }}
+{{- $type := "" }}
+{{- if index $ "WithEnumTypes" }}
+{{- $type = FieldTypeWithEnum $tableName $field.Column $field.Schema $field.EnumType }}
+{{- else }}
+{{- $type = FieldType $tableName $field.Column $field.Schema }}
+{{- end }}
+{{- with $field.Type }}{{ $type = . }}{{ end }}
+{{- if eq (index $type 0) '[' }}
+{{- $sort = true }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- $schema := or (index . "WithColumnSchema") (index . "WithColumnTypes") }}
+{{- if or $sort $schema }}
+import (
+	{{- if $schema }}
+	"encoding/json"
+	{{- end }}
+	{{- if $sort }}
+	"sort"
+	{{- end }}
+	{{- if $schema }}
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+	{{- end }}
+)
+{{- end }}
+{{- end }}
 `
 
 // NewTableTemplate returns a new table template. It includes the following
@@ -157,10 +997,21 @@ var _ model.ComparableModel = &{{ $structName }}{}
 //   - `preStructDefinitions`: deprecated in favor of `extraImports`
 //   - `extraImports`: include additional imports
 //   - `structComment`: override the comment generated for the table
+//   - `constants`: override the constants holding the names of the table and
+//     its columns
 //   - `extraFields`: add extra fields to the table
 //   - `extraTags`: add tags to the extra fields
+//   - `deepCopy`: override the DeepCopy and DeepCopyInto methods
 //   - `deepCopyExtraFields`: copy extra fields when copying a table
+//   - `equals`: override the Equals method
 //   - `equalExtraFields`: compare extra fields when comparing a table
+//   - `modelMethods`: override the GetUUID and Table methods
+//   - `mapMerge`: override the Merge methods of the map columns
+//   - `optionalGetters`: override the getters of the optional columns
+//   - `cardinalityValidation`: override the Validate method
+//   - `fromMap`: override the New<StructName>FromMap function
+//   - `columns`: override the Columns method
+//   - `referenceValidation`: override the ValidateReferences method
 //   - `postStructDefinitions`: deprecated in favor of `extraDefinitions`
 //   - `extraDefinitions`: include additional definitions like functions etc.
 //
@@ -170,17 +1021,35 @@ var _ model.ComparableModel = &{{ $structName }}{}
 //
 //    - `PrintVal`: prints a field value
 //    - `FieldName`: prints the name of a field based on its column
+//    - `MemberName`: prints the name of an enum member, appended to its type
 //    - `FieldType`: prints the field type based on its column and schema
 //    - `FieldTypeWithEnums`: same as FieldType but with enum type expansion
+//    - `PrefixedFieldTypeWithEnums`: same as FieldTypeWithEnums, with the enum
+//      types prefixed by the StructNamePrefix given as first argument
+//    - `FieldTypeWithEnum`: same as FieldTypeWithEnums, with the enum type
+//      given as last argument, like the EnumType of the field
 //    - `OvsdbTag`: prints the ovsdb tag
+//    - `JSONTag`: prints the json tag
+//    - `FormatVerb`: prints the fmt verb used to print a field based on its schema
+//    - `JSONLiteral`: prints the JSON representation of a value as a Go string
+//      literal
+//    - `ElemType`: prints the type of the elements of a slice or array type
 func NewTableTemplate() *template.Template {
 	return template.Must(template.New("").Funcs(
 		template.FuncMap{
-			"PrintVal":           printVal,
-			"FieldName":          FieldName,
-			"FieldType":          FieldType,
-			"FieldTypeWithEnums": FieldTypeWithEnums,
-			"OvsdbTag":           Tag,
+			"PrintVal":                   printVal,
+			"FieldName":                  FieldName,
+			"MemberName":                 MemberName,
+			"FieldType":                  FieldType,
+			"FieldTypeWithEnums":         FieldTypeWithEnums,
+			"PrefixedFieldTypeWithEnums": prefixedFieldTypeWithEnums,
+			"FieldTypeWithEnum":          fieldType,
+			"OvsdbTag":                   Tag,
+			"JSONTag":                    JSONTag,
+			"FormatVerb":                 formatVerb,
+			"Comment":                    comment,
+			"JSONLiteral":                jsonLiteral,
+			"ElemType":                   elemType,
 		},
 	).Parse(extendedGenTemplate + `
 {{- define "header" }}
@@ -197,12 +1066,90 @@ func NewTableTemplate() *template.Template {
 {{ define "extraDefinitions" }}{{ end }}
 {{ define "postStructDefinitions" }}{{ end }}
 {{ template "header" . }}
+{{- define "customTypesImports" }}
+{{- range index . "CustomImports" }}
+import {{ printf "%q" . }}
+{{- end }}
+{{- end }}
+{{- define "enumStringerImports" }}
+{{- if and (index . "WithEnumTypes") (index . "WithEnumStringer") }}
+{{- $numeric := false }}
+{{- range index . "Enums" }}
+{{- if ne .Type "string" }}
+{{- $numeric = true }}
+{{- end }}
+{{- end }}
+{{- if $numeric }}
+import "strconv"
+{{- end }}
+{{- end }}
+{{- end }}
+{{- define "taggableMethods" }}
+{{- $tagged := eq (index . "Part") "tagged" }}
+{{- if eq $tagged (index . "TaggedTemplates" "builder") }}
+{{ template "builder" . }}
+{{- end }}
+{{- if eq $tagged (index . "TaggedTemplates" "stringer") }}
+{{ template "stringer" . }}
+{{- end }}
+{{- if eq $tagged (index . "TaggedTemplates" "textMarshaler") }}
+{{ template "textMarshaler" . }}
+{{- end }}
+{{- if eq $tagged (index . "TaggedTemplates" "insertRowMinimal") }}
+{{ template "insertRowMinimal" . }}
+{{- end }}
+{{- if eq $tagged (index . "TaggedTemplates" "enumPredicates") }}
+{{ template "enumPredicates" . }}
+{{- end }}
+{{- if eq $tagged (index . "TaggedTemplates" "modelMethods") }}
+{{ template "modelMethods" . }}
+{{- end }}
+{{- if eq $tagged (index . "TaggedTemplates" "mapMerge") }}
+{{ template "mapMerge" . }}
+{{- end }}
+{{- if eq $tagged (index . "TaggedTemplates" "optionalGetters") }}
+{{ template "optionalGetters" . }}
+{{- end }}
+{{- if eq $tagged (index . "TaggedTemplates" "cardinalityValidation") }}
+{{ template "cardinalityValidation" . }}
+{{- end }}
+{{- if eq $tagged (index . "TaggedTemplates" "fromMap") }}
+{{ template "fromMap" . }}
+{{- end }}
+{{- if eq $tagged (index . "TaggedTemplates" "columns") }}
+{{ template "columns" . }}
+{{- end }}
+{{- if eq $tagged (index . "TaggedTemplates" "referenceValidation") }}
+{{ template "referenceValidation" . }}
+{{- end }}
+{{- end }}
+{{- define "tableImports" }}
+{{ template "customTypesImports" . }}
+{{ template "enumStringerImports" . }}
+{{ template "extendedGenImports" . }}
+{{ template "columnSchemaImports" . }}
+{{ template "stringerImports" . }}
+{{ template "cardinalityValidationImports" . }}
+{{ template "fromMapImports" . }}
+{{ template "referenceValidationImports" . }}
+{{ template "copyCommonFieldsImports" . }}
+{{ template "insertRowMinimalImports" . }}
+{{ template "extraImports" . }}
+{{- end }}
+{{- define "tableCode" }}
+{{ template "types" . }}
+{{ template "extendedGen" . }}
+{{ template "columnSchema" . }}
+{{ template "columnTypes" . }}
+{{ template "enumColumns" . }}
+{{ template "taggableMethods" . }}
+{{- end }}
 {{ define "enums" }}
 {{ if index . "WithEnumTypes" }}
 {{ if index . "Enums" }}
 type (
 {{ range index . "Enums" }}
-{{ .Alias }} = {{ .Type }}
+{{ .Alias }} {{ if not (index $ "WithEnumStringer") }}= {{ end }}{{ .Type }}
 {{- end }}
 )
 
@@ -210,47 +1157,238 @@ var (
 {{ range  index . "Enums" }}
 {{- $e := . }}
 {{- range .Sets }}
-{{ $e.Alias }}{{ FieldName . }} {{ $e.Alias }} = {{ PrintVal . $e.Type }}
+{{ $e.Alias }}{{ $e.MemberName . }} {{ $e.Alias }} = {{ PrintVal . $e.Type }}
 {{- end }}
 {{- end }}
 )
+{{ range index . "Enums" }}
+{{- if .Docs }}
+{{- $e := . }}
+
+// {{ .Alias }}Descriptions documents the members of {{ .Alias }}
+var {{ .Alias }}Descriptions = map[{{ .Alias }}]string{
+{{- range $member := .Sets }}
+{{- with $e.Description $member }}
+{{ $e.Alias }}{{ $e.MemberName $member }}: {{ printf "%q" . }},
 {{- end }}
 {{- end }}
+}
 {{- end }}
-package {{ index . "PackageName" }}
-{{ template "extendedGenImports" . }}
-{{ template "extraImports" . }}
+{{- end }}
+{{- end }}
+{{- if index . "WithEnumStringer" }}
+{{- range index . "Enums" }}
+{{- $e := . }}
+{{- if eq .Type "string" }}
+
+// String returns the value of the {{ .Alias }}
+func (e {{ .Alias }}) String() string {
+	return string(e)
+}
+{{- else }}
+
+// String returns the name of the variable holding the {{ .Alias }}, or its
+// value if it is not a member of {{ .Alias }}
+func (e {{ .Alias }}) String() string {
+	switch e {
+{{- range .Sets }}
+	case {{ $e.Alias }}{{ $e.MemberName . }}:
+		return "{{ $e.Alias }}{{ $e.MemberName . }}"
+{{- end }}
+	}
+{{- if eq .Type "int64" }}
+	return strconv.FormatInt(int64(e), 10)
+{{- else if eq .Type "float64" }}
+	return strconv.FormatFloat(float64(e), 'g', -1, 64)
+{{- else }}
+	return strconv.FormatBool(bool(e))
+{{- end }}
+}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- if index . "WithEnumExhaustiveness" }}
+{{- range index . "Enums" }}
+{{- $e := . }}
+
+// every member of {{ .Alias }}, for linters checking exhaustiveness
+var _ = map[{{ .Alias }}]struct{}{
+{{- range .Sets }}
+{{ $e.Alias }}{{ $e.MemberName . }}: {},
+{{- end }}
+}
+{{- end }}
+{{- end }}
+{{- if index . "WithEnumValidation" }}
+{{- range index . "Enums" }}
+{{- $e := . }}
+
+// Valid{{ .Alias }} reports whether a value is a member of {{ .Alias }}
+func Valid{{ .Alias }}(value {{ .Alias }}) bool {
+	switch value {
+	case {{ range $i, $member := .Sets }}{{ if $i }}, {{ end }}{{ $e.Alias }}{{ $e.MemberName $member }}{{ end }}:
+		return true
+	}
+	return false
+}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{ define "constants" }}
+const (
+	// Table{{ index . "StructName" }} is the name of the {{ index . "TableName" }} table
+	Table{{ index . "StructName" }} = {{ printf "%q" (index . "TableName") }}
+)
+
+// names of the columns of the {{ index . "TableName" }} table
+const (
+{{- range index . "Columns" }}
+	{{ .Constant }} = {{ printf "%q" .Column }}
+{{- end }}
+)
+{{- end }}
+{{ define "types" }}
 {{ template "preStructDefinitions" . }}
+{{ template "constants" . }}
 {{ template "enums" . }}
 {{ template "structComment" . }}
 type {{ index . "StructName" }} struct {
 {{- $tableName := index . "TableName" }}
 {{ if index . "WithEnumTypes" }}
-{{ range $field := index . "Fields" }}	{{ FieldName $field.Column }}  {{ FieldTypeWithEnums $tableName $field.Column $field.Schema }} ` + "`" + `{{ OvsdbTag $field.Column }}{{ template "extraTags" . }}` + "`" + `
+{{ range $field := index . "Fields" }}{{ with $field.Comment }}{{ Comment . }}
+{{ end }}	{{ or $field.Name (FieldName $field.Column) }}  {{ or $field.Type (FieldTypeWithEnum $tableName $field.Column $field.Schema $field.EnumType) }} ` + "`" + `{{ OvsdbTag $field.Column }}{{ if index $ "WithJSONTags" }} {{ JSONTag $field.Column }}{{ end }}{{ template "extraTags" . }}` + "`" + `
 {{ end }}
 {{ else }}
-{{ range  $field := index . "Fields" }}	{{ FieldName $field.Column }}  {{ FieldType $tableName $field.Column $field.Schema }} ` + "`" + `{{ OvsdbTag $field.Column }}{{ template "extraTags" . }}` + "`" + `
+{{ range  $field := index . "Fields" }}{{ with $field.Comment }}{{ Comment . }}
+{{ end }}	{{ or $field.Name (FieldName $field.Column) }}  {{ or $field.Type (FieldType $tableName $field.Column $field.Schema) }} ` + "`" + `{{ OvsdbTag $field.Column }}{{ if index $ "WithJSONTags" }} {{ JSONTag $field.Column }}{{ end }}{{ template "extraTags" . }}` + "`" + `
 {{ end }}
 {{ end }}
 {{ template "extraFields" . }}
 }
+{{ template "fieldColumnMaps" . }}
 {{ template "postStructDefinitions" . }}
 {{ template "extraDefinitions" . }}
-{{ template "extendedGen" . }}
+{{ end }}
+{{- if eq (index . "Part") "tagged" }}
+//go:build {{ index . "BuildTag" }}
+{{ end }}
+package {{ index . "PackageName" }}
+{{ with index . "Part" }}
+{{- if eq . "types" }}
+{{ template "customTypesImports" $ }}
+{{ template "enumStringerImports" $ }}
+{{ template "extraImports" $ }}
+{{ template "types" $ }}
+{{- else if eq . "methods" }}
+{{ template "customTypesImports" $ }}
+{{ template "methodsImports" $ }}
+{{ template "extendedGenMethods" $ }}
+{{ template "columnSchemaMethods" $ }}
+{{ template "taggableMethods" $ }}
+{{- else if eq . "tagged" }}
+{{ template "customTypesImports" $ }}
+{{ template "methodsImports" $ }}
+{{ template "taggableMethods" $ }}
+{{- else if eq . "helpers" }}
+{{ template "customTypesImports" $ }}
+{{ template "helpersImports" $ }}
+{{ template "extendedGenHelpers" $ }}
+{{ template "columnSchemaHelpers" $ }}
+{{ template "columnTypes" $ }}
+{{ template "enumColumns" $ }}
+{{- end }}
+{{- else }}
+{{ template "tableImports" . }}
+{{ template "tableCode" . }}
+{{- end }}
 `))
 }
 
+// NewTablesTemplate returns a new template generating the code of several
+// tables in a single file, executed with the data returned by
+// GetTablesTemplateData. The code of each table is the one NewTableTemplate
+// generates in a single file, and the same templates can be overridden. The
+// package clause and the header are generated once, and the imports of the
+// tables are merged (see Generator.Format)
+func NewTablesTemplate() *template.Template {
+	return template.Must(NewTableTemplate().Parse(`
+{{- define "` + tablesTemplateName + `" }}
+{{ template "header" . }}
+
+package {{ index . "PackageName" }}
+{{ range index . "Tables" }}
+{{ template "tableImports" . }}
+{{- end }}
+{{ range index . "Tables" }}
+{{ template "tableCode" . }}
+{{- end }}
+{{- end }}
+`)).Lookup(tablesTemplateName)
+}
+
+// tablesTemplateName is the name of the template returned by
+// NewTablesTemplate
+const tablesTemplateName = "tables"
+
 // Enum represents the enum schema type
 type Enum struct {
 	Type  string
 	Alias string
 	Sets  []interface{}
+	// Column is the name of the column holding the enum
+	Column string
+	// Docs holds the documentation of the members, if the schema provides it
+	Docs map[string]string
+	// initialisms are the initialisms kept upper case in the names of the
+	// members
+	initialisms map[string]bool
+}
+
+// Description returns the documentation of an enum member, or an empty
+// string if it is not documented
+func (e Enum) Description(member interface{}) string {
+	return e.Docs[fmt.Sprintf("%v", member)]
+}
+
+// MemberName returns the name of an enum member, appended to the name of the
+// enum type, like the MemberName function but with the initialisms of the
+// options the enum was generated with (see WithInitialisms)
+func (e Enum) MemberName(member interface{}) string {
+	return memberName(fmt.Sprintf("%v", member), e.initialisms)
 }
 
 // Field represents the field information
 type Field struct {
 	Column string
 	Schema *ovsdb.ColumnSchema
+	// Type is the custom type of the field set with WithCustomTypeMapping,
+	// which the templates use instead of its FieldType
+	Type string
+	// Name is the name of the field, the one set with WithFieldNameOverrides
+	// or else its FieldName with the initialisms of the options, which the
+	// templates use instead of its FieldName
+	Name string
+	// EnumType is the name of the enum type of the field, if its column is
+	// an enum or a set of enum members
+	EnumType string
+	// Min and Max are the minimum and maximum number of elements of the
+	// column. Max is ovsdb.Unlimited when the column has no maximum
+	Min int
+	Max int
+	// Comment documents the field, out of the description of the column
+	Comment string
+	// KeyRefTable and ValueRefTable are the tables referenced by the UUIDs of
+	// the keys and of the values of the column, if any
+	KeyRefTable   string
+	ValueRefTable string
+}
+
+// ColumnConstant represents a column and the name of the constant holding
+// its name
+type ColumnConstant struct {
+	Column   string
+	Constant string
 }
 
 // TableTemplateData represents the data used by the Table Template
@@ -264,25 +1402,274 @@ func (t TableTemplateData) WithEnumTypes(val bool) {
 }
 
 // WithExtendedGen configures whether the Template should generate code to deep
-// copy models.
+// copy, compare and normalize models.
 func (t TableTemplateData) WithExtendedGen(val bool) {
 	t["WithExtendedGen"] = val
 }
 
+// WithDeepCopy configures whether the Template should generate the DeepCopy
+// and DeepCopyInto methods, which copy the pointers, slices and maps of the
+// models instead of sharing them. They are also generated with
+// WithExtendedGen.
+func (t TableTemplateData) WithDeepCopy(val bool) {
+	t["WithDeepCopy"] = val
+}
+
+// WithEquals configures whether the Template should generate the Equals
+// method, which compares the sets regardless of their order and an empty set or
+// map with a nil one, as OVSDB does. It is also generated with WithExtendedGen.
+func (t TableTemplateData) WithEquals(val bool) {
+	t["WithEquals"] = val
+}
+
+// WithColumnSchema configures whether the Template should generate a
+// ColumnSchema method that returns the schema of a column of the table.
+func (t TableTemplateData) WithColumnSchema(val bool) {
+	t["WithColumnSchema"] = val
+}
+
+// WithBuilder configures whether the Template should generate a builder to
+// construct models with method chaining, e.g.
+// NewLogicalSwitchBuilder().Name("ls").Build()
+func (t TableTemplateData) WithBuilder(val bool) {
+	t["WithBuilder"] = val
+}
+
+// WithFieldColumnMaps configures whether the Template should generate maps
+// from the struct field names to the column names of the table, and back.
+func (t TableTemplateData) WithFieldColumnMaps(val bool) {
+	t["WithFieldColumnMaps"] = val
+}
+
+// WithApplyPatch configures whether the Template should generate an ApplyPatch
+// method that copies the fields set in a patch model into a model. It requires
+// WithExtendedGen.
+func (t TableTemplateData) WithApplyPatch(val bool) {
+	t["WithApplyPatch"] = val
+}
+
+// WithCopyCommonFields configures whether the Template should generate a
+// CopyCommonFields method that copies the fields of a model into the fields of
+// a model of another table mapped to columns of the same name and type. It
+// requires WithExtendedGen.
+func (t TableTemplateData) WithCopyCommonFields(val bool) {
+	t["WithCopyCommonFields"] = val
+}
+
+// WithEnumExhaustiveness configures whether the Template should generate, for
+// each enum, a map literal holding all of its members, so that linters can
+// check that switches over the enum are exhaustive. It requires WithEnumTypes.
+func (t TableTemplateData) WithEnumExhaustiveness(val bool) {
+	t["WithEnumExhaustiveness"] = val
+}
+
+// WithEnumValidation configures whether the Template should generate, for
+// each enum, a function reporting whether a value is one of its members, e.g.
+// ValidBridgeFailMode, to check values before sending them to the server. It
+// requires WithEnumTypes.
+func (t TableTemplateData) WithEnumValidation(val bool) {
+	t["WithEnumValidation"] = val
+}
+
+// WithStringer configures whether the Template should generate a String method
+// that prints all the fields of a model in a stable, readable form.
+func (t TableTemplateData) WithStringer(val bool) {
+	t["WithStringer"] = val
+}
+
+// WithTextMarshaler configures whether the Template should generate
+// MarshalText and UnmarshalText methods that render and parse the UUID of a
+// model, so it can be used with text based encoders keyed by identity. Note
+// that encoding/json then encodes a model as its UUID rather than its row.
+func (t TableTemplateData) WithTextMarshaler(val bool) {
+	t["WithTextMarshaler"] = val
+}
+
+// WithInsertRowMinimal configures whether the Template should generate an
+// InsertRowMinimal method that returns the row inserting a model, without the
+// columns holding the default value of their type.
+func (t TableTemplateData) WithInsertRowMinimal(val bool) {
+	t["WithInsertRowMinimal"] = val
+}
+
+// WithEnumPredicates configures whether the Template should generate, for
+// each member of the single valued string enum columns, a predicate reporting
+// whether the field holds that member, e.g. IsFailModeSecure. Optional fields
+// that are not set hold none of the members.
+func (t TableTemplateData) WithEnumPredicates(val bool) {
+	t["WithEnumPredicates"] = val
+}
+
+// WithModelMethods configures whether the Template should generate a GetUUID
+// method returning the UUID of a model and a Table method returning the name
+// of its table, so that every model satisfies the same small interface.
+func (t TableTemplateData) WithModelMethods(val bool) {
+	t["WithModelMethods"] = val
+}
+
+// WithMapMerge configures whether the Template should generate, for each map
+// column, a method setting the given keys in the map of a model while keeping
+// its other keys, e.g. MergeExternalIDs.
+func (t TableTemplateData) WithMapMerge(val bool) {
+	t["WithMapMerge"] = val
+}
+
+// WithOptionalGetters configures whether the Template should generate, for
+// each optional column, a getter returning its value and whether it is set,
+// e.g. GetDatapathID.
+func (t TableTemplateData) WithOptionalGetters(val bool) {
+	t["WithOptionalGetters"] = val
+}
+
+// WithCardinalityValidation configures whether the Template should generate a
+// Validate method checking that the number of elements of each set and map
+// column is within the min and max bounds of the schema.
+func (t TableTemplateData) WithCardinalityValidation(val bool) {
+	t["WithCardinalityValidation"] = val
+}
+
+// WithFromMap configures whether the Template should generate a function
+// building a model from the values of its columns, by column name, e.g.
+// NewLogicalSwitchFromMap.
+func (t TableTemplateData) WithFromMap(val bool) {
+	t["WithFromMap"] = val
+}
+
+// WithColumns configures whether the Template should generate a Columns method
+// returning the names of all the columns of the table, including _uuid.
+func (t TableTemplateData) WithColumns(val bool) {
+	t["WithColumns"] = val
+}
+
+// WithReferenceValidation configures whether the Template should generate a
+// ValidateReferences method checking that the UUIDs held by the reference
+// columns are the ones of rows of the tables they reference in a cache.
+func (t TableTemplateData) WithReferenceValidation(val bool) {
+	t["WithReferenceValidation"] = val
+}
+
+// WithEnumStringer configures whether the Template should generate the enums
+// as types defined over their base type rather than aliases of it, with a
+// String method. The String method of a string enum returns its value, the one
+// of the other enums the name of the variable holding it.
+func (t TableTemplateData) WithEnumStringer(val bool) {
+	t["WithEnumStringer"] = val
+}
+
+// WithJSONTags configures whether the Template should add a json tag named
+// after the column to the struct fields, next to their ovsdb tag (see JSONTag).
+func (t TableTemplateData) WithJSONTags(val bool) {
+	t["WithJSONTags"] = val
+}
+
+// WithColumnTypes configures whether the Template should generate a map
+// holding the type of each column of the table, e.g. LogicalSwitchColumnTypes,
+// so that data can be validated against the table without its schema.
+func (t TableTemplateData) WithColumnTypes(val bool) {
+	t["WithColumnTypes"] = val
+}
+
+// WithEnumColumns configures whether the Template should generate a map
+// holding the members of each enum column of the table, e.g.
+// LogicalSwitchEnumColumns.
+func (t TableTemplateData) WithEnumColumns(val bool) {
+	t["WithEnumColumns"] = val
+}
+
+// Parts of the code generated for a table, used to split it into several files
+const (
+	// TableTypesPart holds the enums and the struct of the table
+	TableTypesPart = "types"
+	// TableMethodsPart holds the methods of the struct
+	TableMethodsPart = "methods"
+	// TableHelpersPart holds the unexported helpers used by the methods
+	TableHelpersPart = "helpers"
+	// TableTaggedPart holds the methods generated behind the build tag set
+	// with WithBuildTag
+	TableTaggedPart = "tagged"
+)
+
+// taggableTemplates are the templates generating methods that can be moved to
+// the tagged part, in the order they are generated
+var taggableTemplates = []string{
+	"builder",
+	"stringer",
+	"textMarshaler",
+	"insertRowMinimal",
+	"enumPredicates",
+	"modelMethods",
+	"mapMerge",
+	"optionalGetters",
+	"cardinalityValidation",
+	"fromMap",
+	"columns",
+	"referenceValidation",
+}
+
+// TaggableTemplates returns the names of the templates whose methods can be
+// generated behind a build tag with WithBuildTag
+func TaggableTemplates() []string {
+	return append([]string{}, taggableTemplates...)
+}
+
+// WithPart configures the Template to only generate one part of the code of
+// the table, so that it can be split into several files (see Parts and
+// PartFileName). An empty part generates the whole code in a single file.
+func (t TableTemplateData) WithPart(part string) {
+	t["Part"] = part
+}
+
+// Parts returns the parts the code of the table is split into with its
+// current configuration
+func (t TableTemplateData) Parts() []string {
+	parts := []string{TableTypesPart}
+	if t["WithExtendedGen"] == true || t["WithDeepCopy"] == true || t["WithEquals"] == true || t["WithColumnSchema"] == true || t["WithBuilder"] == true || t["WithStringer"] == true || t["WithTextMarshaler"] == true || t["WithInsertRowMinimal"] == true || t["WithColumnTypes"] == true || t["WithEnumColumns"] == true || t["WithEnumPredicates"] == true || t["WithModelMethods"] == true || t["WithMapMerge"] == true || t["WithOptionalGetters"] == true || t["WithCardinalityValidation"] == true || t["WithFromMap"] == true || t["WithColumns"] == true || t["WithReferenceValidation"] == true {
+		parts = append(parts, TableMethodsPart, TableHelpersPart)
+		if tagged, _ := t["TaggedTemplates"].(map[string]bool); len(tagged) > 0 {
+			parts = append(parts, TableTaggedPart)
+		}
+	}
+	return parts
+}
+
 // GetTableTemplateData returns the TableTemplateData map. It has the following
 // keys:
 //
 //   - `TableName`: (string) the table name
 //   - `TPackageName`: (string) the package name
 //   - `TStructName`: (string) the struct name
+//   - `StructNamePrefix`: (string) the prefix of the struct name and of the
+//     names derived from it (see WithStructNamePrefix)
 //   - `TFields`: []Field a list of Fields that the struct has
-func GetTableTemplateData(pkg, name string, table *ovsdb.TableSchema) TableTemplateData {
+//   - `CustomImports`: []string the import paths of the custom types of the
+//     fields (see WithCustomTypeMapping)
+//   - `Columns`: []ColumnConstant the columns and the names of the constants
+//     holding them
+//   - `TableSchema`: (*ovsdb.TableSchema) the table schema
+//   - `ColumnTypes`: (map[string]*ovsdb.ColumnType) the type of each column
+//   - `Part`: (string) the part of the code to generate, empty for all of it
+//   - `BuildTag`: (string) the build tag of the tagged part (see WithBuildTag)
+//   - `TaggedTemplates`: (map[string]bool) the templates whose methods are
+//     generated in the tagged part only
+//
+// The options configure the code generated for the table, like the With
+// methods of the TableTemplateData do. It fails if an option is invalid, like
+// a field name override (see WithFieldNameOverrides).
+func GetTableTemplateData(pkg, name string, table *ovsdb.TableSchema, opts ...Option) (TableTemplateData, error) {
+	o, err := newOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
 	data := map[string]interface{}{}
 	data["TableName"] = name
 	data["PackageName"] = pkg
-	data["StructName"] = StructName(name)
+	data["StructName"] = o.structNamePrefix + structName(name, o.nameInitialisms())
+	data["StructNamePrefix"] = o.structNamePrefix
 	Fields := []Field{}
+	Columns := []ColumnConstant{}
 	Enums := []Enum{}
+	columnTypes := map[string]*ovsdb.ColumnType{}
+	customImports := []string{}
 
 	// Map iteration order is random, so for predictable generation
 	// lets sort fields by name
@@ -294,99 +1681,360 @@ func GetTableTemplateData(pkg, name string, table *ovsdb.TableSchema) TableTempl
 
 	for _, columnName := range append([]string{"_uuid"}, order...) {
 		columnSchema := table.Column(columnName)
-		Fields = append(Fields, Field{
-			Column: columnName,
-			Schema: columnSchema,
+		field := Field{
+			Column:  columnName,
+			Schema:  columnSchema,
+			Min:     1,
+			Max:     1,
+			Comment: columnSchema.Description,
+		}
+		if columnSchema.TypeObj != nil {
+			field.Min = columnSchema.TypeObj.Min()
+			field.Max = columnSchema.TypeObj.Max()
+			field.KeyRefTable = refTable(columnSchema.TypeObj.Key)
+			field.ValueRefTable = refTable(columnSchema.TypeObj.Value)
+		}
+		field.Name = o.fieldNames[name][columnName]
+		if field.Name == "" {
+			field.Name = fieldName(columnName, o.nameInitialisms())
+		}
+		if spec, ok := o.customTypes[name][columnName]; ok {
+			var importPath string
+			field.Type, importPath = customType(spec)
+			if importPath != "" {
+				customImports = append(customImports, importPath)
+			}
+		}
+		Columns = append(Columns, ColumnConstant{
+			Column:   columnName,
+			Constant: o.structNamePrefix + structName(name, o.nameInitialisms()) + "Column" + fieldName(columnName, o.nameInitialisms()),
 		})
-		if enum := FieldEnum(name, columnName, columnSchema); enum != nil {
+		if enum := fieldEnum(name, columnName, columnSchema, o.nameInitialisms()); enum != nil {
+			enum.Alias = o.structNamePrefix + enum.Alias
+			field.EnumType = enum.Alias
 			Enums = append(Enums, *enum)
 		}
+		Fields = append(Fields, field)
+		columnTypes[columnName] = columnSchema.TypeObj
+		if columnSchema.TypeObj == nil {
+			// only the schema of the _uuid column has no type object
+			columnTypes[columnName] = &ovsdb.ColumnType{Key: &ovsdb.BaseType{Type: columnSchema.Type}}
+		}
 	}
 	data["Fields"] = Fields
+	data["CustomImports"] = sortedUnique(customImports)
+	data["Columns"] = Columns
 	data["Enums"] = Enums
+	data["TableSchema"] = table
+	data["ColumnTypes"] = columnTypes
 	data["WithEnumTypes"] = true
 	data["WithExtendedGen"] = false
-	return data
+	data["WithDeepCopy"] = false
+	data["WithEquals"] = false
+	data["WithColumnSchema"] = false
+	data["WithBuilder"] = false
+	data["WithFieldColumnMaps"] = false
+	data["WithApplyPatch"] = false
+	data["WithStringer"] = false
+	data["WithTextMarshaler"] = false
+	data["WithInsertRowMinimal"] = false
+	data["WithEnumExhaustiveness"] = false
+	data["WithEnumValidation"] = false
+	data["WithEnumStringer"] = false
+	data["WithColumnTypes"] = false
+	data["WithEnumColumns"] = false
+	data["WithCopyCommonFields"] = false
+	data["WithJSONTags"] = false
+	data["WithEnumPredicates"] = false
+	data["WithModelMethods"] = false
+	data["WithMapMerge"] = false
+	data["WithOptionalGetters"] = false
+	data["WithCardinalityValidation"] = false
+	data["WithFromMap"] = false
+	data["WithColumns"] = false
+	data["WithReferenceValidation"] = false
+	data["Part"] = ""
+	data["BuildTag"] = o.buildTag
+	tagged := map[string]bool{}
+	for _, name := range o.taggedTemplates {
+		tagged[name] = true
+	}
+	data["TaggedTemplates"] = tagged
+	for flag, val := range o.tableFlags {
+		data[flag] = val
+	}
+	return data, nil
+}
+
+// GetTablesTemplateData returns the data needed to execute the template
+// returned by NewTablesTemplate, generating the code of all the tables of a
+// schema in a single file. It has the following keys:
+//
+//   - `DatabaseName`: (string) the database name
+//   - `PackageName`: (string) the package name
+//   - `Tables`: []TableTemplateData the data of each table, sorted by name
+//
+// The options configure the code generated for every table. An enum is only
+// declared by the first table holding it, so that its type and members are
+// not declared twice in the file
+func GetTablesTemplateData(pkg string, schema ovsdb.DatabaseSchema, opts ...Option) (map[string]interface{}, error) {
+	data := map[string]interface{}{}
+	data["DatabaseName"] = schema.Name
+	data["PackageName"] = pkg
+	tables := []TableTemplateData{}
+	declared := map[string]bool{}
+	for _, tableName := range sortedTables(schema) {
+		table := schema.Tables[tableName]
+		tableData, err := GetTableTemplateData(pkg, tableName, &table, opts...)
+		if err != nil {
+			return nil, err
+		}
+		enums := []Enum{}
+		for _, enum := range tableData["Enums"].([]Enum) {
+			if !declared[enum.Alias] {
+				declared[enum.Alias] = true
+				enums = append(enums, enum)
+			}
+		}
+		tableData["Enums"] = enums
+		tables = append(tables, tableData)
+	}
+	data["Tables"] = tables
+	return data, nil
+}
+
+// customType returns the type of a field, and the path of the package to
+// import for it, out of a type given to WithCustomTypeMapping, e.g. time.Time
+// from time.Time, or json.RawMessage from encoding/json.RawMessage
+func customType(spec string) (string, string) {
+	typ := strings.TrimLeft(spec, "*[]")
+	prefix := spec[:len(spec)-len(typ)]
+	dot := strings.LastIndex(typ, ".")
+	if dot < 0 || dot < strings.LastIndex(typ, "/") {
+		// a predeclared type, or one of the generated package
+		return spec, ""
+	}
+	importPath := typ[:dot]
+	pkg := importPath[strings.LastIndex(importPath, "/")+1:]
+	return prefix + pkg + typ[dot:], importPath
+}
+
+// sortedUnique returns the sorted strings without duplicates
+func sortedUnique(values []string) []string {
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	sort.Strings(unique)
+	return unique
 }
 
-// FieldName returns the name of a column field
+// FieldName returns the name of a column field. It is an exported identifier
+// even for the names that camelCase alone does not turn into one: a name
+// starting with a digit is prefixed with an X, e.g. X2ndPort, and a name made
+// of separators only is spelled out, e.g. Underscore
 func FieldName(column string) string {
-	return camelCase(strings.Trim(column, "_"))
+	return fieldName(column, defaultInitialisms)
+}
+
+// fieldName is FieldName with the given initialisms
+func fieldName(column string, initialisms map[string]bool) string {
+	name := memberName(column, initialisms)
+	switch {
+	case name == "":
+		return separatorsName(column)
+	case name[0] >= '0' && name[0] <= '9':
+		return "X" + name
+	}
+	return name
+}
+
+// MemberName returns the name of an enum member, appended to the name of its
+// enum type. The characters not allowed in identifiers, like quotes, are
+// dropped, and the sign of a negative number is spelled out, e.g. Minus1
+func MemberName(member string) string {
+	return memberName(member, defaultInitialisms)
+}
+
+// memberName is MemberName with the given initialisms
+func memberName(member string, initialisms map[string]bool) string {
+	if len(member) > 1 && member[0] == '-' && member[1] >= '0' && member[1] <= '9' {
+		return "Minus" + memberName(member[1:], initialisms)
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, camelCase(strings.Trim(member, "_"), initialisms))
+}
+
+// separatorsName spells out the separators a column name is made of
+func separatorsName(column string) string {
+	if column == "" {
+		return "Column"
+	}
+	name := ""
+	for _, r := range column {
+		switch r {
+		case '_':
+			name += "Underscore"
+		case '-':
+			name += "Hyphen"
+		}
+	}
+	if name == "" {
+		return "Column"
+	}
+	return name
 }
 
-// StructName returns the name of the table struct
+// StructName returns the name of the table struct. The segments of the table
+// name, separated by underscores or hyphens, are joined: the lower case ones
+// are title-cased, or upper-cased if they are initialisms, e.g. ipfix-config
+// becomes IPFIXConfig, while the ones already holding upper case letters are
+// kept as is, but for the first letter of the name, e.g. Open_vSwitch becomes
+// OpenvSwitch
 func StructName(tableName string) string {
-	return strings.Title(strings.ReplaceAll(tableName, "_", ""))
+	return structName(tableName, defaultInitialisms)
+}
+
+// structName is StructName with the given initialisms
+func structName(tableName string, initialisms map[string]bool) string {
+	var name strings.Builder
+	for i, segment := range strings.FieldsFunc(tableName, func(r rune) bool {
+		return r == '_' || r == '-'
+	}) {
+		switch {
+		case segment == strings.ToLower(segment):
+			name.WriteString(title(expandInitilaisms(segment, initialisms)))
+		case i == 0:
+			name.WriteString(title(segment))
+		default:
+			name.WriteString(segment)
+		}
+	}
+	return name.String()
 }
 
-func fieldType(tableName, columnName string, column *ovsdb.ColumnSchema, enumTypes bool) string {
+func fieldType(tableName, columnName string, column *ovsdb.ColumnSchema, enumType string) string {
 	switch column.Type {
 	case ovsdb.TypeEnum:
-		if enumTypes {
-			return enumName(tableName, columnName)
+		if enumType != "" {
+			return enumType
 		}
-		return AtomicType(column.TypeObj.Key.Type)
+		return BaseType(column.TypeObj.Key)
 	case ovsdb.TypeMap:
-		return fmt.Sprintf("map[%s]%s", AtomicType(column.TypeObj.Key.Type),
-			AtomicType(column.TypeObj.Value.Type))
+		return fmt.Sprintf("map[%s]%s", BaseType(column.TypeObj.Key),
+			BaseType(column.TypeObj.Value))
 	case ovsdb.TypeSet:
-		// optional with max 1 element
-		if column.TypeObj.Min() == 0 && column.TypeObj.Max() == 1 {
-			if enumTypes && FieldEnum(tableName, columnName, column) != nil {
-				return fmt.Sprintf("*%s", enumName(tableName, columnName))
-			}
-			return fmt.Sprintf("*%s", AtomicType(column.TypeObj.Key.Type))
+		// the elements of the enum sets, whatever their size, are of the
+		// enum type
+		elemType := BaseType(column.TypeObj.Key)
+		if enumType != "" && FieldEnum(tableName, columnName, column) != nil {
+			elemType = enumType
 		}
+		switch {
+		// optional with max 1 element
+		case column.TypeObj.Min() == 0 && column.TypeObj.Max() == 1:
+			return fmt.Sprintf("*%s", elemType)
 		// required, max 1 element
-		if column.TypeObj.Min() == 1 && column.TypeObj.Max() == 1 {
-			if enumTypes && FieldEnum(tableName, columnName, column) != nil {
-				return enumName(tableName, columnName)
-			}
-			return AtomicType(column.TypeObj.Key.Type)
-		}
+		case column.TypeObj.Min() == 1 && column.TypeObj.Max() == 1:
+			return elemType
 		// use array for columns with max > 1
-		if column.TypeObj.Max() > 1 {
-			if enumTypes && FieldEnum(tableName, columnName, column) != nil {
-				return fmt.Sprintf("[%d]%s", column.TypeObj.Max(), enumName(tableName, columnName))
-			}
-			return fmt.Sprintf("[%d]%s", column.TypeObj.Max(), AtomicType(column.TypeObj.Key.Type))
+		case column.TypeObj.Max() > 1:
+			return fmt.Sprintf("[%d]%s", column.TypeObj.Max(), elemType)
 		}
 		// use a slice
-		if enumTypes && FieldEnum(tableName, columnName, column) != nil {
-			return fmt.Sprintf("[]%s", enumName(tableName, columnName))
-		}
-		return fmt.Sprintf("[]%s", AtomicType(column.TypeObj.Key.Type))
+		return fmt.Sprintf("[]%s", elemType)
 	default:
+		if column.TypeObj != nil && column.TypeObj.Key != nil {
+			return BaseType(column.TypeObj.Key)
+		}
 		return AtomicType(column.Type)
 	}
 }
 
 // EnumName returns the name of the enum field
-func enumName(tableName, columnName string) string {
-	return strings.Title(StructName(tableName)) + camelCase(columnName)
+func enumName(tableName, columnName string, initialisms map[string]bool) string {
+	return structName(tableName, initialisms) + camelCase(columnName, initialisms)
 }
 
 // FieldType returns the string representation of a column type without enum types expansion
 func FieldType(tableName, columnName string, column *ovsdb.ColumnSchema) string {
-	return fieldType(tableName, columnName, column, false)
+	return fieldType(tableName, columnName, column, "")
 }
 
 // FieldTypeWithEnums returns the string representation of a column type where Enums
 // are expanded into their own types
 func FieldTypeWithEnums(tableName, columnName string, column *ovsdb.ColumnSchema) string {
-	return fieldType(tableName, columnName, column, true)
+	return fieldType(tableName, columnName, column, enumName(tableName, columnName, defaultInitialisms))
+}
+
+// prefixedFieldTypeWithEnums is like FieldTypeWithEnums, with the names of the
+// enum types prefixed like the table structs (see WithStructNamePrefix)
+func prefixedFieldTypeWithEnums(prefix, tableName, columnName string, column *ovsdb.ColumnSchema) string {
+	return fieldType(tableName, columnName, column, prefix+enumName(tableName, columnName, defaultInitialisms))
 }
 
-// FieldEnum returns the Enum if the column is an enum type
+// FieldEnum returns the Enum if the column is an enum type, or a set of the
+// members of an enum
 func FieldEnum(tableName, columnName string, column *ovsdb.ColumnSchema) *Enum {
-	if column.TypeObj == nil || column.TypeObj.Key.Enum == nil {
+	return fieldEnum(tableName, columnName, column, defaultInitialisms)
+}
+
+// fieldEnum is FieldEnum with the given initialisms
+func fieldEnum(tableName, columnName string, column *ovsdb.ColumnSchema, initialisms map[string]bool) *Enum {
+	if column.TypeObj == nil || column.TypeObj.Key == nil || column.TypeObj.Key.Enum == nil {
 		return nil
 	}
 	return &Enum{
-		Type:  column.TypeObj.Key.Type,
-		Alias: enumName(tableName, columnName),
-		Sets:  column.TypeObj.Key.Enum,
+		Type:        BaseType(column.TypeObj.Key),
+		Alias:       enumName(tableName, columnName, initialisms),
+		Sets:        enumMembers(column.TypeObj.Key),
+		Column:      columnName,
+		Docs:        column.TypeObj.Key.EnumDoc,
+		initialisms: initialisms,
+	}
+}
+
+// enumMembers returns the members of an enum. The integers, decoded from JSON
+// as float64, are converted back to int64, so that they are printed as such
+func enumMembers(base *ovsdb.BaseType) []interface{} {
+	if base.Type != ovsdb.TypeInteger {
+		return base.Enum
+	}
+	members := make([]interface{}, 0, len(base.Enum))
+	for _, member := range base.Enum {
+		if f, ok := member.(float64); ok {
+			member = int64(f)
+		}
+		members = append(members, member)
 	}
+	return members
+}
+
+// BaseType returns the string type of the keys or values of a column. Unlike
+// AtomicType, integers are int64, the size of the OVSDB integers, so that they
+// are not truncated on 32-bit platforms
+func BaseType(base *ovsdb.BaseType) string {
+	if base.Type == ovsdb.TypeInteger {
+		return "int64"
+	}
+	return AtomicType(base.Type)
+}
+
+// refTable returns the table referenced by the UUIDs of a base type, if any
+func refTable(base *ovsdb.BaseType) string {
+	if base == nil || base.Type != ovsdb.TypeUUID {
+		return ""
+	}
+	table, _ := base.RefTable()
+	return table
 }
 
 // AtomicType returns the string type of an AtomicType
@@ -406,18 +2054,74 @@ func AtomicType(atype string) string {
 	return ""
 }
 
+// formatVerb returns the fmt verb that prints the values of a column: strings
+// and UUIDs, and the sets and maps holding only them, are quoted
+func formatVerb(column *ovsdb.ColumnSchema) string {
+	quoted := func(atype string) bool {
+		return atype == ovsdb.TypeString || atype == ovsdb.TypeUUID
+	}
+	if column.TypeObj == nil {
+		if quoted(column.Type) {
+			return "%q"
+		}
+		return "%v"
+	}
+	if quoted(column.TypeObj.Key.Type) && (column.TypeObj.Value == nil || quoted(column.TypeObj.Value.Type)) {
+		return "%q"
+	}
+	return "%v"
+}
+
+// comment returns the line comments holding a text, one per line of the text
+func comment(text string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		lines = append(lines, strings.TrimSpace("// "+strings.TrimSpace(line)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// elemType returns the type of the elements of a slice or array type
+func elemType(t string) string {
+	return t[strings.Index(t, "]")+1:]
+}
+
+// jsonLiteral returns a Go string literal holding the JSON representation of a
+// value, to embed it in the generated code
+func jsonLiteral(v interface{}) (string, error) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return escape(string(b)), nil
+}
+
 // Tag returns the Tag string of a column
 func Tag(column string) string {
 	return fmt.Sprintf("ovsdb:\"%s\"", column)
 }
 
+// JSONTag returns the json Tag string of a column, omitting empty values. The
+// _uuid column is named uuid
+func JSONTag(column string) string {
+	if column == "_uuid" {
+		column = "uuid"
+	}
+	return fmt.Sprintf("json:\"%s,omitempty\"", column)
+}
+
 // FileName returns the filename of a table
 func FileName(table string) string {
 	return fmt.Sprintf("%s.go", strings.ToLower(table))
 }
 
+// PartFileName returns the filename of a part of the code of a table
+func PartFileName(table, part string) string {
+	return fmt.Sprintf("%s_%s.go", strings.ToLower(table), part)
+}
+
 // common initialisms used in ovsdb schemas
-var initialisms = map[string]bool{
+var defaultInitialisms = map[string]bool{
 	"ACL":   true,
 	"BFD":   true,
 	"CFM":   true,
@@ -449,32 +2153,58 @@ var initialisms = map[string]bool{
 	"SLB":   true,
 }
 
-func camelCase(field string) string {
+func camelCase(field string, initialisms map[string]bool) string {
 	s := strings.ToLower(field)
 	parts := strings.FieldsFunc(s, func(r rune) bool {
 		return r == '_' || r == '-'
 	})
-	if len(parts) > 1 {
-		s = ""
-		for _, p := range parts {
-			s += strings.Title(expandInitilaisms(p))
-		}
-	} else {
-		s = strings.Title(expandInitilaisms(s))
+	s = ""
+	for _, p := range parts {
+		s += title(expandInitilaisms(p, initialisms))
 	}
 	return s
 }
 
-func expandInitilaisms(s string) string {
+// title returns a string with the letters beginning its words upper-cased,
+// the words being separated by the characters other than letters, digits and
+// underscores, like the deprecated strings.Title
+func title(s string) string {
+	prev := ' '
+	return strings.Map(func(r rune) rune {
+		separated := isWordSeparator(prev)
+		prev = r
+		if separated {
+			return unicode.ToTitle(r)
+		}
+		return r
+	}, s)
+}
+
+// isWordSeparator tells whether a rune separates the words for title
+func isWordSeparator(r rune) bool {
+	if r <= 0x7F {
+		switch {
+		case '0' <= r && r <= '9', 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', r == '_':
+			return false
+		}
+		return true
+	}
+	if unicode.IsLetter(r) || unicode.IsDigit(r) {
+		return false
+	}
+	return unicode.IsSpace(r)
+}
+
+func expandInitilaisms(s string, initialisms map[string]bool) string {
 	// check initialisms
 	if u := strings.ToUpper(s); initialisms[u] {
-		return strings.ToUpper(s)
+		return u
 	}
 	// check for plurals too
 	if strings.HasSuffix(s, "s") {
 		sub := s[:len(s)-1]
 		if u := strings.ToUpper(sub); initialisms[u] {
-			return strings.ToUpper(sub) + "s"
+			return u + "s"
 		}
 	}
 	return s
@@ -482,14 +2212,22 @@ func expandInitilaisms(s string) string {
 
 func printVal(v interface{}, t string) string {
 	switch t {
-	case "int":
+	case "int", "int64":
+		// JSON numbers are decoded as float64
+		if f, ok := v.(float64); ok {
+			v = int64(f)
+		}
 		return fmt.Sprintf(`%d`, v)
 	case "float64":
-		return fmt.Sprintf(`%f`, v)
+		// the shortest representation reading back as the same value
+		if f, ok := v.(float64); ok {
+			return strconv.FormatFloat(f, 'g', -1, 64)
+		}
+		return fmt.Sprintf(`%v`, v)
 	case "bool":
 		return fmt.Sprintf(`%t`, v)
 	case "string":
-		return fmt.Sprintf(`"%s"`, v)
+		return strconv.Quote(fmt.Sprintf(`%v`, v))
 	}
 	return ""
 }
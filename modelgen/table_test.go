@@ -3,11 +3,21 @@ package modelgen
 import (
 	"encoding/json"
 	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"text/template"
 
 	"github.com/google/uuid"
+	"github.com/ovn-org/libovsdb/cache"
 	"github.com/ovn-org/libovsdb/example/vswitchd"
 	"github.com/ovn-org/libovsdb/model"
 	"github.com/ovn-org/libovsdb/ovsdb"
@@ -15,6 +25,72 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// tableSchema returns the schema of a table holding the given columns
+func tableSchema(t *testing.T, columns string) *ovsdb.TableSchema {
+	t.Helper()
+	var table ovsdb.TableSchema
+	err := json.Unmarshal([]byte(`{"columns": `+columns+`}`), &table)
+	require.NoError(t, err)
+	return &table
+}
+
+// tableTemplateData returns the template data of a table with the given
+// options
+func tableTemplateData(t *testing.T, name string, table *ovsdb.TableSchema, opts ...Option) TableTemplateData {
+	t.Helper()
+	data, err := GetTableTemplateData("test", name, table, opts...)
+	require.NoError(t, err)
+	return data
+}
+
+// formatTable returns the code generated out of the template data of a table
+func formatTable(t *testing.T, data TableTemplateData) string {
+	t.Helper()
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	b, err := g.Format(NewTableTemplate(), data)
+	require.NoError(t, err)
+	return string(b)
+}
+
+// generateTable returns the code generated for a table with the given options
+func generateTable(t *testing.T, name string, table *ovsdb.TableSchema, opts ...Option) string {
+	t.Helper()
+	return formatTable(t, tableTemplateData(t, name, table, opts...))
+}
+
+// generateParts returns the code generated for each part of a table, checking
+// that the parts compile together
+func generateParts(t *testing.T, data TableTemplateData) map[string]string {
+	t.Helper()
+	code := map[string]string{}
+	files := []string{}
+	for _, part := range data.Parts() {
+		data.WithPart(part)
+		code[part] = formatTable(t, data)
+		files = append(files, code[part])
+	}
+	checkCode(t, files...)
+	return code
+}
+
+// checkCode checks that the given files compile as a single package, and
+// returns it
+func checkCode(t *testing.T, files ...string) *types.Package {
+	t.Helper()
+	fset := token.NewFileSet()
+	parsed := []*ast.File{}
+	for i, src := range files {
+		file, err := parser.ParseFile(fset, fmt.Sprintf("%d.go", i), src, 0)
+		require.NoError(t, err, src)
+		parsed = append(parsed, file)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("test", fset, parsed, nil)
+	require.NoError(t, err, strings.Join(files, "\n"))
+	return pkg
+}
+
 func TestNewTableTemplate(t *testing.T) {
 	rawSchema := []byte(`
 	{
@@ -57,6 +133,21 @@ func TestNewTableTemplate(t *testing.T) {
 
 package test
 
+const (
+	// TableAtomicTable is the name of the atomicTable table
+	TableAtomicTable = "atomicTable"
+)
+
+// names of the columns of the atomicTable table
+const (
+	AtomicTableColumnUUID      = "_uuid"
+	AtomicTableColumnEventType = "event_type"
+	AtomicTableColumnFloat     = "float"
+	AtomicTableColumnInt       = "int"
+	AtomicTableColumnProtocol  = "protocol"
+	AtomicTableColumnStr       = "str"
+)
+
 type (
 	AtomicTableEventType = string
 	AtomicTableProtocol  = string
@@ -74,7 +165,7 @@ type AtomicTable struct {
 	UUID      string               ` + "`" + `ovsdb:"_uuid"` + "`" + `
 	EventType AtomicTableEventType ` + "`" + `ovsdb:"event_type"` + "`" + `
 	Float     float64              ` + "`" + `ovsdb:"float"` + "`" + `
-	Int       int                  ` + "`" + `ovsdb:"int"` + "`" + `
+	Int       int64                ` + "`" + `ovsdb:"int"` + "`" + `
 	Protocol  *AtomicTableProtocol ` + "`" + `ovsdb:"protocol"` + "`" + `
 	Str       string               ` + "`" + `ovsdb:"str"` + "`" + `
 }
@@ -90,12 +181,27 @@ type AtomicTable struct {
 
 package test
 
+const (
+	// TableAtomicTable is the name of the atomicTable table
+	TableAtomicTable = "atomicTable"
+)
+
+// names of the columns of the atomicTable table
+const (
+	AtomicTableColumnUUID      = "_uuid"
+	AtomicTableColumnEventType = "event_type"
+	AtomicTableColumnFloat     = "float"
+	AtomicTableColumnInt       = "int"
+	AtomicTableColumnProtocol  = "protocol"
+	AtomicTableColumnStr       = "str"
+)
+
 // AtomicTable defines an object in atomicTable table
 type AtomicTable struct {
 	UUID      string  ` + "`" + `ovsdb:"_uuid"` + "`" + `
 	EventType string  ` + "`" + `ovsdb:"event_type"` + "`" + `
 	Float     float64 ` + "`" + `ovsdb:"float"` + "`" + `
-	Int       int     ` + "`" + `ovsdb:"int"` + "`" + `
+	Int       int64   ` + "`" + `ovsdb:"int"` + "`" + `
 	Protocol  *string ` + "`" + `ovsdb:"protocol"` + "`" + `
 	Str       string  ` + "`" + `ovsdb:"str"` + "`" + `
 }
@@ -117,6 +223,21 @@ type AtomicTable struct {
 
 package test
 
+const (
+	// TableAtomicTable is the name of the atomicTable table
+	TableAtomicTable = "atomicTable"
+)
+
+// names of the columns of the atomicTable table
+const (
+	AtomicTableColumnUUID      = "_uuid"
+	AtomicTableColumnEventType = "event_type"
+	AtomicTableColumnFloat     = "float"
+	AtomicTableColumnInt       = "int"
+	AtomicTableColumnProtocol  = "protocol"
+	AtomicTableColumnStr       = "str"
+)
+
 type (
 	AtomicTableEventType = string
 	AtomicTableProtocol  = string
@@ -134,14 +255,14 @@ type AtomicTable struct {
 	UUID      string               ` + "`" + `ovsdb:"_uuid"` + "`" + `
 	EventType AtomicTableEventType ` + "`" + `ovsdb:"event_type"` + "`" + `
 	Float     float64              ` + "`" + `ovsdb:"float"` + "`" + `
-	Int       int                  ` + "`" + `ovsdb:"int"` + "`" + `
+	Int       int64                ` + "`" + `ovsdb:"int"` + "`" + `
 	Protocol  *AtomicTableProtocol ` + "`" + `ovsdb:"protocol"` + "`" + `
 	Str       string               ` + "`" + `ovsdb:"str"` + "`" + `
 
 	OtherUUID      string
 	OtherEventType string
 	OtherFloat     float64
-	OtherInt       int
+	OtherInt       int64
 	OtherProtocol  *string
 	OtherStr       string
 }
@@ -159,6 +280,21 @@ package test
 
 import "github.com/ovn-org/libovsdb/model"
 
+const (
+	// TableAtomicTable is the name of the atomicTable table
+	TableAtomicTable = "atomicTable"
+)
+
+// names of the columns of the atomicTable table
+const (
+	AtomicTableColumnUUID      = "_uuid"
+	AtomicTableColumnEventType = "event_type"
+	AtomicTableColumnFloat     = "float"
+	AtomicTableColumnInt       = "int"
+	AtomicTableColumnProtocol  = "protocol"
+	AtomicTableColumnStr       = "str"
+)
+
 type (
 	AtomicTableEventType = string
 	AtomicTableProtocol  = string
@@ -176,7 +312,7 @@ type AtomicTable struct {
 	UUID      string               ` + "`" + `ovsdb:"_uuid"` + "`" + `
 	EventType AtomicTableEventType ` + "`" + `ovsdb:"event_type"` + "`" + `
 	Float     float64              ` + "`" + `ovsdb:"float"` + "`" + `
-	Int       int                  ` + "`" + `ovsdb:"int"` + "`" + `
+	Int       int64                ` + "`" + `ovsdb:"int"` + "`" + `
 	Protocol  *AtomicTableProtocol ` + "`" + `ovsdb:"protocol"` + "`" + `
 	Str       string               ` + "`" + `ovsdb:"str"` + "`" + `
 }
@@ -233,6 +369,9 @@ func (a *AtomicTable) EqualsModel(b model.Model) bool {
 	return a.Equals(c)
 }
 
+func (a *AtomicTable) Normalize() {
+}
+
 var _ model.CloneableModel = &AtomicTable{}
 var _ model.ComparableModel = &AtomicTable{}
 `,
@@ -287,9 +426,26 @@ func (a *AtomicTable) PrintAtomicTableOtherProtocol() bool {
 
 package test
 
-import "github.com/ovn-org/libovsdb/model"
+import (
+	"fmt"
 
-import "fmt"
+	"github.com/ovn-org/libovsdb/model"
+)
+
+const (
+	// TableAtomicTable is the name of the atomicTable table
+	TableAtomicTable = "atomicTable"
+)
+
+// names of the columns of the atomicTable table
+const (
+	AtomicTableColumnUUID      = "_uuid"
+	AtomicTableColumnEventType = "event_type"
+	AtomicTableColumnFloat     = "float"
+	AtomicTableColumnInt       = "int"
+	AtomicTableColumnProtocol  = "protocol"
+	AtomicTableColumnStr       = "str"
+)
 
 type (
 	AtomicTableEventType = string
@@ -308,14 +464,14 @@ type AtomicTable struct {
 	UUID      string               ` + "`" + `ovsdb:"_uuid"` + "`" + `
 	EventType AtomicTableEventType ` + "`" + `ovsdb:"event_type"` + "`" + `
 	Float     float64              ` + "`" + `ovsdb:"float"` + "`" + `
-	Int       int                  ` + "`" + `ovsdb:"int"` + "`" + `
+	Int       int64                ` + "`" + `ovsdb:"int"` + "`" + `
 	Protocol  *AtomicTableProtocol ` + "`" + `ovsdb:"protocol"` + "`" + `
 	Str       string               ` + "`" + `ovsdb:"str"` + "`" + `
 
 	OtherUUID      string
 	OtherEventType string
 	OtherFloat     float64
-	OtherInt       int
+	OtherInt       int64
 	OtherProtocol  *string
 	OtherStr       string
 }
@@ -396,6 +552,9 @@ func (a *AtomicTable) EqualsModel(b model.Model) bool {
 	return a.Equals(c)
 }
 
+func (a *AtomicTable) Normalize() {
+}
+
 var _ model.CloneableModel = &AtomicTable{}
 var _ model.ComparableModel = &AtomicTable{}
 `,
@@ -413,12 +572,27 @@ package test
 
 import "github.com/ovn-org/libovsdb/model"
 
+const (
+	// TableAtomicTable is the name of the atomicTable table
+	TableAtomicTable = "atomicTable"
+)
+
+// names of the columns of the atomicTable table
+const (
+	AtomicTableColumnUUID      = "_uuid"
+	AtomicTableColumnEventType = "event_type"
+	AtomicTableColumnFloat     = "float"
+	AtomicTableColumnInt       = "int"
+	AtomicTableColumnProtocol  = "protocol"
+	AtomicTableColumnStr       = "str"
+)
+
 // AtomicTable defines an object in atomicTable table
 type AtomicTable struct {
 	UUID      string  ` + "`" + `ovsdb:"_uuid"` + "`" + `
 	EventType string  ` + "`" + `ovsdb:"event_type"` + "`" + `
 	Float     float64 ` + "`" + `ovsdb:"float"` + "`" + `
-	Int       int     ` + "`" + `ovsdb:"int"` + "`" + `
+	Int       int64   ` + "`" + `ovsdb:"int"` + "`" + `
 	Protocol  *string ` + "`" + `ovsdb:"protocol"` + "`" + `
 	Str       string  ` + "`" + `ovsdb:"str"` + "`" + `
 }
@@ -475,6 +649,9 @@ func (a *AtomicTable) EqualsModel(b model.Model) bool {
 	return a.Equals(c)
 }
 
+func (a *AtomicTable) Normalize() {
+}
+
 var _ model.CloneableModel = &AtomicTable{}
 var _ model.ComparableModel = &AtomicTable{}
 `,
@@ -498,6 +675,21 @@ func {{ index . "TestName" }} () string {
 
 package test
 
+const (
+	// TableAtomicTable is the name of the atomicTable table
+	TableAtomicTable = "atomicTable"
+)
+
+// names of the columns of the atomicTable table
+const (
+	AtomicTableColumnUUID      = "_uuid"
+	AtomicTableColumnEventType = "event_type"
+	AtomicTableColumnFloat     = "float"
+	AtomicTableColumnInt       = "int"
+	AtomicTableColumnProtocol  = "protocol"
+	AtomicTableColumnStr       = "str"
+)
+
 type (
 	AtomicTableEventType = string
 	AtomicTableProtocol  = string
@@ -515,7 +707,7 @@ type AtomicTable struct {
 	UUID      string               ` + "`" + `ovsdb:"_uuid"` + "`" + `
 	EventType AtomicTableEventType ` + "`" + `ovsdb:"event_type"` + "`" + `
 	Float     float64              ` + "`" + `ovsdb:"float"` + "`" + `
-	Int       int                  ` + "`" + `ovsdb:"int"` + "`" + `
+	Int       int64                ` + "`" + `ovsdb:"int"` + "`" + `
 	Protocol  *AtomicTableProtocol ` + "`" + `ovsdb:"protocol"` + "`" + `
 	Str       string               ` + "`" + `ovsdb:"str"` + "`" + `
 }
@@ -552,11 +744,7 @@ WRONG FORMAT
 			fakeTable := "atomicTable"
 			tmpl := NewTableTemplate()
 			table := schema.Tables[fakeTable]
-			data := GetTableTemplateData(
-				"test",
-				fakeTable,
-				&table,
-			)
+			data := tableTemplateData(t, fakeTable, &table)
 			if tt.err {
 				assert.NotNil(t, err)
 			} else {
@@ -579,12 +767,310 @@ WRONG FORMAT
 	}
 }
 
+func TestNewTableTemplateIntegerEnum(t *testing.T) {
+	table := tableSchema(t, `{
+		"level": {
+			"type": {"key": {"type": "integer",
+					 "enum": ["set", [0, 1, 10]]}}
+		}
+	}`)
+	code := generateTable(t, "atomicTable", table)
+	assert.Equal(t, `// Code generated by "libovsdb.modelgen"
+// DO NOT EDIT.
+
+package test
+
+const (
+	// TableAtomicTable is the name of the atomicTable table
+	TableAtomicTable = "atomicTable"
+)
+
+// names of the columns of the atomicTable table
+const (
+	AtomicTableColumnUUID  = "_uuid"
+	AtomicTableColumnLevel = "level"
+)
+
+type (
+	AtomicTableLevel = int64
+)
+
+var (
+	AtomicTableLevel0  AtomicTableLevel = 0
+	AtomicTableLevel1  AtomicTableLevel = 1
+	AtomicTableLevel10 AtomicTableLevel = 10
+)
+
+// AtomicTable defines an object in atomicTable table
+type AtomicTable struct {
+	UUID  string           `+"`"+`ovsdb:"_uuid"`+"`"+`
+	Level AtomicTableLevel `+"`"+`ovsdb:"level"`+"`"+`
+}
+`, code)
+}
+
+func TestPrintVal(t *testing.T) {
+	tests := []struct {
+		val      interface{}
+		typ      string
+		expected string
+	}{
+		{"tcp", "string", `"tcp"`},
+		{`say "hi"`, "string", `"say \"hi\""`},
+		{`C:\path`, "string", `"C:\\path"`},
+		{float64(5), "int64", "5"},
+		{float64(-5), "int64", "-5"},
+		{float64(4294967296), "int64", "4294967296"},
+		{int64(5), "int64", "5"},
+		{float64(5), "int", "5"},
+		{0.1, "float64", "0.1"},
+		{2.0, "float64", "2"},
+		{-1.5, "float64", "-1.5"},
+		{1e21, "float64", "1e+21"},
+		{true, "bool", "true"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, printVal(tt.val, tt.typ))
+	}
+}
+
+func TestNewTableTemplateEnumValues(t *testing.T) {
+	table := tableSchema(t, `{
+		"quote": {
+			"type": {"key": {"type": "string",
+					 "enum": ["set", ["say \"hi\"", "back\\slash"]]}}
+		},
+		"level": {
+			"type": {"key": {"type": "integer",
+					 "enum": ["set", [5, -1, 4294967296]]}}
+		},
+		"ratio": {
+			"type": {"key": {"type": "real",
+					 "enum": ["set", [0.1, 2.0]]}}
+		}
+	}`)
+	// a member as decoded from JSON, like in a schema not unmarshaled by
+	// ovsdb.DatabaseSchema
+	table.Columns["level"].TypeObj.Key.Enum[0] = float64(5)
+	table.Columns["level"].TypeObj.Key.Enum[2] = float64(4294967296)
+
+	src := generateTable(t, "atomicTable", table, WithEnumPredicates(), WithEnumValidation())
+	code := strings.Join(strings.Fields(src), " ")
+	assert.Contains(t, code, `AtomicTableQuoteSayHi AtomicTableQuote = "say \"hi\""`)
+	assert.Contains(t, code, `AtomicTableQuoteBackSlash AtomicTableQuote = "back\\slash"`)
+	assert.Contains(t, code, `AtomicTableLevel5 AtomicTableLevel = 5 `)
+	assert.Contains(t, code, `AtomicTableLevelMinus1 AtomicTableLevel = -1 `)
+	assert.Contains(t, code, `AtomicTableLevel4294967296 AtomicTableLevel = 4294967296 `)
+	assert.Contains(t, code, `AtomicTableRatio = float64`)
+	assert.Contains(t, code, `AtomicTableRatio01 AtomicTableRatio = 0.1 `)
+	assert.Contains(t, code, `AtomicTableRatio2 AtomicTableRatio = 2 `)
+	assert.Contains(t, code, "Ratio AtomicTableRatio `ovsdb:\"ratio\"`")
+
+	// the code is valid
+	checkCode(t, src)
+}
+
+func TestNewTableTemplateEnumDescriptions(t *testing.T) {
+	table := tableSchema(t, `{
+		"action": {
+			"type": {"key": {"type": "string",
+					 "enum": ["set", ["allow", "allow-related", "drop"]],
+					 "enumDoc": {"allow": "Forward the packet.",
+						     "drop": "Silently drop the packet."}}}
+		},
+		"direction": {
+			"type": {"key": {"type": "string",
+					 "enum": ["set", ["from-lport", "to-lport"]]}}
+		}
+	}`)
+	code := generateTable(t, "ACL", table)
+	assert.Equal(t, `// Code generated by "libovsdb.modelgen"
+// DO NOT EDIT.
+
+package test
+
+const (
+	// TableACL is the name of the ACL table
+	TableACL = "ACL"
+)
+
+// names of the columns of the ACL table
+const (
+	ACLColumnUUID      = "_uuid"
+	ACLColumnAction    = "action"
+	ACLColumnDirection = "direction"
+)
+
+type (
+	ACLAction    = string
+	ACLDirection = string
+)
+
+var (
+	ACLActionAllow        ACLAction    = "allow"
+	ACLActionAllowRelated ACLAction    = "allow-related"
+	ACLActionDrop         ACLAction    = "drop"
+	ACLDirectionFromLport ACLDirection = "from-lport"
+	ACLDirectionToLport   ACLDirection = "to-lport"
+)
+
+// ACLActionDescriptions documents the members of ACLAction
+var ACLActionDescriptions = map[ACLAction]string{
+	ACLActionAllow: "Forward the packet.",
+	ACLActionDrop:  "Silently drop the packet.",
+}
+
+// ACL defines an object in ACL table
+type ACL struct {
+	UUID      string       `+"`"+`ovsdb:"_uuid"`+"`"+`
+	Action    ACLAction    `+"`"+`ovsdb:"action"`+"`"+`
+	Direction ACLDirection `+"`"+`ovsdb:"direction"`+"`"+`
+}
+`, code)
+}
+
+// declarations returns the names of the top-level declarations of a Go
+// source file, methods being prefixed with their receiver type
+func declarations(t *testing.T, src []byte) []string {
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	require.NoError(t, err)
+	names := []string{}
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			name := d.Name.Name
+			if d.Recv != nil {
+				recv := d.Recv.List[0].Type
+				if star, ok := recv.(*ast.StarExpr); ok {
+					recv = star.X
+				}
+				name = recv.(*ast.Ident).Name + "." + name
+			}
+			names = append(names, name)
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch sp := spec.(type) {
+				case *ast.TypeSpec:
+					names = append(names, sp.Name.Name)
+				case *ast.ValueSpec:
+					for _, n := range sp.Names {
+						if n.Name != "_" {
+							names = append(names, n.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+func TestNewTableTemplateSplit(t *testing.T) {
+	table := tableSchema(t, `{
+		"str": {
+			"type": "string"
+		},
+		"protocol": {
+			"type": {"key": {"type": "string",
+					 "enum": ["set", ["tcp", "udp", "sctp"]]},
+					 "min": 0, "max": 1}},
+		"ports": {
+			"type": {"key": "integer", "min": 0, "max": "unlimited"}},
+		"external_ids": {
+			"type": {"key": "string", "value": "string",
+					 "min": 0, "max": "unlimited"}}
+	}`)
+	// the only set column is held in an array, which is normalized in the
+	// helpers too
+	arrayTable := tableSchema(t, `{
+		"addresses": {
+			"type": {"key": "string", "min": 0, "max": 3}}
+	}`)
+
+	tests := []struct {
+		name     string
+		table    *ovsdb.TableSchema
+		extended bool
+		files    []string
+	}{
+		{
+			name:     "base",
+			table:    table,
+			extended: false,
+			files:    []string{"atomictable_types.go"},
+		},
+		{
+			name:     "extended",
+			table:    table,
+			extended: true,
+			files:    []string{"atomictable_helpers.go", "atomictable_methods.go", "atomictable_types.go"},
+		},
+		{
+			name:     "extended array",
+			table:    arrayTable,
+			extended: true,
+			files:    []string{"atomictable_helpers.go", "atomictable_methods.go", "atomictable_types.go"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewGenerator()
+			require.NoError(t, err)
+			tmpl := NewTableTemplate()
+			data := tableTemplateData(t, "atomicTable", tt.table)
+			data.WithExtendedGen(tt.extended)
+			data.WithColumnSchema(tt.extended)
+
+			whole, err := g.Format(tmpl, data)
+			require.NoError(t, err)
+			expected := declarations(t, whole)
+			sort.Strings(expected)
+
+			dir := t.TempDir()
+			for _, part := range data.Parts() {
+				data.WithPart(part)
+				err := g.Generate(filepath.Join(dir, PartFileName("atomicTable", part)), tmpl, data)
+				require.NoError(t, err)
+			}
+
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			files := []string{}
+			srcs := []string{}
+			decls := []string{}
+			for _, entry := range entries {
+				files = append(files, entry.Name())
+				src, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+				require.NoError(t, err)
+				assert.Contains(t, string(src), "// Code generated by \"libovsdb.modelgen\"\n// DO NOT EDIT.\n\npackage test\n")
+				srcs = append(srcs, string(src))
+				decls = append(decls, declarations(t, src)...)
+			}
+			assert.Equal(t, tt.files, files)
+			checkCode(t, srcs...)
+
+			// every declaration of the single file is generated exactly once
+			// across the split files
+			sort.Strings(decls)
+			assert.Equal(t, expected, decls)
+		})
+	}
+}
+
 func TestFieldName(t *testing.T) {
 	cases := []struct {
 		in       string
 		expected string
 	}{
 		{"foo", "Foo"},
+		{"_uuid", "UUID"},
+		{"type", "Type"},
+		{"range", "Range"},
+		{"func", "Func"},
+		{"2nd_port", "X2ndPort"},
+		{"_", "Underscore"},
+		{"__", "UnderscoreUnderscore"},
+		{"_-", "UnderscoreHyphen"},
 	}
 	for _, tt := range cases {
 		if s := FieldName(tt.in); s != tt.expected {
@@ -598,21 +1084,129 @@ func TestStructName(t *testing.T) {
 	if s := StructName("Foo_Bar"); s != "FooBar" {
 		t.Fatalf("got %s, wanted FooBar", s)
 	}
+	tests := map[string]string{
+		"DHCP_Options":          "DHCPOptions",
+		"ipfix-config":          "IPFIXConfig",
+		"logical-switch_port":   "LogicalSwitchPort",
+		"mac__binding--entries": "MACBindingEntries",
+		"_private_table_":       "PrivateTable",
+		"Open_vSwitch":          "OpenvSwitch",
+		"sFlow":                 "SFlow",
+		"QoS":                   "QoS",
+		"atomicTable":           "AtomicTable",
+		"table1":                "Table1",
+	}
+	for table, expected := range tests {
+		assert.Equal(t, expected, StructName(table), table)
+	}
+
+	// the enums are named after the struct
+	var column ovsdb.ColumnSchema
+	err := json.Unmarshal([]byte(`{"type": {"key": {"type": "string", "enum": ["set", ["a", "b"]]}}}`), &column)
+	require.NoError(t, err)
+	enum := FieldEnum("ipfix-config", "cache-mode", &column)
+	require.NotNil(t, enum)
+	assert.Equal(t, "IPFIXConfigCacheMode", enum.Alias)
+}
+
+func TestTitle(t *testing.T) {
+	for _, s := range []string{"", "foo", "foo bar", "foo.bar", "foo_bar", "l3gateway", "élan", "ünïcode wörds", "a-b:c"} {
+		assert.Equal(t, strings.Title(s), title(s), s)
+	}
 }
 
-/*
-TODO: Write Test
 func TestFieldType(t *testing.T) {
+	tests := []struct {
+		name      string
+		column    string
+		withEnums string
+		out       string
+	}{
+		{
+			name:   "required integer",
+			column: `{"type": "integer"}`,
+			out:    "int64",
+		},
+		{
+			name:   "required integer set of one",
+			column: `{"type": {"key": "integer", "min": 1, "max": 1}}`,
+			out:    "int64",
+		},
+		{
+			name:   "optional integer",
+			column: `{"type": {"key": "integer", "min": 0, "max": 1}}`,
+			out:    "*int64",
+		},
+		{
+			name:      "optional enum",
+			column:    `{"type": {"key": {"type": "string", "enum": ["set", ["tcp", "udp"]]}, "min": 0, "max": 1}}`,
+			withEnums: "*AtomicTableCol",
+			out:       "*string",
+		},
+		{
+			name:      "required enum",
+			column:    `{"type": {"key": {"type": "string", "enum": ["set", ["tcp", "udp"]]}}}`,
+			withEnums: "AtomicTableCol",
+			out:       "string",
+		},
+		{
+			name:   "set",
+			column: `{"type": {"key": "string", "min": 0, "max": "unlimited"}}`,
+			out:    "[]string",
+		},
+		{
+			name:      "enum set",
+			column:    `{"type": {"key": {"type": "string", "enum": ["set", ["tcp", "udp"]]}, "min": 0, "max": "unlimited"}}`,
+			withEnums: "[]AtomicTableCol",
+			out:       "[]string",
+		},
+		{
+			name:      "bounded enum set",
+			column:    `{"type": {"key": {"type": "string", "enum": ["set", ["tcp", "udp"]]}, "min": 0, "max": 2}}`,
+			withEnums: "[2]AtomicTableCol",
+			out:       "[2]string",
+		},
+		{
+			name:   "map",
+			column: `{"type": {"key": "string", "value": "integer", "min": 0, "max": "unlimited"}}`,
+			out:    "map[string]int64",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var column ovsdb.ColumnSchema
+			err := json.Unmarshal([]byte(tt.column), &column)
+			require.NoError(t, err)
+			assert.Equal(t, tt.out, FieldType("atomicTable", "col", &column))
+			withEnums := tt.withEnums
+			if withEnums == "" {
+				withEnums = tt.out
+			}
+			assert.Equal(t, withEnums, FieldTypeWithEnums("atomicTable", "col", &column))
+		})
+	}
+}
+
+func TestBaseType(t *testing.T) {
 	tests := []struct {
 		name string
-		in   *ovsdb.ColumnSchema
+		in   string
 		out  string
+	}{
+		{"IntegerToInt64", `"integer"`, "int64"},
+		{"BoundedIntegerToInt64", `{"type": "integer", "minInteger": 0, "maxInteger": 4095}`, "int64"},
+		{"RealToFloat", `"real"`, "float64"},
+		{"UUIDToString", `{"type": "uuid", "refTable": "Bridge"}`, "string"},
 	}
-	if got := FieldType(tt.args.column); got != tt.want {
-		t.Errorf("FieldType() = %v, want %v", got, tt.want)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var base ovsdb.BaseType
+			err := json.Unmarshal([]byte(tt.in), &base)
+			require.NoError(t, err)
+			assert.Equal(t, tt.out, BaseType(&base))
+		})
 	}
 }
-*/
 
 func TestAtomicType(t *testing.T) {
 	tests := []struct {
@@ -642,12 +1236,84 @@ func TestTag(t *testing.T) {
 	}
 }
 
+func TestJSONTag(t *testing.T) {
+	assert.Equal(t, `json:"external_ids,omitempty"`, JSONTag("external_ids"))
+	assert.Equal(t, `json:"uuid,omitempty"`, JSONTag("_uuid"))
+}
+
+func TestNewTableTemplateJSONTags(t *testing.T) {
+	table := tableSchema(t, `{
+		"str": {
+			"type": "string"
+		},
+		"ports": {
+			"type": {"key": "integer", "min": 0, "max": "unlimited"}
+		},
+		"external_ids": {
+			"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}
+		}
+	}`)
+
+	data := tableTemplateData(t, "atomicTable", table)
+	assert.NotContains(t, formatTable(t, data), "json:")
+
+	data.WithJSONTags(true)
+	code := formatTable(t, data)
+	assert.Contains(t, code, "UUID        string            `ovsdb:\"_uuid\" json:\"uuid,omitempty\"`")
+	assert.Contains(t, code, "ExternalIDs map[string]string `ovsdb:\"external_ids\" json:\"external_ids,omitempty\"`")
+	assert.Contains(t, code, "Ports       []int64           `ovsdb:\"ports\" json:\"ports,omitempty\"`")
+	assert.Contains(t, code, "Str         string            `ovsdb:\"str\" json:\"str,omitempty\"`")
+}
+
+func TestNewTableTemplateConstants(t *testing.T) {
+	table := tableSchema(t, `{
+		"name": {
+			"type": "string"
+		},
+		"other_config": {
+			"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}
+		}
+	}`)
+
+	data := tableTemplateData(t, "Logical_Switch", table)
+	assert.Equal(t, []ColumnConstant{
+		{Column: "_uuid", Constant: "LogicalSwitchColumnUUID"},
+		{Column: "name", Constant: "LogicalSwitchColumnName"},
+		{Column: "other_config", Constant: "LogicalSwitchColumnOtherConfig"},
+	}, data["Columns"])
+	assert.Contains(t, formatTable(t, data), `const (
+	// TableLogicalSwitch is the name of the Logical_Switch table
+	TableLogicalSwitch = "Logical_Switch"
+)
+
+// names of the columns of the Logical_Switch table
+const (
+	LogicalSwitchColumnUUID        = "_uuid"
+	LogicalSwitchColumnName        = "name"
+	LogicalSwitchColumnOtherConfig = "other_config"
+)
+`)
+
+	tmpl := NewTableTemplate()
+	_, err := tmpl.Parse(`{{ define "constants" }}// no constants{{ end }}`)
+	require.NoError(t, err)
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	b, err := g.Format(tmpl, data)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "LogicalSwitchColumn")
+}
+
 func TestFileName(t *testing.T) {
 	if s := FileName("foo"); s != "foo.go" {
 		t.Fatalf("got %s, wanted foo.go", s)
 	}
 }
 
+func TestPartFileName(t *testing.T) {
+	assert.Equal(t, "foo_types.go", PartFileName("Foo", TableTypesPart))
+}
+
 func TestCamelCase(t *testing.T) {
 	cases := []struct {
 		in       string
@@ -662,14 +1328,106 @@ func TestCamelCase(t *testing.T) {
 		{"dns_records", "DNSRecords"},
 		{"logical_ip", "LogicalIP"},
 		{"ip", "IP"},
+		{"foo-", "Foo"},
+		{"-", ""},
 	}
 	for _, tt := range cases {
-		if s := camelCase(tt.in); s != tt.expected {
+		if s := camelCase(tt.in, defaultInitialisms); s != tt.expected {
 			t.Fatalf("got %s, wanted %s", s, tt.expected)
 		}
 	}
 }
 
+func TestWithInitialisms(t *testing.T) {
+	o, err := newOptions(WithInitialisms(map[string]bool{"vtep": true}))
+	require.NoError(t, err)
+	assert.Equal(t, "VTEPLogicalSwitch", camelCase("vtep_logical_switch", o.nameInitialisms()))
+	assert.Equal(t, "LocalVTEPs", camelCase("local_vteps", o.nameInitialisms()))
+	assert.Equal(t, "VTEP", camelCase("VTEP", o.nameInitialisms()))
+	assert.Equal(t, "IDString", fieldName("id_string", o.nameInitialisms()))
+
+	// the initialisms are removed, common ones included, and the options
+	// add up
+	o, err = newOptions(WithInitialisms(map[string]bool{"vtep": true}), WithInitialisms(map[string]bool{"Vtep": false, "id": false}))
+	require.NoError(t, err)
+	assert.Equal(t, "VtepLogicalSwitch", camelCase("vtep_logical_switch", o.nameInitialisms()))
+	assert.Equal(t, "IdString", fieldName("id_string", o.nameInitialisms()))
+	assert.Equal(t, "ExternalIds", fieldName("external_ids", o.nameInitialisms()))
+	assert.Equal(t, "LogicalIPs", fieldName("logical_ips", o.nameInitialisms()))
+
+	// the common initialisms are left unchanged
+	assert.True(t, defaultInitialisms["ID"])
+	assert.False(t, defaultInitialisms["VTEP"])
+	assert.Equal(t, "IDString", FieldName("id_string"))
+
+	table := tableSchema(t, `{
+		"id_string": {
+			"type": "string"
+		},
+		"vtep_mode": {
+			"type": {"key": {"type": "string", "enum": ["set", ["ip", "vtep"]]}}
+		}
+	}`)
+	schema := ovsdb.DatabaseSchema{Name: "AtomicDB", Tables: map[string]ovsdb.TableSchema{"vtep_table": *table}}
+	opt := WithInitialisms(map[string]bool{"vtep": true, "uuid": false, "ip": false})
+	code := strings.Join(strings.Fields(generateTable(t, "vtep_table", table, opt, WithModelMethods())), " ")
+	assert.Contains(t, code, "type VTEPTable struct {")
+	assert.Contains(t, code, "Uuid string `ovsdb:\"_uuid\"`")
+	assert.Contains(t, code, "IDString string `ovsdb:\"id_string\"`")
+	assert.Contains(t, code, "VTEPMode VTEPTableVTEPMode `ovsdb:\"vtep_mode\"`")
+	assert.Contains(t, code, `VTEPTableVTEPModeIp VTEPTableVTEPMode = "ip"`)
+	assert.Contains(t, code, `VTEPTableVTEPModeVTEP VTEPTableVTEPMode = "vtep"`)
+	assert.Contains(t, code, "VTEPTableColumnVTEPMode = \"vtep_mode\"")
+	assert.Contains(t, code, "return a.Uuid")
+	dbData, err := GetDBTemplateData("test", schema, opt)
+	require.NoError(t, err)
+	assert.Equal(t, []TableInfo{{TableName: "vtep_table", StructName: "VTEPTable"}}, dbData["Tables"])
+
+	// the code generated without the option keeps the common initialisms
+	code = strings.Join(strings.Fields(generateTable(t, "vtep_table", table)), " ")
+	assert.Contains(t, code, "type VtepTable struct {")
+	assert.Contains(t, code, "UUID string `ovsdb:\"_uuid\"`")
+	assert.Contains(t, code, `VtepTableVtepModeIP VtepTableVtepMode = "ip"`)
+}
+
+func TestWithStructNamePrefix(t *testing.T) {
+	table := tableSchema(t, `{
+		"type": {
+			"type": {"key": {"type": "string", "enum": ["set", ["router", "localnet"]]}}
+		},
+		"modes": {
+			"type": {"key": {"type": "string", "enum": ["set", ["a", "b"]]}, "min": 0, "max": "unlimited"}
+		}
+	}`)
+	schema := ovsdb.DatabaseSchema{
+		Name:   "OVN_Northbound",
+		Tables: map[string]ovsdb.TableSchema{"Logical_Switch_Port": *table},
+	}
+
+	data := tableTemplateData(t, "Logical_Switch_Port", table, WithStructNamePrefix("nb"))
+	assert.Equal(t, "NbLogicalSwitchPort", data["StructName"])
+	assert.Equal(t, "Nb", data["StructNamePrefix"])
+	code := strings.Join(strings.Fields(formatTable(t, data)), " ")
+	assert.Contains(t, code, "type NbLogicalSwitchPort struct {")
+	assert.Contains(t, code, "Type NbLogicalSwitchPortType `ovsdb:\"type\"`")
+	assert.Contains(t, code, "Modes []NbLogicalSwitchPortModes `ovsdb:\"modes\"`")
+	assert.Contains(t, code, `NbLogicalSwitchPortTypeRouter NbLogicalSwitchPortType = "router"`)
+	assert.Contains(t, code, `TableNbLogicalSwitchPort = "Logical_Switch_Port"`)
+	assert.Contains(t, code, `NbLogicalSwitchPortColumnType = "type"`)
+
+	dbData, err := GetDBTemplateData("test", schema, WithStructNamePrefix("nb"))
+	require.NoError(t, err)
+	assert.Equal(t, []TableInfo{{TableName: "Logical_Switch_Port", StructName: "NbLogicalSwitchPort"}}, dbData["Tables"])
+
+	// the prefix only applies to the code generated with the option
+	assert.Equal(t, "LogicalSwitchPort", StructName("Logical_Switch_Port"))
+	data = tableTemplateData(t, "Logical_Switch_Port", table)
+	assert.Equal(t, "LogicalSwitchPort", data["StructName"])
+	dbData, err = GetDBTemplateData("test", schema)
+	require.NoError(t, err)
+	assert.Equal(t, []TableInfo{{TableName: "Logical_Switch_Port", StructName: "LogicalSwitchPort"}}, dbData["Tables"])
+}
+
 func ExampleNewTableTemplate() {
 	schemaString := []byte(`
 	{
@@ -695,7 +1453,10 @@ func ExampleNewTableTemplate() {
 	}
 
 	base := NewTableTemplate()
-	data := GetTableTemplateData("mypackage", "table1", schema.Table("table1"))
+	data, err := GetTableTemplateData("mypackage", "table1", schema.Table("table1"))
+	if err != nil {
+		panic(err)
+	}
 
 	// Add a function at after the struct definition
 	// It can access the default data values plus any extra field that is added to data
@@ -734,6 +1495,54 @@ func TestExtendedGenComparableModel(t *testing.T) {
 	}(a)
 }
 
+func TestExtendedGenNormalize(t *testing.T) {
+	a := buildTestBridge()
+	a.Ports = []string{"c", "a", "b", "a"}
+	a.Protocols = []string{vswitchd.BridgeProtocolsOpenflow13, vswitchd.BridgeProtocolsOpenflow10}
+	a.Mirrors = nil
+	a.OtherConfig = nil
+	a.FloodVLANs = [4096]int64{30, 10, 20}
+
+	b := a.DeepCopy()
+	b.Ports = []string{"b", "c", "a"}
+	b.Protocols = []string{vswitchd.BridgeProtocolsOpenflow10, vswitchd.BridgeProtocolsOpenflow13}
+	b.Mirrors = []string{}
+	b.OtherConfig = map[string]string{}
+	b.FloodVLANs = [4096]int64{20, 30, 10}
+	assert.NotEqual(t, a, b)
+	// the sets held in arrays are compared regardless of their order
+	c := a.DeepCopy()
+	c.FloodVLANs = b.FloodVLANs
+	assert.True(t, a.Equals(c))
+
+	a.Normalize()
+	b.Normalize()
+	assert.Equal(t, a, b)
+	assert.Equal(t, []string{"a", "b", "c"}, a.Ports)
+	assert.Equal(t, []string{vswitchd.BridgeProtocolsOpenflow10, vswitchd.BridgeProtocolsOpenflow13}, a.Protocols)
+	assert.Equal(t, [4096]int64{0, 10, 20, 30}, a.FloodVLANs)
+	assert.NotNil(t, a.Mirrors)
+	assert.NotNil(t, a.OtherConfig)
+}
+
+func TestExtendedGenColumnSchema(t *testing.T) {
+	a := vswitchd.Bridge{}
+
+	column, ok := a.ColumnSchema("ports")
+	require.True(t, ok)
+	assert.Equal(t, ovsdb.TypeSet, column.Type)
+	assert.Equal(t, ovsdb.TypeUUID, column.TypeObj.Key.Type)
+	assert.Equal(t, 0, column.TypeObj.Min())
+	assert.Equal(t, ovsdb.Unlimited, column.TypeObj.Max())
+
+	column, ok = a.ColumnSchema("_uuid")
+	require.True(t, ok)
+	assert.Equal(t, ovsdb.TypeUUID, column.Type)
+
+	_, ok = a.ColumnSchema("foo")
+	assert.False(t, ok)
+}
+
 func doGenDeepCopy(data model.CloneableModel, b *testing.B) {
 	_ = data.CloneModel()
 }
@@ -764,8 +1573,8 @@ func buildTestBridge() *vswitchd.Bridge {
 		DatapathVersion:     *buildRandStr(),
 		ExternalIDs:         map[string]string{*buildRandStr(): *buildRandStr(), *buildRandStr(): *buildRandStr()},
 		FailMode:            &vswitchd.BridgeFailModeSecure,
-		FloodVLANs:          [4096]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
-		FlowTables:          map[int]string{1: *buildRandStr(), 2: *buildRandStr()},
+		FloodVLANs:          [4096]int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		FlowTables:          map[int64]string{1: *buildRandStr(), 2: *buildRandStr()},
 		IPFIX:               buildRandStr(),
 		McastSnoopingEnable: false,
 		Mirrors:             []string{*buildRandStr(), *buildRandStr()},
@@ -784,7 +1593,7 @@ func buildTestBridge() *vswitchd.Bridge {
 
 func buildTestInterface() *vswitchd.Interface {
 	aBool := false
-	aInt := 0
+	aInt := int64(0)
 	return &vswitchd.Interface{
 		UUID:                      *buildRandStr(),
 		AdminState:                buildRandStr(),
@@ -795,7 +1604,7 @@ func buildTestInterface() *vswitchd.Interface {
 		CFMFlapCount:              &aInt,
 		CFMHealth:                 &aInt,
 		CFMMpid:                   &aInt,
-		CFMRemoteMpids:            []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		CFMRemoteMpids:            []int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
 		CFMRemoteOpstate:          buildRandStr(),
 		Duplex:                    buildRandStr(),
 		Error:                     buildRandStr(),
@@ -819,7 +1628,7 @@ func buildTestInterface() *vswitchd.Interface {
 		OfportRequest:             &aInt,
 		Options:                   map[string]string{*buildRandStr(): *buildRandStr(), *buildRandStr(): *buildRandStr()},
 		OtherConfig:               map[string]string{*buildRandStr(): *buildRandStr(), *buildRandStr(): *buildRandStr()},
-		Statistics:                map[string]int{*buildRandStr(): 0, *buildRandStr(): 1},
+		Statistics:                map[string]int64{*buildRandStr(): 0, *buildRandStr(): 1},
 		Status:                    map[string]string{*buildRandStr(): *buildRandStr(), *buildRandStr(): *buildRandStr()},
 		Type:                      *buildRandStr(),
 	}
@@ -877,3 +1686,1393 @@ func BenchmarkDeepEqual(b *testing.B) {
 		})
 	}
 }
+
+func TestExtendedGenBuilder(t *testing.T) {
+	a := vswitchd.NewBridgeBuilder().
+		Name("br0").
+		FailMode(vswitchd.BridgeFailModeSecure).
+		Ports("a", "b").
+		ExternalIDs(map[string]string{"foo": "bar"}).
+		Build()
+	assert.Equal(t, &vswitchd.Bridge{
+		Name:        "br0",
+		FailMode:    &vswitchd.BridgeFailModeSecure,
+		Ports:       []string{"a", "b"},
+		ExternalIDs: map[string]string{"foo": "bar"},
+	}, a)
+
+	// optional fields left unset stay nil
+	assert.Nil(t, a.Netflow)
+
+	// the builder allocates a new pointer for every optional field
+	b := vswitchd.NewBridgeBuilder().Netflow("n1")
+	c := b.Build()
+	d := b.Netflow("n2").Build()
+	assert.Equal(t, "n1", *c.Netflow)
+	assert.Equal(t, "n2", *d.Netflow)
+}
+
+func TestExtendedGenFieldColumnMaps(t *testing.T) {
+	bridgeType := reflect.TypeOf(vswitchd.Bridge{})
+	require.Len(t, vswitchd.BridgeFieldToColumn, bridgeType.NumField())
+	require.Len(t, vswitchd.BridgeColumnToField, bridgeType.NumField())
+	for i := 0; i < bridgeType.NumField(); i++ {
+		field := bridgeType.Field(i)
+		column := field.Tag.Get("ovsdb")
+		assert.Equal(t, column, vswitchd.BridgeFieldToColumn[field.Name])
+		assert.Equal(t, field.Name, vswitchd.BridgeColumnToField[column])
+	}
+	assert.Equal(t, "_uuid", vswitchd.BridgeFieldToColumn["UUID"])
+	assert.Equal(t, "ExternalIDs", vswitchd.BridgeColumnToField["external_ids"])
+}
+
+func TestExtendedGenColumnTypes(t *testing.T) {
+	table := vswitchd.Schema().Tables["Bridge"]
+	require.Len(t, vswitchd.BridgeColumnTypes, len(table.Columns)+1)
+	for name, column := range table.Columns {
+		assert.Equal(t, *column.TypeObj, vswitchd.BridgeColumnTypes[name], name)
+	}
+	assert.Equal(t, ovsdb.TypeUUID, vswitchd.BridgeColumnTypes["_uuid"].Key.Type)
+
+	ports := vswitchd.BridgeColumnTypes["ports"]
+	assert.Equal(t, ovsdb.TypeUUID, ports.Key.Type)
+	assert.Nil(t, ports.Value)
+	assert.Equal(t, ovsdb.Unlimited, ports.Max())
+
+	externalIDs := vswitchd.BridgeColumnTypes["external_ids"]
+	assert.Equal(t, ovsdb.TypeString, externalIDs.Key.Type)
+	require.NotNil(t, externalIDs.Value)
+	assert.Equal(t, ovsdb.TypeString, externalIDs.Value.Type)
+	assert.Equal(t, 0, externalIDs.Min())
+}
+
+func TestGetTableTemplateDataEnumSet(t *testing.T) {
+	table := tableSchema(t, `{
+		"protocols": {
+			"type": {"key": {"type": "string", "enum": ["set", ["OpenFlow10", "OpenFlow13", "OpenFlow15"]]}, "min": 0, "max": "unlimited"}
+		}
+	}`)
+	data := tableTemplateData(t, "Bridge", table)
+	// the enum is registered once for the whole set
+	assert.Equal(t, []Enum{{
+		Type:        "string",
+		Alias:       "BridgeProtocols",
+		Sets:        []interface{}{"OpenFlow10", "OpenFlow13", "OpenFlow15"},
+		Column:      "protocols",
+		initialisms: defaultInitialisms,
+	}}, data["Enums"])
+
+	code := formatTable(t, data)
+	assert.Contains(t, code, "BridgeProtocols = string")
+	assert.Contains(t, code, `BridgeProtocolsOpenflow13 BridgeProtocols = "OpenFlow13"`)
+	assert.Contains(t, code, "Protocols []BridgeProtocols")
+}
+
+func TestNewTableTemplateEnumColumns(t *testing.T) {
+	table := tableSchema(t, `{
+		"type": {
+			"type": {"key": {"type": "string", "enum": ["set", ["router", "localnet"]]}}
+		},
+		"protocols": {
+			"type": {"key": {"type": "string", "enum": ["set", ["tcp", "udp"]]}, "min": 0, "max": "unlimited"}
+		},
+		"level": {
+			"type": {"key": {"type": "integer", "enum": ["set", [1, 2]]}}
+		},
+		"name": {
+			"type": "string"
+		}
+	}`)
+	code := formatTable(t, tableTemplateData(t, "Logical_Switch_Port", table, WithColumnTypes()))
+	assert.NotContains(t, code, "LogicalSwitchPortEnumColumns")
+	code = formatTable(t, tableTemplateData(t, "Logical_Switch_Port", table, WithEnumColumns()))
+	assert.NotContains(t, code, "LogicalSwitchPortColumnTypes")
+	assert.Contains(t, code, `// LogicalSwitchPortEnumColumns holds the members of each enum column of
+// LogicalSwitchPort
+var LogicalSwitchPortEnumColumns = map[string][]string{
+	"level":     {"1", "2"},
+	"protocols": {"tcp", "udp"},
+	"type":      {"router", "localnet"},
+}`)
+}
+
+func TestExtendedGenEnumColumns(t *testing.T) {
+	table := vswitchd.Schema().Tables["Bridge"]
+	expected := map[string][]string{}
+	for name, column := range table.Columns {
+		if column.TypeObj == nil || column.TypeObj.Key == nil || column.TypeObj.Key.Enum == nil {
+			continue
+		}
+		for _, member := range column.TypeObj.Key.Enum {
+			expected[name] = append(expected[name], fmt.Sprintf("%v", member))
+		}
+	}
+	require.NotEmpty(t, expected)
+	require.Len(t, vswitchd.BridgeEnumColumns, len(expected))
+	for name, members := range expected {
+		assert.ElementsMatch(t, members, vswitchd.BridgeEnumColumns[name], name)
+	}
+	assert.ElementsMatch(t, []string{"secure", "standalone"}, vswitchd.BridgeEnumColumns["fail_mode"])
+}
+
+func TestNewTableTemplateDeepCopy(t *testing.T) {
+	table := tableSchema(t, `{
+		"ports": {
+			"type": {"key": "string", "min": 0, "max": "unlimited"}
+		},
+		"name": {
+			"type": {"key": "string", "min": 0, "max": 1}
+		},
+		"external_ids": {
+			"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}
+		}
+	}`)
+	data := tableTemplateData(t, "atomicTable", table)
+	data.WithDeepCopy(true)
+	code := formatTable(t, data)
+	assert.Contains(t, code, `func (a *AtomicTable) DeepCopyInto(b *AtomicTable) {
+	*b = *a
+	b.ExternalIDs = copyAtomicTableExternalIDs(a.ExternalIDs)
+	b.Name = copyAtomicTableName(a.Name)
+	b.Ports = copyAtomicTablePorts(a.Ports)
+}
+
+func (a *AtomicTable) DeepCopy() *AtomicTable {
+	b := new(AtomicTable)
+	a.DeepCopyInto(b)
+	return b
+}`)
+	assert.Contains(t, code, `func copyAtomicTableName(a *string) *string {
+	if a == nil {
+		return nil
+	}
+	b := *a
+	return &b
+}`)
+	// none of the rest of the extended code is generated
+	assert.NotContains(t, code, "equalAtomicTable")
+	assert.NotContains(t, code, "normalizeAtomicTable")
+	assert.NotContains(t, code, "CloneModel")
+	assert.NotContains(t, code, "import")
+	assert.Equal(t, []string{TableTypesPart, TableMethodsPart, TableHelpersPart}, data.Parts())
+}
+
+func TestExtendedGenDeepCopy(t *testing.T) {
+	a := buildTestBridge()
+	b := a.DeepCopy()
+	assert.Equal(t, a, b)
+
+	// the slices and maps are not shared
+	assert.NotSame(t, &a.Ports[0], &b.Ports[0])
+	assert.NotSame(t, &a.Controller[0], &b.Controller[0])
+	assert.NotEqual(t, reflect.ValueOf(a.ExternalIDs).Pointer(), reflect.ValueOf(b.ExternalIDs).Pointer())
+	b.Ports[0] = "changed"
+	b.ExternalIDs["changed"] = "changed"
+	assert.NotEqual(t, "changed", a.Ports[0])
+	assert.NotContains(t, a.ExternalIDs, "changed")
+
+	// the optional fields point to new values
+	assert.NotSame(t, a.AutoAttach, b.AutoAttach)
+	assert.NotSame(t, a.FailMode, b.FailMode)
+	assert.Equal(t, *a.FailMode, *b.FailMode)
+
+	// nil fields stay nil
+	a.Mirrors = nil
+	a.Netflow = nil
+	b = a.DeepCopy()
+	assert.Nil(t, b.Mirrors)
+	assert.Nil(t, b.Netflow)
+}
+
+func TestNewTableTemplateEquals(t *testing.T) {
+	table := tableSchema(t, `{
+		"ports": {
+			"type": {"key": "string", "min": 0, "max": "unlimited"}
+		},
+		"name": {
+			"type": {"key": "string", "min": 0, "max": 1}
+		}
+	}`)
+	data := tableTemplateData(t, "atomicTable", table)
+	data.WithEquals(true)
+	code := formatTable(t, data)
+	assert.Contains(t, code, `func (a *AtomicTable) Equals(b *AtomicTable) bool {
+	return a.UUID == b.UUID &&
+		equalAtomicTableName(a.Name, b.Name) &&
+		equalAtomicTablePorts(a.Ports, b.Ports)
+}`)
+	assert.Contains(t, code, `func equalAtomicTablePorts(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}`)
+	// none of the rest of the extended code is generated
+	assert.NotContains(t, code, "copyAtomicTable")
+	assert.NotContains(t, code, "normalizeAtomicTable")
+	assert.NotContains(t, code, "EqualsModel")
+	assert.NotContains(t, code, "import")
+	assert.Equal(t, []string{TableTypesPart, TableMethodsPart, TableHelpersPart}, data.Parts())
+}
+
+func TestExtendedGenEquals(t *testing.T) {
+	a := buildTestBridge()
+	b := a.DeepCopy()
+	assert.True(t, a.Equals(b))
+
+	// a map value differs
+	b.ExternalIDs[a.Name] = "changed"
+	a.ExternalIDs[a.Name] = "original"
+	assert.False(t, a.Equals(b))
+
+	// the same set in a different order
+	b = a.DeepCopy()
+	b.Ports[0], b.Ports[1] = b.Ports[1], b.Ports[0]
+	assert.True(t, a.Equals(b))
+	b.Ports[0] = "changed"
+	assert.False(t, a.Equals(b))
+
+	// a nil set or map is empty
+	a.Mirrors = nil
+	a.OtherConfig = nil
+	b = a.DeepCopy()
+	b.Mirrors = []string{}
+	b.OtherConfig = map[string]string{}
+	assert.True(t, a.Equals(b))
+	assert.True(t, b.Equals(a))
+}
+
+func TestNewTableTemplateEnumPredicates(t *testing.T) {
+	table := tableSchema(t, `{
+		"type": {
+			"type": {"key": {"type": "string", "enum": ["set", ["router", "localnet"]]}}
+		},
+		"mode": {
+			"type": {"key": {"type": "string", "enum": ["set", ["active", "backup"]]}, "min": 0, "max": 1}
+		},
+		"protocols": {
+			"type": {"key": {"type": "string", "enum": ["set", ["tcp", "udp"]]}, "min": 0, "max": "unlimited"}
+		},
+		"level": {
+			"type": {"key": {"type": "integer", "enum": ["set", [1, 2]]}}
+		}
+	}`)
+	data := tableTemplateData(t, "Logical_Switch_Port", table, WithEnumPredicates())
+	code := formatTable(t, data)
+	assert.Contains(t, code, `// IsTypeRouter reports whether the Type of the
+// LogicalSwitchPort is "router"
+func (a *LogicalSwitchPort) IsTypeRouter() bool {
+	return a.Type == LogicalSwitchPortTypeRouter
+}`)
+	assert.Contains(t, code, `func (a *LogicalSwitchPort) IsModeBackup() bool {
+	return a.Mode != nil && *a.Mode == LogicalSwitchPortModeBackup
+}`)
+	// sets and non string enums have no predicates
+	assert.NotContains(t, code, "IsProtocols")
+	assert.NotContains(t, code, "IsLevel")
+	assert.Equal(t, []string{TableTypesPart, TableMethodsPart, TableHelpersPart}, data.Parts())
+
+	data = tableTemplateData(t, "Logical_Switch_Port", table, WithEnumPredicates(), WithoutEnumTypes())
+	code = formatTable(t, data)
+	assert.Contains(t, code, `func (a *LogicalSwitchPort) IsTypeLocalnet() bool {
+	return a.Type == "localnet"
+}`)
+}
+
+func TestExtendedGenEnumPredicates(t *testing.T) {
+	bridge := buildTestBridge()
+	secure := vswitchd.BridgeFailModeSecure
+	bridge.FailMode = &secure
+	assert.True(t, bridge.IsFailModeSecure())
+	assert.False(t, bridge.IsFailModeStandalone())
+
+	standalone := vswitchd.BridgeFailModeStandalone
+	bridge.FailMode = &standalone
+	assert.False(t, bridge.IsFailModeSecure())
+	assert.True(t, bridge.IsFailModeStandalone())
+
+	bridge.FailMode = nil
+	assert.False(t, bridge.IsFailModeSecure())
+	assert.False(t, bridge.IsFailModeStandalone())
+}
+
+func TestNewTableTemplateFieldNames(t *testing.T) {
+	table := tableSchema(t, `{
+		"2nd_port": {
+			"type": "string"
+		},
+		"_": {
+			"type": "string"
+		},
+		"type": {
+			"type": "string"
+		},
+		"level": {
+			"type": {"key": {"type": "integer", "enum": ["set", [1, 2]]}}
+		}
+	}`)
+	// the generated code is formatted, so it fails on invalid identifiers
+	data := tableTemplateData(t, "Logical_Switch_Port", table)
+	code := formatTable(t, data)
+	assert.Contains(t, code, "X2ndPort   string")
+	assert.Contains(t, code, "Underscore string")
+	assert.Contains(t, code, "Type       string")
+	assert.Contains(t, code, "LogicalSwitchPortColumnX2ndPort")
+	// enum members are appended to their type, they keep their leading digits
+	assert.Contains(t, code, "LogicalSwitchPortLevel1 LogicalSwitchPortLevel = 1")
+}
+
+func TestNewTableTemplateModelMethods(t *testing.T) {
+	table := tableSchema(t, `{
+		"name": {
+			"type": "string"
+		}
+	}`)
+	data := tableTemplateData(t, "Logical_Switch_Port", table, WithModelMethods())
+	code := formatTable(t, data)
+	assert.Contains(t, code, `// GetUUID returns the UUID of the LogicalSwitchPort
+func (a *LogicalSwitchPort) GetUUID() string {
+	return a.UUID
+}`)
+	assert.Contains(t, code, `// Table returns the name of the table of the LogicalSwitchPort
+func (a *LogicalSwitchPort) Table() string {
+	return TableLogicalSwitchPort
+}`)
+	assert.Equal(t, []string{TableTypesPart, TableMethodsPart, TableHelpersPart}, data.Parts())
+
+	data = tableTemplateData(t, "Logical_Switch_Port", table)
+	code = formatTable(t, data)
+	assert.NotContains(t, code, "GetUUID")
+}
+
+func TestExtendedGenModelMethods(t *testing.T) {
+	schema := vswitchd.Schema()
+	bridge := buildTestBridge()
+	assert.Equal(t, bridge.UUID, bridge.GetUUID())
+	_, ok := schema.Tables[bridge.Table()]
+	assert.True(t, ok)
+	assert.Equal(t, "Bridge", bridge.Table())
+
+	models := []interface {
+		GetUUID() string
+		Table() string
+	}{&vswitchd.Bridge{}, &vswitchd.Interface{}}
+	for _, m := range models {
+		_, ok := schema.Tables[m.Table()]
+		assert.True(t, ok, m.Table())
+	}
+}
+
+func TestNewTableTemplateMapMerge(t *testing.T) {
+	table := tableSchema(t, `{
+		"name": {
+			"type": "string"
+		},
+		"ports": {
+			"type": {"key": "string", "min": 0, "max": "unlimited"}
+		},
+		"external_ids": {
+			"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}
+		},
+		"priorities": {
+			"type": {"key": "string", "value": "integer", "min": 0, "max": "unlimited"}
+		}
+	}`)
+	data := tableTemplateData(t, "Logical_Switch", table, WithMapMerge())
+	code := formatTable(t, data)
+	assert.Contains(t, code, `// MergeExternalIDs sets the keys of kv in the ExternalIDs of the
+// LogicalSwitch, keeping its other keys
+func (a *LogicalSwitch) MergeExternalIDs(kv map[string]string) {
+	if len(kv) == 0 {
+		return
+	}
+	if a.ExternalIDs == nil {
+		a.ExternalIDs = make(map[string]string, len(kv))
+	}
+	for k, v := range kv {
+		a.ExternalIDs[k] = v
+	}
+}`)
+	assert.Contains(t, code, "func (a *LogicalSwitch) MergePriorities(kv map[string]int64) {")
+	// only maps are merged
+	assert.NotContains(t, code, "MergeName")
+	assert.NotContains(t, code, "MergePorts")
+	assert.Equal(t, []string{TableTypesPart, TableMethodsPart, TableHelpersPart}, data.Parts())
+}
+
+func TestExtendedGenMapMerge(t *testing.T) {
+	bridge := &vswitchd.Bridge{}
+	bridge.MergeExternalIDs(nil)
+	assert.Nil(t, bridge.ExternalIDs)
+	bridge.MergeExternalIDs(map[string]string{"owner": "ovnkube"})
+	assert.Equal(t, map[string]string{"owner": "ovnkube"}, bridge.ExternalIDs)
+
+	bridge = buildTestBridge()
+	bridge.ExternalIDs = map[string]string{"owner": "ovnkube", "zone": "a"}
+	kv := map[string]string{"zone": "b", "node": "n1"}
+	bridge.MergeExternalIDs(kv)
+	assert.Equal(t, map[string]string{"owner": "ovnkube", "zone": "b", "node": "n1"}, bridge.ExternalIDs)
+	// the merged keys are copied
+	kv["node"] = "n2"
+	assert.Equal(t, "n1", bridge.ExternalIDs["node"])
+}
+
+func TestNewTableTemplateOptionalGetters(t *testing.T) {
+	table := tableSchema(t, `{
+		"name": {
+			"type": "string"
+		},
+		"description": {
+			"type": {"key": "string", "min": 0, "max": 1}
+		},
+		"tag": {
+			"type": {"key": {"type": "integer", "minInteger": 1, "maxInteger": 4095}, "min": 0, "max": 1}
+		},
+		"ports": {
+			"type": {"key": "string", "min": 0, "max": "unlimited"}
+		}
+	}`)
+	data := tableTemplateData(t, "Logical_Switch", table, WithOptionalGetters())
+	code := formatTable(t, data)
+	assert.Contains(t, code, `// GetDescription returns the Description of the LogicalSwitch, if it is set
+func (a *LogicalSwitch) GetDescription() (string, bool) {
+	if a.Description == nil {
+		var zero string
+		return zero, false
+	}
+	return *a.Description, true
+}`)
+	assert.Contains(t, code, "func (a *LogicalSwitch) GetTag() (int64, bool) {")
+	// only the optional columns have getters
+	assert.NotContains(t, code, "GetName")
+	assert.NotContains(t, code, "GetPorts")
+	assert.Equal(t, []string{TableTypesPart, TableMethodsPart, TableHelpersPart}, data.Parts())
+}
+
+func TestExtendedGenOptionalGetters(t *testing.T) {
+	bridge := &vswitchd.Bridge{}
+	datapathID, ok := bridge.GetDatapathID()
+	assert.False(t, ok)
+	assert.Equal(t, "", datapathID)
+	failMode, ok := bridge.GetFailMode()
+	assert.False(t, ok)
+	assert.Equal(t, vswitchd.BridgeFailMode(""), failMode)
+
+	id := "0000000000000001"
+	mode := vswitchd.BridgeFailModeSecure
+	bridge.DatapathID = &id
+	bridge.FailMode = &mode
+	datapathID, ok = bridge.GetDatapathID()
+	assert.True(t, ok)
+	assert.Equal(t, id, datapathID)
+	failMode, ok = bridge.GetFailMode()
+	assert.True(t, ok)
+	assert.Equal(t, vswitchd.BridgeFailModeSecure, failMode)
+}
+
+func TestNewTableTemplateCardinalityValidation(t *testing.T) {
+	tables := map[string]*ovsdb.TableSchema{
+		"Logical_Switch": tableSchema(t, `{
+			"name": {
+				"type": "string"
+			},
+			"ports": {
+				"type": {"key": "string", "min": 1, "max": "unlimited"}
+			},
+			"tags": {
+				"type": {"key": "integer", "min": 0, "max": 2}
+			},
+			"labels": {
+				"type": {"key": "string", "value": "string", "min": 0, "max": 2}
+			},
+			"options": {
+				"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}
+			}
+		}`),
+		"Unbounded": tableSchema(t, `{
+			"ports": {
+				"type": {"key": "string", "min": 0, "max": "unlimited"}
+			}
+		}`),
+	}
+
+	for name, table := range tables {
+		data := tableTemplateData(t, name, table, WithCardinalityValidation())
+		assert.Equal(t, []string{TableTypesPart, TableMethodsPart, TableHelpersPart}, data.Parts())
+		src := formatTable(t, data)
+		code := strings.Join(strings.Fields(src), " ")
+		if name == "Logical_Switch" {
+			// a required set column errors when empty and passes with one element
+			assert.Contains(t, code, `if len(a.Ports) < 1 { return fmt.Errorf("column ports of Logical_Switch requires at least 1 elements, has %d", len(a.Ports)) }`)
+			assert.Contains(t, code, `if len(a.Labels) > 2 { return fmt.Errorf("column labels of Logical_Switch allows at most 2 elements, has %d", len(a.Labels)) }`)
+			// the sets with a maximum are arrays, always of the maximum length
+			assert.NotContains(t, code, "len(a.Tags)")
+			assert.NotContains(t, code, "len(a.Options)")
+			assert.NotContains(t, code, "len(a.Name)")
+		} else {
+			assert.Contains(t, code, "func (a *Unbounded) Validate() error { return nil }")
+			assert.NotContains(t, code, `"fmt"`)
+		}
+
+		// the code is valid
+		checkCode(t, src)
+	}
+}
+
+func TestExtendedGenCardinalityValidation(t *testing.T) {
+	// the columns of the example schema have no minimum, and their maximum is
+	// the length of their array
+	assert.NoError(t, (&vswitchd.Bridge{}).Validate())
+	assert.NoError(t, (&vswitchd.Interface{}).Validate())
+}
+
+func TestNewTableTemplateFromMap(t *testing.T) {
+	table := tableSchema(t, `{
+		"name": {
+			"type": "string"
+		},
+		"type": {
+			"type": {"key": {"type": "string", "enum": ["set", ["internal", "patch"]]}}
+		},
+		"protocol": {
+			"type": {"key": {"type": "string", "enum": ["set", ["tcp", "udp"]]}, "min": 0, "max": 1}
+		},
+		"modes": {
+			"type": {"key": {"type": "string", "enum": ["set", ["a", "b"]]}, "min": 0, "max": "unlimited"}
+		},
+		"pair": {
+			"type": {"key": {"type": "string", "enum": ["set", ["a", "b"]]}, "min": 0, "max": 2}
+		},
+		"ports": {
+			"type": {"key": "string", "min": 1, "max": "unlimited"}
+		},
+		"options": {
+			"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}
+		}
+	}`)
+
+	for _, opts := range [][]Option{
+		{WithFromMap()},
+		{WithFromMap(), WithoutEnumTypes()},
+		// fmt is imported once along with the other methods using it
+		{WithFromMap(), WithCardinalityValidation()},
+		{WithFromMap(), WithStringer(), WithCardinalityValidation()},
+	} {
+		data := tableTemplateData(t, "Logical_Switch", table, opts...)
+		src := formatTable(t, data)
+		code := strings.Join(strings.Fields(src), " ")
+		assert.Contains(t, code, "func NewLogicalSwitchFromMap(m map[string]interface{}) (*LogicalSwitch, error) {")
+		assert.Contains(t, code, `case "name": switch v := value.(type) { case string: a.Name = v default: return nil, fmt.Errorf("column name of Logical_Switch requires a value of type string, has %T", value) }`)
+		assert.Contains(t, code, `default: return nil, fmt.Errorf("unknown column %s of Logical_Switch", column)`)
+		// the enum types are aliases of their base type
+		assert.Contains(t, code, `case "type": switch v := value.(type) { case `)
+		assert.Contains(t, code, `case "pair": switch v := value.(type) { case [2]`)
+
+		// the code is valid
+		checkCode(t, src)
+	}
+}
+
+func TestExtendedGenFromMap(t *testing.T) {
+	failMode := "secure"
+	bridge, err := vswitchd.NewBridgeFromMap(map[string]interface{}{
+		"_uuid":        "uuid",
+		"name":         "br0",
+		"fail_mode":    &failMode,
+		"protocols":    []vswitchd.BridgeProtocols{vswitchd.BridgeProtocolsOpenflow13},
+		"external_ids": map[string]string{"foo": "bar"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &vswitchd.Bridge{
+		UUID:        "uuid",
+		Name:        "br0",
+		FailMode:    &vswitchd.BridgeFailModeSecure,
+		Protocols:   []vswitchd.BridgeProtocols{vswitchd.BridgeProtocolsOpenflow13},
+		ExternalIDs: map[string]string{"foo": "bar"},
+	}, bridge)
+
+	_, err = vswitchd.NewBridgeFromMap(map[string]interface{}{"name": 1})
+	assert.EqualError(t, err, "column name of Bridge requires a value of type string, has int")
+	_, err = vswitchd.NewBridgeFromMap(map[string]interface{}{"unknown": "br0"})
+	assert.EqualError(t, err, "unknown column unknown of Bridge")
+}
+
+func TestNewTableTemplateColumns(t *testing.T) {
+	table := tableSchema(t, `{
+		"name": {
+			"type": "string"
+		},
+		"ports": {
+			"type": {"key": "string", "min": 0, "max": "unlimited"}
+		}
+	}`)
+
+	// the names of the fields do not change the names of the columns
+	data := tableTemplateData(t, "Logical_Switch", table, WithColumns(),
+		WithFieldNameOverrides(map[string]map[string]string{"Logical_Switch": {"ports": "Members"}}))
+	src := formatTable(t, data)
+	code := strings.Join(strings.Fields(src), " ")
+	assert.Contains(t, code, `func (a *LogicalSwitch) Columns() []string { return []string{ "_uuid", "name", "ports", } }`)
+
+	// the code is valid
+	checkCode(t, src)
+
+	// the method is in the methods part
+	assert.Equal(t, []string{TableTypesPart, TableMethodsPart, TableHelpersPart}, data.Parts())
+	data.WithPart(TableMethodsPart)
+	assert.Contains(t, formatTable(t, data), "func (a *LogicalSwitch) Columns() []string {")
+}
+
+func TestExtendedGenColumns(t *testing.T) {
+	table := vswitchd.Schema().Tables["Bridge"]
+	expected := []string{"_uuid"}
+	for column := range table.Columns {
+		expected = append(expected, column)
+	}
+	columns := (&vswitchd.Bridge{}).Columns()
+	assert.ElementsMatch(t, expected, columns)
+	assert.Equal(t, "_uuid", columns[0])
+
+	// the callers can modify the slice
+	columns[0] = "name"
+	assert.Equal(t, "_uuid", (&vswitchd.Bridge{}).Columns()[0])
+}
+
+func TestNewTableTemplateReferenceValidation(t *testing.T) {
+	table := tableSchema(t, `{
+		"name": {
+			"type": "string"
+		},
+		"copp": {
+			"type": {"key": {"type": "uuid", "refTable": "Copp"}}
+		},
+		"load_balancer_group": {
+			"type": {"key": {"type": "uuid", "refTable": "Load_Balancer_Group", "refType": "weak"}, "min": 0, "max": 1}
+		},
+		"ports": {
+			"type": {"key": {"type": "uuid", "refTable": "Logical_Router_Port"}, "min": 0, "max": "unlimited"}
+		},
+		"pair": {
+			"type": {"key": {"type": "uuid", "refTable": "Logical_Router_Port"}, "min": 0, "max": 2}
+		},
+		"by_name": {
+			"type": {"key": "string", "value": {"type": "uuid", "refTable": "NAT"}, "min": 0, "max": "unlimited"}
+		},
+		"by_nat": {
+			"type": {"key": {"type": "uuid", "refTable": "NAT"}, "value": "string", "min": 0, "max": "unlimited"}
+		},
+		"unreferenced": {
+			"type": {"key": "uuid", "min": 0, "max": "unlimited"}
+		}
+	}`)
+
+	data := tableTemplateData(t, "Logical_Router", table, WithReferenceValidation())
+	src := formatTable(t, data)
+	code := strings.Join(strings.Fields(src), " ")
+	assert.Contains(t, code, "func (a *LogicalRouter) ValidateReferences(c *cache.TableCache) error {")
+	assert.Contains(t, code, `if err := c.CheckReference("Logical_Router", "copp", "Copp", a.Copp); err != nil { return err }`)
+	assert.Contains(t, code, `if a.LoadBalancerGroup != nil { if err := c.CheckReference("Logical_Router", "load_balancer_group", "Load_Balancer_Group", *a.LoadBalancerGroup); err != nil { return err } }`)
+	assert.Contains(t, code, `for _, uuid := range a.Ports { if err := c.CheckReference("Logical_Router", "ports", "Logical_Router_Port", uuid); err != nil { return err } }`)
+	assert.Contains(t, code, `for _, uuid := range a.Pair { if err := c.CheckReference("Logical_Router", "pair", "Logical_Router_Port", uuid); err != nil { return err } }`)
+	assert.Contains(t, code, `for _, value := range a.ByName { if err := c.CheckReference("Logical_Router", "by_name", "NAT", value); err != nil { return err } }`)
+	assert.Contains(t, code, `for key := range a.ByNat { if err := c.CheckReference("Logical_Router", "by_nat", "NAT", key); err != nil { return err } }`)
+	// the columns without a referenced table are not checked
+	assert.NotContains(t, code, `"name"`+", ")
+	assert.NotContains(t, code, "a.Unreferenced")
+
+	// the code is valid
+	checkCode(t, src)
+}
+
+func TestExtendedGenReferenceValidation(t *testing.T) {
+	clientDBModel, err := vswitchd.FullDatabaseModel()
+	require.NoError(t, err)
+	dbModel, errs := model.NewDatabaseModel(vswitchd.Schema(), clientDBModel)
+	require.Empty(t, errs)
+	tc, err := cache.NewTableCache(dbModel, nil, nil)
+	require.NoError(t, err)
+	port := uuid.NewString()
+	err = tc.Table(vswitchd.TablePort).Create(port, &vswitchd.Port{UUID: port, Name: "p0"}, false)
+	require.NoError(t, err)
+
+	// a port of the cache, and one inserted by the same transaction
+	bridge := &vswitchd.Bridge{Name: "br0", Ports: []string{port, "named"}}
+	assert.NoError(t, bridge.ValidateReferences(tc))
+
+	missing := uuid.NewString()
+	bridge.Ports = append(bridge.Ports, missing)
+	err = bridge.ValidateReferences(tc)
+	assert.Equal(t, &cache.ErrMissingReference{
+		Table:    vswitchd.TableBridge,
+		Column:   vswitchd.BridgeColumnPorts,
+		RefTable: vswitchd.TablePort,
+		UUID:     missing,
+	}, err)
+}
+
+func TestNewTableTemplateBuildTag(t *testing.T) {
+	table := tableSchema(t, `{
+		"name": {
+			"type": "string"
+		},
+		"external_ids": {
+			"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}
+		}
+	}`)
+	data := tableTemplateData(t, "Logical_Switch", table,
+		WithStringer(), WithMapMerge(), WithBuildTag("libovsdb_helpers", "stringer"))
+	assert.Equal(t, []string{TableTypesPart, TableMethodsPart, TableHelpersPart, TableTaggedPart}, data.Parts())
+
+	// the parts are valid with the tag
+	code := generateParts(t, data)
+	data.WithPart("")
+	code[""] = formatTable(t, data)
+
+	// the tagged file holds the build constraint and the selected methods
+	assert.Contains(t, code[TableTaggedPart], "//go:build libovsdb_helpers\n\npackage test")
+	assert.Contains(t, code[TableTaggedPart], "func (a *LogicalSwitch) String() string {")
+	assert.NotContains(t, code[TableTaggedPart], "MergeExternalIDs")
+	for _, part := range []string{TableTypesPart, TableMethodsPart, TableHelpersPart, ""} {
+		assert.NotContains(t, code[part], "//go:build")
+		assert.NotContains(t, code[part], "String()")
+	}
+	assert.Contains(t, code[TableMethodsPart], "func (a *LogicalSwitch) MergeExternalIDs(")
+	assert.Contains(t, code[""], "func (a *LogicalSwitch) MergeExternalIDs(")
+	// the imports of the moved methods are left out
+	assert.NotContains(t, code[TableMethodsPart], `"fmt"`)
+	assert.NotContains(t, code[""], `"fmt"`)
+
+	// the code is valid with and without the tag
+	checkCode(t, code[""])
+	checkCode(t, code[""], code[TableTaggedPart])
+	checkCode(t, code[TableTypesPart], code[TableMethodsPart], code[TableHelpersPart])
+}
+
+func TestNewTableTemplateFieldComments(t *testing.T) {
+	table := tableSchema(t, `{
+		"name": {
+			"type": "string",
+			"description": "The name of the switch."
+		},
+		"ports": {
+			"type": {"key": "string", "min": 0, "max": "unlimited"},
+			"description": "The ports of the switch.\n  One per line.\n"
+		},
+		"tag": {
+			"type": "integer"
+		}
+	}`)
+	for _, enumTypes := range []bool{true, false} {
+		data := tableTemplateData(t, "Logical_Switch", table)
+		data.WithEnumTypes(enumTypes)
+		code := formatTable(t, data)
+		assert.Contains(t, code, `type LogicalSwitch struct {
+	UUID string `+"`"+`ovsdb:"_uuid"`+"`"+`
+	// The name of the switch.
+	Name string `+"`"+`ovsdb:"name"`+"`"+`
+	// The ports of the switch.
+	// One per line.
+	Ports []string `+"`"+`ovsdb:"ports"`+"`"+`
+	Tag   int64    `+"`"+`ovsdb:"tag"`+"`"+`
+}`)
+	}
+}
+
+func TestNewTableTemplateBackquotes(t *testing.T) {
+	table := tableSchema(t, `{
+		"name": {
+			"type": "string",
+			"description": "The name, see `+"`"+`ovn-nbctl`+"`"+`."
+		},
+		"action": {
+			"type": {"key": {"type": "string",
+					 "enum": ["set", ["allow", "drop"]],
+					 "enumDoc": {"allow": "Like `+"`"+`allow-related`+"`"+`."}}}
+		}
+	}`)
+	// the code would not even be formatted if the backquotes ended the
+	// string literals embedding the schema
+	code := generateTable(t, "ACL", table, WithColumnSchema(), WithColumnTypes())
+	assert.Contains(t, code, "// The name, see `ovn-nbctl`.")
+	assert.Contains(t, code, `ACLActionAllow: "Like `+"`"+`allow-related`+"`"+`.",`)
+
+	schema := ovsdb.DatabaseSchema{Name: "AtomicDB", Tables: map[string]ovsdb.TableSchema{"ACL": *table}}
+	data, err := GetDBTemplateData("test", schema)
+	require.NoError(t, err)
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	b, err := g.Format(NewDBTemplate(), data)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `var schema = "{\n`)
+}
+
+func TestNewTableTemplateSchemaError(t *testing.T) {
+	table := ovsdb.TableSchema{Columns: map[string]*ovsdb.ColumnSchema{}}
+	data := tableTemplateData(t, "atomicTable", &table, WithColumnSchema())
+	// a map with boolean keys cannot be marshaled to JSON
+	data["TableSchema"] = map[bool]string{true: "foo"}
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	_, err = g.Format(NewTableTemplate(), data)
+	assert.Error(t, err)
+}
+
+func TestCustomType(t *testing.T) {
+	tests := []struct {
+		spec       string
+		typ        string
+		importPath string
+	}{
+		{"time.Time", "time.Time", "time"},
+		{"*time.Time", "*time.Time", "time"},
+		{"encoding/json.RawMessage", "json.RawMessage", "encoding/json"},
+		{"[]github.com/org/project/pkg.Value", "[]pkg.Value", "github.com/org/project/pkg"},
+		{"int32", "int32", ""},
+		{"Timestamp", "Timestamp", ""},
+	}
+	for _, tt := range tests {
+		typ, importPath := customType(tt.spec)
+		assert.Equal(t, tt.typ, typ, tt.spec)
+		assert.Equal(t, tt.importPath, importPath, tt.spec)
+	}
+}
+
+func TestNewTableTemplateCustomTypes(t *testing.T) {
+	table := tableSchema(t, `{
+		"name": {
+			"type": "string"
+		},
+		"created": {
+			"type": "string"
+		},
+		"expires": {
+			"type": {"key": "string", "min": 0, "max": 1}
+		},
+		"data": {
+			"type": "string"
+		}
+	}`)
+
+	mapping := WithCustomTypeMapping(map[string]map[string]string{
+		"atomicTable": {
+			"created": "time.Time",
+			"expires": "*time.Time",
+			"data":    "encoding/json.RawMessage",
+		},
+		"otherTable": {
+			"name": "int32",
+		},
+	})
+	data := tableTemplateData(t, "atomicTable", table, mapping)
+	assert.Equal(t, []string{"encoding/json", "time"}, data["CustomImports"])
+	src := formatTable(t, data)
+	code := strings.Join(strings.Fields(src), " ")
+	assert.Contains(t, code, "import ( \"encoding/json\" \"time\" )")
+	assert.Contains(t, code, "Created time.Time `ovsdb:\"created\"`")
+	assert.Contains(t, code, "Data json.RawMessage `ovsdb:\"data\"`")
+	assert.Contains(t, code, "Expires *time.Time `ovsdb:\"expires\"`")
+	// the other columns keep their type
+	assert.Contains(t, code, "Name string `ovsdb:\"name\"`")
+
+	// the code generated for comparable custom types compiles, the imports
+	// being removed from the parts not using them
+	mapping = WithCustomTypeMapping(map[string]map[string]string{
+		"atomicTable": {
+			"created": "time.Time",
+		},
+	})
+	data = tableTemplateData(t, "atomicTable", table, mapping, WithExtendedGen(), WithBuilder())
+	for part, src := range generateParts(t, data) {
+		switch part {
+		case TableHelpersPart:
+			assert.NotContains(t, src, "\"time\"")
+		default:
+			// the methods part holds the builder setting the field
+			assert.Contains(t, src, "\"time\"")
+		}
+	}
+}
+
+func TestNewTableTemplateFieldNameOverrides(t *testing.T) {
+	table := tableSchema(t, `{
+		"ip": {
+			"type": "string"
+		},
+		"mac": {
+			"type": {"key": "string", "min": 0, "max": 1}
+		}
+	}`)
+
+	overrides := WithFieldNameOverrides(map[string]map[string]string{
+		"atomicTable": {
+			"ip": "IPAddress",
+		},
+		"otherTable": {
+			"mac": "MACAddress",
+		},
+	})
+	data := tableTemplateData(t, "atomicTable", table, overrides)
+	src := formatTable(t, data)
+	code := strings.Join(strings.Fields(src), " ")
+	// the tag still holds the column name
+	assert.Contains(t, code, "IPAddress string `ovsdb:\"ip\"`")
+	assert.NotContains(t, code, "IP string")
+	// the other columns keep their name
+	assert.Contains(t, code, "MAC *string `ovsdb:\"mac\"`")
+
+	// the methods use the name of the field
+	data = tableTemplateData(t, "atomicTable", table, overrides,
+		WithExtendedGen(), WithBuilder(), WithFieldColumnMaps(), WithApplyPatch(), WithStringer(), WithOptionalGetters())
+	parts := generateParts(t, data)
+	assert.Contains(t, parts[TableTypesPart], `"IPAddress": "ip",`)
+}
+
+func TestNewTableTemplateApplyPatch(t *testing.T) {
+	table := tableSchema(t, `{
+		"ports": {
+			"type": {"key": "string", "min": 0, "max": "unlimited"}
+		},
+		"name": {
+			"type": {"key": "string", "min": 0, "max": 1}
+		}
+	}`)
+	data := tableTemplateData(t, "atomicTable", table)
+	data.WithExtendedGen(true)
+	data.WithApplyPatch(true)
+	code := formatTable(t, data)
+	// no zero value is needed to compare the fields
+	assert.Contains(t, code, `func (a *AtomicTable) ApplyPatch(patch *AtomicTable) {
+	if patch.Name != nil {
+		a.Name = copyAtomicTableName(patch.Name)
+	}
+	if patch.Ports != nil {
+		a.Ports = copyAtomicTablePorts(patch.Ports)
+	}
+}`)
+}
+
+func TestExtendedGenApplyPatch(t *testing.T) {
+	base := &vswitchd.Bridge{
+		UUID:        "base",
+		Name:        "br0",
+		FailMode:    &vswitchd.BridgeFailModeStandalone,
+		Ports:       []string{"a", "b"},
+		ExternalIDs: map[string]string{"foo": "bar"},
+	}
+	expected := base.DeepCopy()
+
+	// a patch setting a single optional field
+	patch := &vswitchd.Bridge{UUID: "patch", FailMode: &vswitchd.BridgeFailModeSecure}
+	base.ApplyPatch(patch)
+	expected.FailMode = &vswitchd.BridgeFailModeSecure
+	assert.Equal(t, expected, base)
+	// the field is copied
+	assert.NotSame(t, patch.FailMode, base.FailMode)
+
+	// fields holding their zero value are left untouched, others are set
+	base.ApplyPatch(&vswitchd.Bridge{Name: "br1", Ports: []string{}})
+	expected.Name = "br1"
+	expected.Ports = []string{}
+	assert.Equal(t, expected, base)
+}
+
+func TestExtendedGenCopyCommonFields(t *testing.T) {
+	bridge := &vswitchd.Bridge{
+		UUID:        "bridge",
+		Name:        "br0",
+		Ports:       []string{"a", "b"},
+		ExternalIDs: map[string]string{"foo": "bar"},
+		Status:      map[string]string{"state": "up"},
+	}
+	mtu := int64(1500)
+	iface := &vswitchd.Interface{
+		UUID:        "interface",
+		Type:        "internal",
+		MTU:         &mtu,
+		ExternalIDs: map[string]string{"baz": "quux"},
+	}
+	bridge.CopyCommonFields(iface)
+	assert.Equal(t, &vswitchd.Interface{
+		UUID:        "interface",
+		Name:        "br0",
+		Type:        "internal",
+		MTU:         &mtu,
+		ExternalIDs: map[string]string{"foo": "bar"},
+		Status:      map[string]string{"state": "up"},
+	}, iface)
+	// the fields are copied
+	iface.ExternalIDs["foo"] = "baz"
+	assert.Equal(t, "bar", bridge.ExternalIDs["foo"])
+
+	// fields mapped to columns of another type are left untouched
+	other := struct {
+		UUID        string            `ovsdb:"_uuid"`
+		Name        *string           `ovsdb:"name"`
+		Ports       []string          `ovsdb:"ports"`
+		ExternalIDs map[string]string `ovsdb:"other_config"`
+	}{}
+	bridge.CopyCommonFields(&other)
+	assert.Empty(t, other.UUID)
+	assert.Nil(t, other.Name)
+	assert.Equal(t, []string{"a", "b"}, other.Ports)
+	assert.Nil(t, other.ExternalIDs)
+
+	// anything but a pointer to a struct is ignored
+	bridge.CopyCommonFields(*iface)
+	bridge.CopyCommonFields(nil)
+}
+
+func TestNewTableTemplateStringer(t *testing.T) {
+	table := tableSchema(t, `{
+		"name": {
+			"type": {"key": "string", "min": 0, "max": 1}
+		},
+		"options": {
+			"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}
+		},
+		"tag": {
+			"type": "integer"
+		}
+	}`)
+	data := tableTemplateData(t, "atomicTable", table)
+	data.WithStringer(true)
+	code := formatTable(t, data)
+	assert.Contains(t, code, `import "fmt"`)
+	assert.Contains(t, code, `func (a *AtomicTable) String() string {
+	s := "AtomicTable{"
+	s += fmt.Sprintf("UUID: %q", a.UUID)
+	if a.Name == nil {
+		s += ", Name: nil"
+	} else {
+		s += fmt.Sprintf(", Name: %q", *a.Name)
+	}
+	s += fmt.Sprintf(", Options: %q", a.Options)
+	s += fmt.Sprintf(", Tag: %v", a.Tag)
+	return s + "}"
+}`)
+}
+
+func TestExtendedGenString(t *testing.T) {
+	externalIDs := map[string]string{}
+	for i := 0; i < 20; i++ {
+		externalIDs[fmt.Sprintf("key%02d", i)] = fmt.Sprintf("value%02d", i)
+	}
+	bridge := &vswitchd.Bridge{
+		UUID:        "uuid",
+		Name:        "br0",
+		FailMode:    &vswitchd.BridgeFailModeSecure,
+		ExternalIDs: externalIDs,
+	}
+	s := bridge.String()
+	for i := 0; i < 10; i++ {
+		// a copy holds its map entries in another order
+		assert.Equal(t, s, bridge.DeepCopy().String())
+	}
+	assert.Contains(t, s, `UUID: "uuid"`)
+	assert.Contains(t, s, `FailMode: "secure"`)
+	assert.Contains(t, s, `ExternalIDs: map["key00":"value00" "key01":"value01" "key02":"value02"`)
+	assert.Contains(t, s, `Controller: []`)
+}
+
+func TestExtendedGenInsertRowMinimal(t *testing.T) {
+	schema := vswitchd.Schema().Table("Bridge")
+	require.NotNil(t, schema)
+	bridge := &vswitchd.Bridge{
+		UUID:      uuid.NewString(),
+		Name:      "br0",
+		STPEnable: false,
+	}
+	row, err := bridge.InsertRowMinimal(schema)
+	require.NoError(t, err)
+	assert.Equal(t, ovsdb.Row{"name": "br0"}, row)
+
+	bridge.STPEnable = true
+	bridge.ExternalIDs = map[string]string{"foo": "bar"}
+	row, err = bridge.InsertRowMinimal(schema)
+	require.NoError(t, err)
+	assert.Equal(t, ovsdb.Row{
+		"name":         "br0",
+		"stp_enable":   true,
+		"external_ids": ovsdb.OvsMap{GoMap: map[interface{}]interface{}{"foo": "bar"}},
+	}, row)
+}
+
+func TestExtendedGenTextMarshaler(t *testing.T) {
+	bridge := &vswitchd.Bridge{
+		UUID: uuid.NewString(),
+		Name: "br0",
+	}
+	text, err := bridge.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, bridge.UUID, string(text))
+
+	var decoded vswitchd.Bridge
+	err = decoded.UnmarshalText(text)
+	require.NoError(t, err)
+	assert.Equal(t, vswitchd.Bridge{UUID: bridge.UUID}, decoded)
+
+	// models can be used as keys of text based encoders
+	b, err := json.Marshal(map[*vswitchd.Bridge]int{bridge: 1})
+	require.NoError(t, err)
+	assert.JSONEq(t, fmt.Sprintf(`{%q: 1}`, bridge.UUID), string(b))
+}
+
+func TestNewTableTemplateEnumExhaustiveness(t *testing.T) {
+	table := tableSchema(t, `{
+		"action": {
+			"type": {"key": {"type": "string",
+					 "enum": ["set", ["allow", "allow-related", "drop", "reject"]]}}
+		},
+		"severity": {
+			"type": {"key": {"type": "string",
+					 "enum": ["set", ["alert", "debug", "info"]]},
+				 "min": 0, "max": 1}
+		}
+	}`)
+	data := tableTemplateData(t, "ACL", table)
+	data.WithEnumExhaustiveness(true)
+	code := formatTable(t, data)
+
+	// collect the keys of the exhaustiveness maps by enum type
+	file, err := parser.ParseFile(token.NewFileSet(), "", code, 0)
+	require.NoError(t, err)
+	keys := map[string][]string{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		mapType, ok := lit.Type.(*ast.MapType)
+		if !ok {
+			return true
+		}
+		enum := mapType.Key.(*ast.Ident).Name
+		for _, elt := range lit.Elts {
+			keys[enum] = append(keys[enum], elt.(*ast.KeyValueExpr).Key.(*ast.Ident).Name)
+		}
+		return false
+	})
+
+	for column, alias := range map[string]string{"action": "ACLAction", "severity": "ACLSeverity"} {
+		var expected []string
+		for _, member := range table.Column(column).TypeObj.Key.Enum {
+			expected = append(expected, alias+FieldName(member.(string)))
+		}
+		require.NotEmpty(t, expected)
+		assert.ElementsMatch(t, expected, keys[alias], alias)
+	}
+}
+
+func TestNewTableTemplateEnumValidation(t *testing.T) {
+	table := tableSchema(t, `{
+		"action": {
+			"type": {"key": {"type": "string", "enum": ["set", ["allow", "drop"]]}}
+		},
+		"level": {
+			"type": {"key": {"type": "integer", "enum": ["set", [1, 2]]}, "min": 0, "max": 1}
+		}
+	}`)
+	data := tableTemplateData(t, "ACL", table, WithEnumValidation())
+	code := formatTable(t, data)
+	assert.Contains(t, code, `// ValidACLAction reports whether a value is a member of ACLAction
+func ValidACLAction(value ACLAction) bool {
+	switch value {
+	case ACLActionAllow, ACLActionDrop:
+		return true
+	}
+	return false
+}`)
+	assert.Contains(t, code, `func ValidACLLevel(value ACLLevel) bool {
+	switch value {
+	case ACLLevel1, ACLLevel2:
+		return true
+	}
+	return false
+}`)
+
+	data = tableTemplateData(t, "ACL", table)
+	code = formatTable(t, data)
+	assert.NotContains(t, code, "ValidACLAction")
+}
+
+func TestExtendedGenEnumValidation(t *testing.T) {
+	for _, member := range vswitchd.Schema().Tables["Bridge"].Column("fail_mode").TypeObj.Key.Enum {
+		assert.True(t, vswitchd.ValidBridgeFailMode(member.(string)), member)
+	}
+	for _, protocol := range []vswitchd.BridgeProtocols{
+		vswitchd.BridgeProtocolsOpenflow10,
+		vswitchd.BridgeProtocolsOpenflow11,
+		vswitchd.BridgeProtocolsOpenflow12,
+		vswitchd.BridgeProtocolsOpenflow13,
+		vswitchd.BridgeProtocolsOpenflow14,
+		vswitchd.BridgeProtocolsOpenflow15,
+	} {
+		assert.True(t, vswitchd.ValidBridgeProtocols(protocol), protocol)
+	}
+	assert.False(t, vswitchd.ValidBridgeFailMode("unknown"))
+	assert.False(t, vswitchd.ValidBridgeFailMode(""))
+	assert.False(t, vswitchd.ValidBridgeProtocols("OpenFlow16"))
+}
+
+func TestNewTableTemplateEnumStringer(t *testing.T) {
+	table := tableSchema(t, `{
+		"protocol": {
+			"type": {"key": {"type": "string",
+					 "enum": ["set", ["tcp", "udp"]]}}
+		},
+		"level": {
+			"type": {"key": {"type": "integer",
+					 "enum": ["set", [1, 2, 3]]},
+				 "min": 0, "max": 1}
+		}
+	}`)
+
+	// the enums are defined types, usable by the other templates
+	data := tableTemplateData(t, "atomicTable", table, WithEnumStringer(), WithExtendedGen(),
+		WithEnumValidation(), WithEnumPredicates(), WithEnumExhaustiveness(), WithFromMap(), WithStringer())
+	files := []string{}
+	for _, part := range data.Parts() {
+		data.WithPart(part)
+		files = append(files, formatTable(t, data))
+	}
+	pkg := checkCode(t, files...)
+	for _, enum := range []string{"AtomicTableProtocol", "AtomicTableLevel"} {
+		named, ok := pkg.Scope().Lookup(enum).Type().(*types.Named)
+		require.Truef(t, ok, "%s is not a defined type", enum)
+		method, _, _ := types.LookupFieldOrMethod(named, false, pkg, "String")
+		require.NotNil(t, method, enum)
+		assert.Equal(t, "func() string", method.Type().(*types.Signature).String(), enum)
+	}
+
+	// collect the results of the String methods
+	data.WithPart("")
+	file, err := parser.ParseFile(token.NewFileSet(), "", formatTable(t, data), 0)
+	require.NoError(t, err)
+	results := map[string][]string{}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != "String" || fn.Recv == nil {
+			continue
+		}
+		recv, ok := fn.Recv.List[0].Type.(*ast.Ident)
+		if !ok {
+			// the String method of the model
+			continue
+		}
+		enum := recv.Name
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if ret, ok := n.(*ast.ReturnStmt); ok {
+				results[enum] = append(results[enum], types.ExprString(ret.Results[0]))
+			}
+			return true
+		})
+	}
+	// the value of a string enum, known or not
+	assert.Equal(t, []string{"string(e)"}, results["AtomicTableProtocol"])
+	// the name of the members of an integer enum, and the value of the
+	// unknown ones
+	assert.Equal(t, []string{
+		`"AtomicTableLevel1"`,
+		`"AtomicTableLevel2"`,
+		`"AtomicTableLevel3"`,
+		"strconv.FormatInt(int64(e), 10)",
+	}, results["AtomicTableLevel"])
+
+	// aliases otherwise, without String methods
+	code := strings.Join(strings.Fields(generateTable(t, "atomicTable", table)), " ")
+	assert.Contains(t, code, "AtomicTableLevel = int")
+	assert.NotContains(t, code, "String() string")
+	assert.NotContains(t, code, "strconv")
+}
+
+func TestNewTablesTemplate(t *testing.T) {
+	rawSchema := []byte(`
+	{
+		"name": "AtomicDB",
+		"version": "0.0.0",
+		"tables": {
+			"atomicTable": {
+				"columns": {
+					"str": {
+						"type": "string"
+					},
+					"protocol": {
+						"type": {"key": {"type": "string",
+								 "enum": ["set", ["tcp", "udp", "sctp"]]},
+								 "min": 0, "max": 1}},
+					"external_ids": {
+						"type": {"key": "string", "value": "string",
+								 "min": 0, "max": "unlimited"}}
+				}
+			},
+			"otherTable": {
+				"columns": {
+					"name": {
+						"type": "string"
+					},
+					"level": {
+						"type": {"key": {"type": "string",
+								 "enum": ["set", ["low", "high"]]}}},
+					"atomic": {
+						"type": {"key": {"type": "uuid", "refTable": "atomicTable"},
+								 "min": 0, "max": "unlimited"}}
+				}
+			}
+		}
+	}`)
+	var schema ovsdb.DatabaseSchema
+	err := json.Unmarshal(rawSchema, &schema)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		imports int
+	}{
+		{
+			name:    "base",
+			imports: 0,
+		},
+		{
+			name:    "extended",
+			opts:    []Option{WithExtendedGen(), WithEnumValidation(), WithModelMethods(), WithMapMerge()},
+			imports: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewGenerator()
+			require.NoError(t, err)
+			data, err := GetTablesTemplateData("test", schema, tt.opts...)
+			require.NoError(t, err)
+			b, err := g.Format(NewTablesTemplate(), data)
+			require.NoError(t, err)
+
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "tables.go", b, parser.ParseComments)
+			require.NoError(t, err)
+			assert.Equal(t, 1, strings.Count(string(b), "// Code generated by \"libovsdb.modelgen\"\n"))
+			assert.Equal(t, 1, strings.Count(string(b), "\npackage test\n"))
+			imports := 0
+			for _, decl := range file.Decls {
+				if d, ok := decl.(*ast.GenDecl); ok && d.Tok == token.IMPORT {
+					imports++
+				}
+			}
+			assert.Equal(t, tt.imports, imports)
+
+			// the declarations of the file are the ones of each table
+			expected := []string{}
+			for _, tableName := range []string{"atomicTable", "otherTable"} {
+				table := schema.Tables[tableName]
+				tb := generateTable(t, tableName, &table, tt.opts...)
+				expected = append(expected, declarations(t, []byte(tb))...)
+			}
+			sort.Strings(expected)
+			decls := declarations(t, b)
+			sort.Strings(decls)
+			assert.Equal(t, expected, decls)
+
+			// the file compiles
+			checkCode(t, string(b))
+		})
+	}
+}
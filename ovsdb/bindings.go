@@ -1,7 +1,9 @@
 package ovsdb
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"reflect"
 )
 
@@ -58,6 +60,9 @@ func NativeTypeFromAtomic(basicType string) reflect.Type {
 // OVS uuid -> go strings
 // OVS map  -> go map
 // OVS enum -> go native type depending on the type of the enum key
+// The optional sets, with min 0 and max 1, are pointers: a nil pointer is the
+// empty set, and a pointer to any value, the zero value included, is the set
+// holding this value
 func NativeType(column *ColumnSchema) reflect.Type {
 	switch column.Type {
 	case TypeInteger, TypeReal, TypeBoolean, TypeUUID, TypeString:
@@ -159,6 +164,8 @@ func OvsToNative(column *ColumnSchema, ovsElem interface{}) (interface{}, error)
 				if len(ovsSet.GoSet) > 1 {
 					return nil, fmt.Errorf("expected a slice of len =< 1, but got a slice with %d elements", len(ovsSet.GoSet))
 				}
+				// an empty set is an unset optional value, i.e. a nil
+				// pointer, unlike a set holding the zero value
 				if len(ovsSet.GoSet) == 0 {
 					return reflect.Zero(naType).Interface(), nil
 				}
@@ -242,6 +249,29 @@ func NativeToOvsAtomic(basicType string, nativeElem interface{}) (interface{}, e
 	}
 }
 
+// ErrIntegerOutOfRange is returned when marshaling an integer that does not
+// fit the 64-bit signed integers of OVSDB
+var ErrIntegerOutOfRange = errors.New("integer out of range")
+
+// checkInteger returns an error wrapping ErrIntegerOutOfRange if value is an
+// unsigned integer exceeding the OVSDB integer range. Signed integers always
+// fit in it
+func checkInteger(value interface{}) error {
+	var u uint64
+	switch v := value.(type) {
+	case uint:
+		u = uint64(v)
+	case uint64:
+		u = v
+	default:
+		return nil
+	}
+	if u > math.MaxInt64 {
+		return fmt.Errorf("%w: %d exceeds the maximum OVSDB integer %d", ErrIntegerOutOfRange, u, int64(math.MaxInt64))
+	}
+	return nil
+}
+
 // NativeToOvs transforms an native type to a ovs type based on the column type information
 func NativeToOvs(column *ColumnSchema, rawElem interface{}) (interface{}, error) {
 	naType := NativeType(column)
@@ -3,6 +3,7 @@ package ovsdb
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
 	"testing"
 
@@ -610,6 +611,69 @@ func TestNativeToOvsErr(t *testing.T) {
 	}
 }
 
+func TestMarshalIntegerRange(t *testing.T) {
+	tooLarge := uint64(math.MaxInt64) + 1
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected string
+		err      bool
+	}{
+		{
+			name:     "max int64",
+			value:    OvsSet{GoSet: []interface{}{int64(math.MaxInt64)}},
+			expected: "9223372036854775807",
+		},
+		{
+			name:     "min int64",
+			value:    OvsSet{GoSet: []interface{}{int64(math.MinInt64)}},
+			expected: "-9223372036854775808",
+		},
+		{
+			name:     "max int64 as uint64",
+			value:    OvsSet{GoSet: []interface{}{uint64(math.MaxInt64)}},
+			expected: "9223372036854775807",
+		},
+		{
+			name:  "uint64 above max int64 in a set",
+			value: OvsSet{GoSet: []interface{}{tooLarge}},
+			err:   true,
+		},
+		{
+			name:  "uint64 above max int64 in a map",
+			value: OvsMap{GoMap: map[interface{}]interface{}{"key": tooLarge}},
+			err:   true,
+		},
+		{
+			name:  "uint64 above max int64 in a mutation",
+			value: NewMutation("tag", MutateOperationAdd, tooLarge),
+			err:   true,
+		},
+		{
+			name:  "uint64 above max int64 in a condition",
+			value: NewCondition("tag", ConditionEqual, tooLarge),
+			err:   true,
+		},
+		{
+			name:  "uint64 above max int64 in a row",
+			value: Operation{Op: OperationInsert, Table: "Bridge", Row: Row{"tag": tooLarge}},
+			err:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.value)
+			if tt.err {
+				assert.ErrorIs(t, err, ErrIntegerOutOfRange)
+				assert.Contains(t, err.Error(), fmt.Sprint(tooLarge))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(b))
+		})
+	}
+}
+
 func TestIsDefault(t *testing.T) {
 	type Test struct {
 		name     string
@@ -0,0 +1,99 @@
+package ovsdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// CanonicalJSON returns a deterministic JSON serialization of the operations,
+// so identical transactions always serialize identically. Object keys are
+// sorted, as well as the elements of sets and the pairs of maps, which are
+// unordered in OVSDB but whose Go representation is not. It is meant for
+// logging, hashing or comparing transactions: the result is valid OVSDB
+// notation, but the operations are not guaranteed to be sent this way
+func CanonicalJSON(ops []Operation) ([]byte, error) {
+	b, err := json.Marshal(ops)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	// keep numbers as they were encoded
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	value, err = canonicalize(value)
+	if err != nil {
+		return nil, err
+	}
+	// maps are encoded with their keys sorted
+	return json.Marshal(value)
+}
+
+// canonicalize sorts the elements of the sets and the pairs of the maps found
+// in a decoded JSON value
+func canonicalize(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, elem := range v {
+			c, err := canonicalize(elem)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = c
+		}
+		return v, nil
+	case []interface{}:
+		for i, elem := range v {
+			c, err := canonicalize(elem)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = c
+		}
+		if len(v) != 2 {
+			return v, nil
+		}
+		if kind, ok := v[0].(string); !ok || (kind != "set" && kind != "map") {
+			return v, nil
+		}
+		elems, ok := v[1].([]interface{})
+		if !ok {
+			return v, nil
+		}
+		// sort the set elements, or the map pairs, by their encoding
+		keys := make([][]byte, len(elems))
+		for i, elem := range elems {
+			key, err := json.Marshal(elem)
+			if err != nil {
+				return nil, err
+			}
+			keys[i] = key
+		}
+		sort.Sort(byEncoding{elems: elems, keys: keys})
+		return v, nil
+	default:
+		return v, nil
+	}
+}
+
+// byEncoding sorts elements by their JSON encoding
+type byEncoding struct {
+	elems []interface{}
+	keys  [][]byte
+}
+
+func (b byEncoding) Len() int {
+	return len(b.elems)
+}
+
+func (b byEncoding) Less(i, j int) bool {
+	return bytes.Compare(b.keys[i], b.keys[j]) < 0
+}
+
+func (b byEncoding) Swap(i, j int) {
+	b.elems[i], b.elems[j] = b.elems[j], b.elems[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+}
@@ -0,0 +1,67 @@
+package ovsdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	newOps := func(ports []interface{}, mirrors []UUID, externalIDs map[interface{}]interface{}) []Operation {
+		portSet, err := NewOvsSet(ports)
+		require.NoError(t, err)
+		mirrorSet, err := NewOvsSet(mirrors)
+		require.NoError(t, err)
+		return []Operation{
+			{
+				Op:    OperationInsert,
+				Table: "Bridge",
+				Row: Row{
+					"name":         "br0",
+					"ports":        portSet,
+					"external_ids": OvsMap{GoMap: externalIDs},
+					"mirrors":      mirrorSet,
+				},
+			},
+			{
+				Op:    OperationUpdate,
+				Table: "Bridge",
+				Row:   Row{"external_ids": OvsMap{GoMap: externalIDs}},
+				Where: []Condition{NewCondition("ports", ConditionIncludes, portSet)},
+			},
+		}
+	}
+
+	externalIDs := map[interface{}]interface{}{}
+	for _, k := range []string{"k1", "k2", "k3", "k4", "k5", "k6", "k7", "k8"} {
+		externalIDs[k] = "v" + k
+	}
+	otherExternalIDs := map[interface{}]interface{}{}
+	for _, k := range []string{"k8", "k7", "k6", "k5", "k4", "k3", "k2", "k1"} {
+		otherExternalIDs[k] = "v" + k
+	}
+
+	a, err := CanonicalJSON(newOps([]interface{}{"eth0", "eth1", "eth2"}, []UUID{{GoUUID: "m1"}, {GoUUID: "m2"}}, externalIDs))
+	require.NoError(t, err)
+	b, err := CanonicalJSON(newOps([]interface{}{"eth1", "eth2", "eth0"}, []UUID{{GoUUID: "m2"}, {GoUUID: "m1"}}, otherExternalIDs))
+	require.NoError(t, err)
+	assert.Equal(t, string(a), string(b))
+	assert.JSONEq(t, `[
+		{"op":"insert","table":"Bridge","row":{
+			"external_ids":["map",[["k1","vk1"],["k2","vk2"],["k3","vk3"],["k4","vk4"],["k5","vk5"],["k6","vk6"],["k7","vk7"],["k8","vk8"]]],
+			"mirrors":["set",[["named-uuid","m1"],["named-uuid","m2"]]],
+			"name":"br0",
+			"ports":["set",["eth0","eth1","eth2"]]}},
+		{"op":"update","table":"Bridge","row":{
+			"external_ids":["map",[["k1","vk1"],["k2","vk2"],["k3","vk3"],["k4","vk4"],["k5","vk5"],["k6","vk6"],["k7","vk7"],["k8","vk8"]]]},
+			"where":[["ports","includes",["set",["eth0","eth1","eth2"]]]]}
+	]`, string(a))
+
+	// the canonical form does not depend on the map iteration order
+	for i := 0; i < 10; i++ {
+		c, err := CanonicalJSON(newOps([]interface{}{"eth2", "eth0", "eth1"}, []UUID{{GoUUID: "m2"}, {GoUUID: "m1"}}, externalIDs))
+		require.NoError(t, err)
+		assert.Equal(t, string(a), string(c))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 )
 
 type ConditionFunction string
@@ -55,6 +56,9 @@ func NewCondition(column string, function ConditionFunction, value interface{})
 
 // MarshalJSON marshals a condition to a 3 element JSON array
 func (c Condition) MarshalJSON() ([]byte, error) {
+	if err := checkInteger(c.Value); err != nil {
+		return nil, err
+	}
 	v := []interface{}{c.Column, c.Function, c.Value}
 	return json.Marshal(v)
 }
@@ -92,6 +96,30 @@ func (c *Condition) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// OrderConditionsByIndex returns the conditions ordered to match the columns of an
+// index of the table, like one of TableSchema.Indexes, so that an equality
+// lookup over the index can be served from it. The equality conditions over
+// the index columns come first, in the order of the index, followed by the
+// other conditions. The conditions are otherwise kept in their original order
+func OrderConditionsByIndex(index []string, conditions []Condition) []Condition {
+	position := make(map[string]int, len(index))
+	for i, column := range index {
+		position[column] = i
+	}
+	rank := func(c Condition) int {
+		if i, ok := position[c.Column]; ok && c.Function == ConditionEqual {
+			return i
+		}
+		return len(index)
+	}
+	ordered := make([]Condition, len(conditions))
+	copy(ordered, conditions)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return rank(ordered[i]) < rank(ordered[j])
+	})
+	return ordered
+}
+
 // Evaluate will evaluate the condition on the two provided values
 // The conditions operately differently depending on the type of
 // the provided values. The behavior is as described in RFC7047
@@ -103,9 +131,9 @@ func (c ConditionFunction) Evaluate(a interface{}, b interface{}) (bool, error)
 	}
 	switch c {
 	case ConditionEqual:
-		return reflect.DeepEqual(a, b), nil
+		return equal(a, b), nil
 	case ConditionNotEqual:
-		return !reflect.DeepEqual(a, b), nil
+		return !equal(a, b), nil
 	case ConditionIncludes:
 		switch x.Kind() {
 		case reflect.Slice:
@@ -175,6 +203,22 @@ func (c ConditionFunction) Evaluate(a interface{}, b interface{}) (bool, error)
 	return false, fmt.Errorf("unreachable condition")
 }
 
+// equal compares two values for the ConditionEqual and ConditionNotEqual
+// functions. Sets and maps are equal if they hold the same elements, regardless
+// of their order or whether they are nil or empty
+func equal(a, b interface{}) bool {
+	x := reflect.ValueOf(a)
+	y := reflect.ValueOf(b)
+	switch x.Kind() {
+	case reflect.Slice:
+		return sliceContains(x, y) && sliceContains(y, x)
+	case reflect.Map:
+		return x.Len() == y.Len() && mapContains(x, y)
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
+
 func sliceContains(x, y reflect.Value) bool {
 	for i := 0; i < y.Len(); i++ {
 		found := false
@@ -575,6 +575,178 @@ func TestConditionFunctionEvaluate(t *testing.T) {
 	}
 }
 
+func TestConditionFunctionEvaluateSetEquality(t *testing.T) {
+	tests := []struct {
+		name  string
+		a     interface{}
+		b     interface{}
+		equal bool
+	}{
+		{
+			"same set",
+			[]string{"foo", "bar"},
+			[]string{"foo", "bar"},
+			true,
+		},
+		{
+			"same set different order",
+			[]string{"foo", "bar"},
+			[]string{"bar", "foo"},
+			true,
+		},
+		{
+			"subset",
+			[]string{"foo", "bar"},
+			[]string{"foo"},
+			false,
+		},
+		{
+			"superset",
+			[]int{1},
+			[]int{1, 2},
+			false,
+		},
+		{
+			"disjoint sets",
+			[]int{1, 2},
+			[]int{3, 4},
+			false,
+		},
+		{
+			"empty sets",
+			[]string{},
+			[]string{},
+			true,
+		},
+		{
+			"nil and empty sets",
+			[]string(nil),
+			[]string{},
+			true,
+		},
+		{
+			"empty and non empty sets",
+			[]string{},
+			[]string{"foo"},
+			false,
+		},
+		{
+			"interface sets different order",
+			[]interface{}{1, "bar"},
+			[]interface{}{"bar", 1},
+			true,
+		},
+		{
+			"same map",
+			map[string]string{"foo": "bar", "bar": "baz"},
+			map[string]string{"bar": "baz", "foo": "bar"},
+			true,
+		},
+		{
+			"map with different value",
+			map[string]string{"foo": "bar"},
+			map[string]string{"foo": "baz"},
+			false,
+		},
+		{
+			"map subset",
+			map[string]string{"foo": "bar", "bar": "baz"},
+			map[string]string{"foo": "bar"},
+			false,
+		},
+		{
+			"map superset",
+			map[string]string{"foo": "bar"},
+			map[string]string{"foo": "bar", "bar": "baz"},
+			false,
+		},
+		{
+			"empty maps",
+			map[string]string{},
+			map[string]string{},
+			true,
+		},
+		{
+			"nil and empty maps",
+			map[string]string(nil),
+			map[string]string{},
+			true,
+		},
+		{
+			"empty and non empty maps",
+			map[string]string{},
+			map[string]string{"foo": "bar"},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eq, err := ConditionEqual.Evaluate(tt.a, tt.b)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.equal, eq)
+			ne, err := ConditionNotEqual.Evaluate(tt.a, tt.b)
+			assert.Nil(t, err)
+			assert.Equal(t, !eq, ne)
+			// both functions are symmetric
+			eq, err = ConditionEqual.Evaluate(tt.b, tt.a)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.equal, eq)
+			ne, err = ConditionNotEqual.Evaluate(tt.b, tt.a)
+			assert.Nil(t, err)
+			assert.Equal(t, !eq, ne)
+		})
+	}
+}
+
+func TestOrderConditionsByIndex(t *testing.T) {
+	index := []string{"name", "type"}
+	name := NewCondition("name", ConditionEqual, "lsp0")
+	typ := NewCondition("type", ConditionEqual, "router")
+	up := NewCondition("up", ConditionEqual, true)
+	notName := NewCondition("name", ConditionNotEqual, "lsp1")
+	tag := NewCondition("tag", ConditionGreaterThan, 10)
+
+	tests := []struct {
+		name       string
+		conditions []Condition
+		expected   []Condition
+	}{
+		{
+			name:       "reversed index columns",
+			conditions: []Condition{typ, name},
+			expected:   []Condition{name, typ},
+		},
+		{
+			name:       "ordered index columns",
+			conditions: []Condition{name, typ},
+			expected:   []Condition{name, typ},
+		},
+		{
+			name:       "other conditions last in their order",
+			conditions: []Condition{tag, typ, up, notName, name},
+			expected:   []Condition{name, typ, tag, up, notName},
+		},
+		{
+			name:       "no index column",
+			conditions: []Condition{tag, up},
+			expected:   []Condition{tag, up},
+		},
+		{
+			name:     "no conditions",
+			expected: []Condition{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditions := append([]Condition{}, tt.conditions...)
+			ordered := OrderConditionsByIndex(index, tt.conditions)
+			assert.Equal(t, tt.expected, ordered)
+			// the conditions given are not modified
+			assert.Equal(t, conditions, append([]Condition{}, tt.conditions...))
+		})
+	}
+}
+
 func TestSliceContains(t *testing.T) {
 	tests := []struct {
 		name string
@@ -0,0 +1,68 @@
+package ovsdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrDuplicateKey is returned when a JSON object holds the same key more than
+// once
+var ErrDuplicateKey = errors.New("duplicate key in JSON object")
+
+// CheckDuplicateKeys returns an error wrapping ErrDuplicateKey if any JSON
+// object in data, at any depth, holds the same key more than once. The standard
+// decoder silently keeps the last value of such a key, which makes a malformed
+// message from a buggy or malicious peer go unnoticed
+func CheckDuplicateKeys(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := checkDuplicateKeys(decoder, "$"); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err == nil {
+		return fmt.Errorf("invalid JSON: unexpected data after the top-level value")
+	}
+	return nil
+}
+
+// checkDuplicateKeys checks the next JSON value read from the decoder. The
+// path locates the value in the document for the error messages
+func checkDuplicateKeys(decoder *json.Decoder, path string) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return nil
+	}
+	switch delim {
+	case '{':
+		keys := make(map[string]bool)
+		for decoder.More() {
+			token, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			key := token.(string)
+			if keys[key] {
+				return fmt.Errorf("%w: %q in %s", ErrDuplicateKey, key, path)
+			}
+			keys[key] = true
+			if err := checkDuplicateKeys(decoder, path+"."+key); err != nil {
+				return err
+			}
+		}
+	case '[':
+		for i := 0; decoder.More(); i++ {
+			if err := checkDuplicateKeys(decoder, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	// consume the closing delimiter
+	_, err = decoder.Token()
+	return err
+}
@@ -0,0 +1,59 @@
+package ovsdb
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckDuplicateKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			"no duplicates",
+			`[{"uuid":["uuid","2f77b348-9768-4866-b761-89d5177ecdab"]},{"count":1},{"rows":[{"name":"foo"},{"name":"bar"}]}]`,
+			"",
+		},
+		{
+			"same key in different objects",
+			`[{"count":1},{"count":2}]`,
+			"",
+		},
+		{
+			"duplicate top level key",
+			`[{"count":1,"error":"","count":2}]`,
+			`duplicate key in JSON object: "count" in $[0]`,
+		},
+		{
+			"duplicate nested key",
+			`[{},{"rows":[{"name":"foo","name":"bar"}]}]`,
+			`duplicate key in JSON object: "name" in $[1].rows[0]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckDuplicateKeys([]byte(tt.data))
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+
+	err := CheckDuplicateKeys([]byte(`[{"count":1}`))
+	assert.Error(t, err)
+
+	// the standard decoder keeps the last value
+	data := []byte(`[{"count":1,"count":2}]`)
+	var results []OperationResult
+	err = json.Unmarshal(data, &results)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, results[0].Count)
+	assert.True(t, errors.Is(CheckDuplicateKeys(data), ErrDuplicateKey))
+}
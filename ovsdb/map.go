@@ -23,6 +23,12 @@ func (o OvsMap) MarshalJSON() ([]byte, error) {
 		var ovsMap, innerMap []interface{}
 		ovsMap = append(ovsMap, "map")
 		for key, val := range o.GoMap {
+			if err := checkInteger(key); err != nil {
+				return nil, err
+			}
+			if err := checkInteger(val); err != nil {
+				return nil, err
+			}
 			var mapSeg []interface{}
 			mapSeg = append(mapSeg, key)
 			mapSeg = append(mapSeg, val)
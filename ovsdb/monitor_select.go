@@ -3,6 +3,9 @@ package ovsdb
 import "encoding/json"
 
 // MonitorSelect represents a monitor select according to RFC7047
+// The members that are not set default to true, as they do on the server when
+// they are omitted. The zero MonitorSelect thus selects everything, like a
+// MonitorRequest without a select
 type MonitorSelect struct {
 	initial *bool
 	insert  *bool
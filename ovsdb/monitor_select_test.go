@@ -101,3 +101,20 @@ func TestMonitorSelectMarshalUnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestMonitorSelectOmitted(t *testing.T) {
+	var ms MonitorSelect
+	assert.True(t, ms.Initial(), "initial")
+	assert.True(t, ms.Insert(), "insert")
+	assert.True(t, ms.Delete(), "delete")
+	assert.True(t, ms.Modify(), "modify")
+
+	b, err := json.Marshal(MonitorRequest{Columns: []string{"name"}})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"columns":["name"]}`, string(b))
+
+	var mr MonitorRequest
+	err = json.Unmarshal(b, &mr)
+	assert.NoError(t, err)
+	assert.Nil(t, mr.Select)
+}
@@ -42,6 +42,9 @@ func NewMutation(column string, mutator Mutator, value interface{}) *Mutation {
 
 // MarshalJSON marshals a mutation to a 3 element JSON array
 func (m Mutation) MarshalJSON() ([]byte, error) {
+	if err := checkInteger(m.Value); err != nil {
+		return nil, err
+	}
 	v := []interface{}{m.Column, m.Mutator, m.Value}
 	return json.Marshal(v)
 }
@@ -1,7 +1,9 @@
 package ovsdb
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 )
 
 const (
@@ -49,6 +51,13 @@ type Operation struct {
 // to allow selecting all rows of a table
 func (o Operation) MarshalJSON() ([]byte, error) {
 	type OpAlias Operation
+	for _, row := range append([]Row{o.Row}, o.Rows...) {
+		for column, value := range row {
+			if err := checkInteger(value); err != nil {
+				return nil, fmt.Errorf("column %s: %w", column, err)
+			}
+		}
+	}
 	switch o.Op {
 	case "select":
 		where := o.Where
@@ -62,6 +71,24 @@ func (o Operation) MarshalJSON() ([]byte, error) {
 			Where:   where,
 			OpAlias: (OpAlias)(o),
 		})
+	case "wait":
+		// the where and rows of a wait are required, even if empty
+		where, rows := o.Where, o.Rows
+		if where == nil {
+			where = make([]Condition, 0)
+		}
+		if rows == nil {
+			rows = make([]Row, 0)
+		}
+		return json.Marshal(&struct {
+			Where []Condition `json:"where"`
+			Rows  []Row       `json:"rows"`
+			OpAlias
+		}{
+			Where:   where,
+			Rows:    rows,
+			OpAlias: (OpAlias)(o),
+		})
 	default:
 		return json.Marshal(&struct {
 			OpAlias
@@ -94,11 +121,72 @@ type TransactResponse struct {
 
 // OperationResult is the result of an Operation
 type OperationResult struct {
-	Count   int    `json:"count,omitempty"`
-	Error   string `json:"error,omitempty"`
+	Count int    `json:"count,omitempty"`
+	Error string `json:"error,omitempty"`
+	// Details is the text of the details of an error. Details that are not
+	// a JSON string are held in their JSON encoding
 	Details string `json:"details,omitempty"`
-	UUID    UUID   `json:"uuid,omitempty"`
-	Rows    []Row  `json:"rows,omitempty"`
+	// StructuredDetails holds the details of an error as decoded from JSON,
+	// so that structured details, such as the row causing the error, can be
+	// inspected
+	StructuredDetails interface{} `json:"-"`
+	// UUID is the UUID assigned by the server to the row created by an
+	// insert operation. It is empty for other operations
+	UUID UUID  `json:"uuid,omitempty"`
+	Rows []Row `json:"rows,omitempty"`
+}
+
+// MarshalJSON marshals an OperationResult. The uuid member is only present
+// in the result of an insert operation
+func (r OperationResult) MarshalJSON() ([]byte, error) {
+	type result OperationResult
+	var uuid *UUID
+	if r.UUID.GoUUID != "" {
+		uuid = &r.UUID
+	}
+	var details interface{}
+	if r.StructuredDetails != nil {
+		details = r.StructuredDetails
+	} else if r.Details != "" {
+		details = r.Details
+	}
+	return json.Marshal(struct {
+		result
+		UUID    *UUID       `json:"uuid,omitempty"`
+		Details interface{} `json:"details,omitempty"`
+	}{result(r), uuid, details})
+}
+
+// UnmarshalJSON unmarshals an OperationResult, keeping the details of an
+// error both as text and as structured data
+func (r *OperationResult) UnmarshalJSON(b []byte) error {
+	type result OperationResult
+	var res struct {
+		result
+		Details json.RawMessage `json:"details,omitempty"`
+	}
+	if err := json.Unmarshal(b, &res); err != nil {
+		return err
+	}
+	*r = OperationResult(res.result)
+	if len(res.Details) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(res.Details, &r.StructuredDetails); err != nil {
+		return err
+	}
+	switch details := r.StructuredDetails.(type) {
+	case nil:
+	case string:
+		r.Details = details
+	default:
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, res.Details); err != nil {
+			return err
+		}
+		r.Details = compact.String()
+	}
+	return nil
 }
 
 func ovsSliceToGoNotation(val interface{}) (interface{}, error) {
@@ -39,6 +39,20 @@ func TestOpRowSerialization(t *testing.T) {
 	}
 }
 
+func TestOpWaitSerialization(t *testing.T) {
+	timeout := 0
+	operation := Operation{
+		Op:      "wait",
+		Table:   "Bridge",
+		Timeout: &timeout,
+		Until:   "==",
+	}
+	str, err := json.Marshal(operation)
+	require.NoError(t, err)
+	// the where and rows of a wait are required, even if empty
+	assert.JSONEq(t, `{"op":"wait","table":"Bridge","timeout":0,"until":"==","where":[],"rows":[]}`, string(str))
+}
+
 func TestOpRowsSerialization(t *testing.T) {
 	operation := Operation{
 		Op:    "insert",
@@ -270,3 +284,49 @@ func TestOvsSliceToGoNotation(t *testing.T) {
 		})
 	}
 }
+
+func TestOperationResultUUID(t *testing.T) {
+	var results []OperationResult
+	err := json.Unmarshal([]byte(`[{"uuid":["uuid","`+aUUID0+`"]},{"count":1}]`), &results)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, UUID{GoUUID: aUUID0}, results[0].UUID)
+	assert.Equal(t, UUID{}, results[1].UUID)
+
+	// the uuid is only present in the result of an insert
+	b, err := json.Marshal(results)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"uuid":["uuid","`+aUUID0+`"]},{"count":1}]`, string(b))
+}
+
+func TestOperationResultDetails(t *testing.T) {
+	var results []OperationResult
+	err := json.Unmarshal([]byte(`[
+		{"error":"constraint violation","details":{"constraint":"name","row":{"name":"ls0","ports":["set",[]]}}},
+		{"error":"domain error","details":"not a valid value"}
+	]`), &results)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	// structured details are available both as text and as data
+	assert.JSONEq(t, `{"constraint":"name","row":{"name":"ls0","ports":["set",[]]}}`, results[0].Details)
+	details, ok := results[0].StructuredDetails.(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "name", details["constraint"])
+	assert.Equal(t, map[string]interface{}{"name": "ls0", "ports": []interface{}{"set", []interface{}{}}}, details["row"])
+
+	assert.Equal(t, "not a valid value", results[1].Details)
+	assert.Equal(t, "not a valid value", results[1].StructuredDetails)
+
+	// the details are marshaled as they were received
+	b, err := json.Marshal(results)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"error":"constraint violation","details":{"constraint":"name","row":{"name":"ls0","ports":["set",[]]}}},
+		{"error":"domain error","details":"not a valid value"}
+	]`, string(b))
+
+	b, err = json.Marshal(OperationResult{Error: "domain error", Details: "text"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"error":"domain error","details":"text"}`, string(b))
+}
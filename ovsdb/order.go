@@ -0,0 +1,69 @@
+package ovsdb
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNamedUUIDOrder is returned when an operation references a named-uuid that
+// is not introduced by an earlier insert of the transaction
+var ErrNamedUUIDOrder = errors.New("named-uuid referenced before its insert")
+
+// ValidateOperationOrder checks that the operations of a transaction are
+// ordered so that every named-uuid they reference, in their rows, mutations or
+// conditions, is introduced by the uuid-name of a prior insert, or of the
+// insert referencing it. It returns an error wrapping ErrNamedUUIDOrder for
+// the first operation that does not, and an error if two inserts introduce
+// the same named-uuid
+func ValidateOperationOrder(ops []Operation) error {
+	named := make(map[string]bool)
+	for i, op := range ops {
+		if op.Op == OperationInsert && op.UUIDName != "" {
+			if named[op.UUIDName] {
+				return fmt.Errorf("operation %d (%s on %s): duplicate uuid-name %s", i, op.Op, op.Table, op.UUIDName)
+			}
+			named[op.UUIDName] = true
+		}
+		var refs []string
+		for _, value := range op.Row {
+			refs = namedUUIDs(refs, value)
+		}
+		for _, row := range op.Rows {
+			for _, value := range row {
+				refs = namedUUIDs(refs, value)
+			}
+		}
+		for _, mutation := range op.Mutations {
+			refs = namedUUIDs(refs, mutation.Value)
+		}
+		for _, condition := range op.Where {
+			refs = namedUUIDs(refs, condition.Value)
+		}
+		for _, ref := range refs {
+			if !named[ref] {
+				return fmt.Errorf("%w: operation %d (%s on %s) references %s", ErrNamedUUIDOrder, i, op.Op, op.Table, ref)
+			}
+		}
+	}
+	return nil
+}
+
+// namedUUIDs appends the named-uuids held by an OVSDB value to refs
+func namedUUIDs(refs []string, value interface{}) []string {
+	switch v := value.(type) {
+	case UUID:
+		if isNamed(v.GoUUID) {
+			refs = append(refs, v.GoUUID)
+		}
+	case OvsSet:
+		for _, elem := range v.GoSet {
+			refs = namedUUIDs(refs, elem)
+		}
+	case OvsMap:
+		for key, elem := range v.GoMap {
+			refs = namedUUIDs(refs, key)
+			refs = namedUUIDs(refs, elem)
+		}
+	}
+	return refs
+}
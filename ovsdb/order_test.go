@@ -0,0 +1,91 @@
+package ovsdb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateOperationOrder(t *testing.T) {
+	insertPort := Operation{
+		Op:       OperationInsert,
+		Table:    "Logical_Switch_Port",
+		UUIDName: "lsp0",
+		Row:      Row{"name": "lsp0"},
+	}
+	insertSwitch := Operation{
+		Op:       OperationInsert,
+		Table:    "Logical_Switch",
+		UUIDName: "ls0",
+		Row: Row{
+			"name":  "ls0",
+			"ports": OvsSet{GoSet: []interface{}{UUID{GoUUID: "lsp0"}, UUID{GoUUID: aUUID0}}},
+		},
+	}
+	mutateRouter := Operation{
+		Op:    OperationMutate,
+		Table: "Logical_Router",
+		Where: []Condition{NewCondition("_uuid", ConditionEqual, UUID{GoUUID: aUUID1})},
+		Mutations: []Mutation{
+			*NewMutation("external_ids", MutateOperationInsert, OvsMap{GoMap: map[interface{}]interface{}{"switch": UUID{GoUUID: "ls0"}}}),
+		},
+	}
+
+	tests := []struct {
+		name string
+		ops  []Operation
+		err  error
+	}{
+		{
+			name: "ordered",
+			ops:  []Operation{insertPort, insertSwitch, mutateRouter},
+		},
+		{
+			name: "forward reference in a row",
+			ops:  []Operation{insertSwitch, insertPort, mutateRouter},
+			err:  ErrNamedUUIDOrder,
+		},
+		{
+			name: "forward reference in a mutation",
+			ops:  []Operation{insertPort, mutateRouter, insertSwitch},
+			err:  ErrNamedUUIDOrder,
+		},
+		{
+			name: "reference in a condition without insert",
+			ops: []Operation{{
+				Op:    OperationDelete,
+				Table: "Logical_Switch",
+				Where: []Condition{NewCondition("_uuid", ConditionEqual, UUID{GoUUID: "ls0"})},
+			}},
+			err: ErrNamedUUIDOrder,
+		},
+		{
+			name: "self reference",
+			ops: []Operation{{
+				Op:       OperationInsert,
+				Table:    "Logical_Switch",
+				UUIDName: "ls0",
+				Row:      Row{"parent": UUID{GoUUID: "ls0"}},
+			}},
+		},
+		{
+			name: "duplicate uuid-name",
+			ops:  []Operation{insertPort, insertPort},
+			err:  errors.New("operation 1 (insert on Logical_Switch_Port): duplicate uuid-name lsp0"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOperationOrder(tt.ops)
+			switch {
+			case tt.err == nil:
+				assert.NoError(t, err)
+			case errors.Is(tt.err, ErrNamedUUIDOrder):
+				assert.ErrorIs(t, err, ErrNamedUUIDOrder)
+			default:
+				assert.EqualError(t, err, tt.err.Error())
+			}
+		})
+	}
+}
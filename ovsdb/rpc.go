@@ -58,6 +58,11 @@ func NewLockArgs(id interface{}) []interface{} {
 	return []interface{}{id}
 }
 
+// LockResult is the result of a lock or steal RPC
+type LockResult struct {
+	Locked bool `json:"locked"`
+}
+
 // NotificationHandler is the interface that must be implemented to receive notifications
 type NotificationHandler interface {
 	// RFC 7047 section 4.1.6 Update Notification
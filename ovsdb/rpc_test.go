@@ -21,7 +21,7 @@ func TestNewWaitTransactArgs(t *testing.T) {
 	operation := Operation{Op: "wait", Table: "Bridge", Timeout: &i}
 	args := NewTransactArgs(database, operation)
 	argString, _ := json.Marshal(args)
-	expected := `["Open_vSwitch",{"op":"wait","table":"Bridge","timeout":0}]`
+	expected := `["Open_vSwitch",{"where":[],"rows":[],"op":"wait","table":"Bridge","timeout":0}]`
 	if string(argString) != expected {
 		t.Error("Expected: ", expected, " Got: ", string(argString))
 	}
@@ -50,6 +50,29 @@ func TestNewMultipleTransactArgs(t *testing.T) {
 	}
 }
 
+func TestNewTransactArgsDatabaseFirst(t *testing.T) {
+	database := "OVN_Northbound"
+	operation := Operation{Op: "select", Table: "Logical_Switch"}
+	argString, err := json.Marshal(NewTransactArgs(database, operation))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var params []json.RawMessage
+	if err := json.Unmarshal(argString, &params); err != nil {
+		t.Fatal(err)
+	}
+	if len(params) != 2 {
+		t.Fatal("Expected 2 params, got: ", len(params))
+	}
+	var first string
+	if err := json.Unmarshal(params[0], &first); err != nil {
+		t.Fatal("Expected the database name as the first param: ", err)
+	}
+	if first != database {
+		t.Error("Expected: ", database, " Got: ", first)
+	}
+}
+
 func TestNewCancelArgs(t *testing.T) {
 	id := 1
 	args := NewCancelArgs(id)
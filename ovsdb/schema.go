@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"math"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -22,6 +23,12 @@ var UUIDColumn = ColumnSchema{
 	Type: TypeUUID,
 }
 
+// VersionColumn is a static column that represents the _version column, common
+// to all tables, whose value changes every time the row is modified
+var VersionColumn = ColumnSchema{
+	Type: TypeUUID,
+}
+
 // Table returns a TableSchema Schema for a given table and column name
 func (schema DatabaseSchema) Table(tableName string) *TableSchema {
 	if table, ok := schema.Tables[tableName]; ok {
@@ -103,7 +110,10 @@ func (schema DatabaseSchema) ValidateOperations(operations ...Operation) bool {
 // TableSchema is a table schema according to RFC7047
 type TableSchema struct {
 	Columns map[string]*ColumnSchema `json:"columns"`
-	Indexes [][]string               `json:"indexes,omitempty"`
+	// Indexes holds the indexes of the table, each one the names of its
+	// columns, e.g. [["name"], ["logical_port", "ip"]] for an index on the name
+	// column and a composite index on the logical_port and ip columns
+	Indexes [][]string `json:"indexes,omitempty"`
 }
 
 // Column returns the Column object for a specific column name
@@ -111,6 +121,9 @@ func (t TableSchema) Column(columnName string) *ColumnSchema {
 	if columnName == "_uuid" {
 		return &UUIDColumn
 	}
+	if columnName == "_version" {
+		return &VersionColumn
+	}
 	if column, ok := t.Columns[columnName]; ok {
 		return column
 	}
@@ -168,8 +181,12 @@ const (
 
 // BaseType is a base-type structure as per RFC7047
 type BaseType struct {
-	Type       string
-	Enum       []interface{}
+	Type string
+	Enum []interface{}
+	// EnumDoc documents the members of Enum, keyed by their string
+	// representation. It is not part of RFC7047 and is only set if the schema
+	// provides the optional "enumDoc" object
+	EnumDoc    map[string]string
 	minReal    *float64
 	maxReal    *float64
 	minInteger *int
@@ -297,16 +314,17 @@ func (b *BaseType) UnmarshalJSON(data []byte) error {
 	}
 	// temporary type to avoid recursive call to unmarshal
 	var bt struct {
-		Type       string      `json:"type"`
-		Enum       interface{} `json:"enum,omitempty"`
-		MinReal    *float64    `json:"minReal,omitempty"`
-		MaxReal    *float64    `json:"maxReal,omitempty"`
-		MinInteger *int        `json:"minInteger,omitempty"`
-		MaxInteger *int        `json:"maxInteger,omitempty"`
-		MinLength  *int        `json:"minLength,omitempty"`
-		MaxLength  *int        `json:"maxLength,omitempty"`
-		RefTable   *string     `json:"refTable,omitempty"`
-		RefType    *RefType    `json:"refType,omitempty"`
+		Type       string            `json:"type"`
+		Enum       interface{}       `json:"enum,omitempty"`
+		EnumDoc    map[string]string `json:"enumDoc,omitempty"`
+		MinReal    *float64          `json:"minReal,omitempty"`
+		MaxReal    *float64          `json:"maxReal,omitempty"`
+		MinInteger *schemaInt        `json:"minInteger,omitempty"`
+		MaxInteger *schemaInt        `json:"maxInteger,omitempty"`
+		MinLength  *schemaInt        `json:"minLength,omitempty"`
+		MaxLength  *schemaInt        `json:"maxLength,omitempty"`
+		RefTable   *string           `json:"refTable,omitempty"`
+		RefType    *RefType          `json:"refType,omitempty"`
 	}
 	err := json.Unmarshal(data, &bt)
 	if err != nil {
@@ -327,39 +345,82 @@ func (b *BaseType) UnmarshalJSON(data []byte) error {
 		default:
 			b.Enum = []interface{}{bt.Enum}
 		}
+		// JSON numbers are decoded as float64. OVSDB integers are 64-bit,
+		// like the enum constants generated by modelgen
+		if bt.Type == TypeInteger {
+			for k, val := range b.Enum {
+				if f, ok := val.(float64); ok {
+					b.Enum[k] = int64(f)
+				}
+			}
+		}
 	}
 	b.Type = bt.Type
+	b.EnumDoc = bt.EnumDoc
 	b.minReal = bt.MinReal
 	b.maxReal = bt.MaxReal
-	b.minInteger = bt.MinInteger
-	b.maxInteger = bt.MaxInteger
-	b.minLength = bt.MaxLength
-	b.maxLength = bt.MaxLength
+	b.minInteger = bt.MinInteger.intPtr()
+	b.maxInteger = bt.MaxInteger.intPtr()
+	b.minLength = bt.MinLength.intPtr()
+	b.maxLength = bt.MaxLength.intPtr()
 	b.refTable = bt.RefTable
 	b.refType = bt.RefType
 	return nil
 }
 
+// schemaInt is an integer bound of a base type, which some schema encoders
+// write as a string instead of a number
+type schemaInt int
+
+// UnmarshalJSON unmarshals an integer encoded as a JSON number or string
+func (i *schemaInt) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q in <base-type>", s)
+		}
+		*i = schemaInt(n)
+		return nil
+	}
+	var n int
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*i = schemaInt(n)
+	return nil
+}
+
+func (i *schemaInt) intPtr() *int {
+	if i == nil {
+		return nil
+	}
+	n := int(*i)
+	return &n
+}
+
 // MarshalJSON marshals a base type to JSON
 func (b BaseType) MarshalJSON() ([]byte, error) {
 	j := struct {
-		Type       string   `json:"type,omitempty"`
-		Enum       *OvsSet  `json:"enum,omitempty"`
-		MinReal    *float64 `json:"minReal,omitempty"`
-		MaxReal    *float64 `json:"maxReal,omitempty"`
-		MinInteger *int     `json:"minInteger,omitempty"`
-		MaxInteger *int     `json:"maxInteger,omitempty"`
-		MinLength  *int     `json:"minLength,omitempty"`
-		MaxLength  *int     `json:"maxLength,omitempty"`
-		RefTable   *string  `json:"refTable,omitempty"`
-		RefType    *RefType `json:"refType,omitempty"`
+		Type       string            `json:"type,omitempty"`
+		Enum       *OvsSet           `json:"enum,omitempty"`
+		EnumDoc    map[string]string `json:"enumDoc,omitempty"`
+		MinReal    *float64          `json:"minReal,omitempty"`
+		MaxReal    *float64          `json:"maxReal,omitempty"`
+		MinInteger *int              `json:"minInteger,omitempty"`
+		MaxInteger *int              `json:"maxInteger,omitempty"`
+		MinLength  *int              `json:"minLength,omitempty"`
+		MaxLength  *int              `json:"maxLength,omitempty"`
+		RefTable   *string           `json:"refTable,omitempty"`
+		RefType    *RefType          `json:"refType,omitempty"`
 	}{
 		Type:       b.Type,
+		EnumDoc:    b.EnumDoc,
 		MinReal:    b.minReal,
 		MaxReal:    b.maxReal,
 		MinInteger: b.minInteger,
 		MaxInteger: b.maxInteger,
-		MinLength:  b.maxLength,
+		MinLength:  b.minLength,
 		MaxLength:  b.maxLength,
 		RefTable:   b.refTable,
 		RefType:    b.refType,
@@ -482,10 +543,13 @@ type ColumnSchema struct {
 	// json message will be parsed manually and Type will indicate the "extended"
 	// type. Depending on its value, more information may be available in TypeObj.
 	// E.g: If Type == TypeEnum, TypeObj.Key.Enum contains the possible values
-	Type      ExtendedType
-	TypeObj   *ColumnType
-	ephemeral *bool
-	mutable   *bool
+	Type    ExtendedType
+	TypeObj *ColumnType
+	// Description documents the column. It is not part of RFC7047, but
+	// extended schema files can provide it as a "description" string
+	Description string
+	ephemeral   *bool
+	mutable     *bool
 }
 
 // Mutable returns whether a column is mutable
@@ -510,9 +574,10 @@ func (c *ColumnSchema) Ephemeral() bool {
 func (c *ColumnSchema) UnmarshalJSON(data []byte) error {
 	// ColumnJSON represents the known json values for a Column
 	var colJSON struct {
-		Type      *ColumnType `json:"type"`
-		Ephemeral *bool       `json:"ephemeral,omitempty"`
-		Mutable   *bool       `json:"mutable,omitempty"`
+		Type        *ColumnType `json:"type"`
+		Ephemeral   *bool       `json:"ephemeral,omitempty"`
+		Mutable     *bool       `json:"mutable,omitempty"`
+		Description string      `json:"description,omitempty"`
 	}
 
 	// Unmarshal known keys
@@ -523,6 +588,7 @@ func (c *ColumnSchema) UnmarshalJSON(data []byte) error {
 	c.ephemeral = colJSON.Ephemeral
 	c.mutable = colJSON.Mutable
 	c.TypeObj = colJSON.Type
+	c.Description = colJSON.Description
 
 	// Infer the ExtendedType from the TypeObj
 	if c.TypeObj.Value != nil {
@@ -540,14 +606,16 @@ func (c *ColumnSchema) UnmarshalJSON(data []byte) error {
 // MarshalJSON marshalls a column schema to JSON
 func (c ColumnSchema) MarshalJSON() ([]byte, error) {
 	type colJSON struct {
-		Type      *ColumnType `json:"type"`
-		Ephemeral *bool       `json:"ephemeral,omitempty"`
-		Mutable   *bool       `json:"mutable,omitempty"`
+		Type        *ColumnType `json:"type"`
+		Ephemeral   *bool       `json:"ephemeral,omitempty"`
+		Mutable     *bool       `json:"mutable,omitempty"`
+		Description string      `json:"description,omitempty"`
 	}
 	column := colJSON{
-		Type:      c.TypeObj,
-		Ephemeral: c.ephemeral,
-		Mutable:   c.mutable,
+		Type:        c.TypeObj,
+		Ephemeral:   c.ephemeral,
+		Mutable:     c.mutable,
+		Description: c.Description,
 	}
 	return json.Marshal(column)
 }
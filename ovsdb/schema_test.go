@@ -406,10 +406,40 @@ func TestTable(t *testing.T) {
 	})
 }
 
+func TestTableIndexes(t *testing.T) {
+	tableJ := []byte(`{
+		"columns": {
+			"name": {"type": "string"},
+			"logical_port": {"type": "string"},
+			"ip": {"type": "string"}
+		},
+		"indexes": [["name"], ["logical_port", "ip"]]
+	}`)
+	var table TableSchema
+	err := json.Unmarshal(tableJ, &table)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"name"}, {"logical_port", "ip"}}, table.Indexes)
+
+	raw, err := json.Marshal(table)
+	assert.NoError(t, err)
+	var indexes struct {
+		Indexes [][]string `json:"indexes"`
+	}
+	err = json.Unmarshal(raw, &indexes)
+	assert.NoError(t, err)
+	assert.Equal(t, table.Indexes, indexes.Indexes)
+
+	// an index is an array of columns, not a column
+	err = json.Unmarshal([]byte(`{"columns": {"name": {"type": "string"}}, "indexes": ["name"]}`), &table)
+	assert.Error(t, err)
+}
+
 func TestBaseTypeMarshalUnmarshalJSON(t *testing.T) {
 	datapath := "Datapath"
 	zero := 0
 	max := 4294967295
+	one := 1
+	sixtyFour := 64
 	strong := "strong"
 	tests := []struct {
 		name         string
@@ -467,6 +497,20 @@ func TestBaseTypeMarshalUnmarshalJSON(t *testing.T) {
 			[]byte(`{"type": "string","enum": ["set", ["OpenFlow10","OpenFlow11","OpenFlow12","OpenFlow13","OpenFlow14","OpenFlow15"]]}`),
 			false,
 		},
+		{
+			"integer enum",
+			[]byte(`{"type": "integer","enum": ["set", [1, 2, 3]]}`),
+			BaseType{Type: TypeInteger, Enum: []interface{}{int64(1), int64(2), int64(3)}},
+			[]byte(`{"type": "integer","enum": ["set", [1, 2, 3]]}`),
+			false,
+		},
+		{
+			"integer enum with single element",
+			[]byte(`{"type": "integer","enum": 5}`),
+			BaseType{Type: TypeInteger, Enum: []interface{}{int64(5)}},
+			[]byte(`{"type": "integer","enum": 5}`),
+			false,
+		},
 		{
 			"int with min and max",
 			[]byte(`{"type":"integer","minInteger":0,"maxInteger": 4294967295}`),
@@ -474,6 +518,20 @@ func TestBaseTypeMarshalUnmarshalJSON(t *testing.T) {
 			[]byte(`{"type":"integer","minInteger":0,"maxInteger": 4294967295}`),
 			false,
 		},
+		{
+			"int with min and max as strings",
+			[]byte(`{"type":"integer","minInteger":"0","maxInteger":"4294967295"}`),
+			BaseType{Type: TypeInteger, minInteger: &zero, maxInteger: &max},
+			[]byte(`{"type":"integer","minInteger":0,"maxInteger": 4294967295}`),
+			false,
+		},
+		{
+			"string with min and max length",
+			[]byte(`{"type":"string","minLength":1,"maxLength":64}`),
+			BaseType{Type: TypeString, minLength: &one, maxLength: &sixtyFour},
+			[]byte(`{"type":"string","minLength":1,"maxLength":64}`),
+			false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -659,6 +717,16 @@ func TestColumnSchemaMarshalUnmarshalJSON(t *testing.T) {
 			},
 			[]byte(`{"type": {"key": {"type": "string","enum": ["set", ["one", "two"]]},"max": 1,"min": 1}}`),
 		},
+		{
+			"description",
+			[]byte(`{"type": "string", "description": "The name of the switch."}`),
+			ColumnSchema{
+				Type:        TypeString,
+				TypeObj:     &ColumnType{Key: &BaseType{Type: TypeString}},
+				Description: "The name of the switch.",
+			},
+			[]byte(`{"type": "string", "description": "The name of the switch."}`),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -674,6 +742,21 @@ func TestColumnSchemaMarshalUnmarshalJSON(t *testing.T) {
 	}
 }
 
+func TestColumnSchemaStringIntegerBounds(t *testing.T) {
+	var c ColumnSchema
+	err := json.Unmarshal([]byte(`{"type": {"key": {"type": "integer", "minInteger": 0, "maxInteger": "4095"}, "min": 0, "max": 1}}`), &c)
+	assert.NoError(t, err)
+	min, err := c.TypeObj.Key.MinInteger()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, min)
+	max, err := c.TypeObj.Key.MaxInteger()
+	assert.NoError(t, err)
+	assert.Equal(t, 4095, max)
+
+	err = json.Unmarshal([]byte(`{"type": {"key": {"type": "integer", "maxInteger": "many"}}}`), &c)
+	assert.Error(t, err)
+}
+
 func TestBaseTypeSimpleAtomic(t *testing.T) {
 	b := BaseType{Type: TypeString}
 	assert.True(t, b.simpleAtomic())
@@ -5,6 +5,24 @@ package serverdb
 
 import "github.com/ovn-org/libovsdb/model"
 
+const (
+	// TableDatabase is the name of the Database table
+	TableDatabase = "Database"
+)
+
+// names of the columns of the Database table
+const (
+	DatabaseColumnUUID      = "_uuid"
+	DatabaseColumnCid       = "cid"
+	DatabaseColumnConnected = "connected"
+	DatabaseColumnIndex     = "index"
+	DatabaseColumnLeader    = "leader"
+	DatabaseColumnModel     = "model"
+	DatabaseColumnName      = "name"
+	DatabaseColumnSchema    = "schema"
+	DatabaseColumnSid       = "sid"
+)
+
 type (
 	DatabaseModel = string
 )
@@ -20,7 +38,7 @@ type Database struct {
 	UUID      string        `ovsdb:"_uuid"`
 	Cid       *string       `ovsdb:"cid"`
 	Connected bool          `ovsdb:"connected"`
-	Index     *int          `ovsdb:"index"`
+	Index     *int64        `ovsdb:"index"`
 	Leader    bool          `ovsdb:"leader"`
 	Model     DatabaseModel `ovsdb:"model"`
 	Name      string        `ovsdb:"name"`
@@ -46,7 +64,7 @@ func equalDatabaseCid(a, b *string) bool {
 	return *a == *b
 }
 
-func copyDatabaseIndex(a *int) *int {
+func copyDatabaseIndex(a *int64) *int64 {
 	if a == nil {
 		return nil
 	}
@@ -54,7 +72,7 @@ func copyDatabaseIndex(a *int) *int {
 	return &b
 }
 
-func equalDatabaseIndex(a, b *int) bool {
+func equalDatabaseIndex(a, b *int64) bool {
 	if (a == nil) != (b == nil) {
 		return false
 	}
@@ -140,5 +158,8 @@ func (a *Database) EqualsModel(b model.Model) bool {
 	return a.Equals(c)
 }
 
+func (a *Database) Normalize() {
+}
+
 var _ model.CloneableModel = &Database{}
 var _ model.ComparableModel = &Database{}
@@ -13,10 +13,17 @@ import (
 // FullDatabaseModel returns the DatabaseModel object to be used in libovsdb
 func FullDatabaseModel() (model.ClientDBModel, error) {
 	return model.NewClientDBModel("_Server", map[string]model.Model{
-		"Database": &Database{},
+		TableDatabase: &Database{},
 	})
 }
 
+// TableNames returns the names of the tables of the database, sorted
+func TableNames() []string {
+	return []string{
+		"Database",
+	}
+}
+
 var schema = `{
   "name": "_Server",
   "version": "1.2.0",
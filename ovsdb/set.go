@@ -21,6 +21,10 @@ type OvsSet struct {
 func NewOvsSet(obj interface{}) (OvsSet, error) {
 	var v reflect.Value
 	if reflect.TypeOf(obj).Kind() == reflect.Ptr {
+		// a nil pointer is an unset optional value, i.e. an empty set
+		if reflect.ValueOf(obj).IsNil() {
+			return OvsSet{GoSet: []interface{}{}}, nil
+		}
 		v = reflect.ValueOf(obj).Elem()
 	} else {
 		v = reflect.ValueOf(obj)
@@ -50,6 +54,11 @@ func NewOvsSet(obj interface{}) (OvsSet, error) {
 
 // MarshalJSON wil marshal an OVSDB style Set in to a JSON byte array
 func (o OvsSet) MarshalJSON() ([]byte, error) {
+	for _, elem := range o.GoSet {
+		if err := checkInteger(elem); err != nil {
+			return nil, err
+		}
+	}
 	switch l := len(o.GoSet); {
 	case l == 1:
 		return json.Marshal(o.GoSet[0])
@@ -81,15 +90,24 @@ func (o *OvsSet) UnmarshalJSON(b []byte) (err error) {
 	case []interface{}:
 		var oSet []interface{}
 		oSet = inter.([]interface{})
+		typeErr := &json.UnmarshalTypeError{Value: reflect.ValueOf(inter).String(), Type: reflect.TypeOf(*o)}
+		if len(oSet) != 2 {
+			// it is a slice, but is neither a uuid nor a set
+			return typeErr
+		}
 		// it's a single uuid object
-		if len(oSet) == 2 && (oSet[0] == "uuid" || oSet[0] == "named-uuid") {
-			return addToSet(o, UUID{GoUUID: oSet[1].(string)})
+		if oSet[0] == "uuid" || oSet[0] == "named-uuid" {
+			uuid, ok := oSet[1].(string)
+			if !ok {
+				return typeErr
+			}
+			return addToSet(o, UUID{GoUUID: uuid})
 		}
-		if oSet[0] != "set" {
+		innerSet, ok := oSet[1].([]interface{})
+		if oSet[0] != "set" || !ok {
 			// it is a slice, but is not a set
-			return &json.UnmarshalTypeError{Value: reflect.ValueOf(inter).String(), Type: reflect.TypeOf(*o)}
+			return typeErr
 		}
-		innerSet := oSet[1].([]interface{})
 		for _, val := range innerSet {
 			err := addToSet(o, val)
 			if err != nil {
@@ -6,6 +6,9 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var testUUIDs = []string{
@@ -169,3 +172,102 @@ func setify(i interface{}) []byte {
 	}
 	return []byte(fmt.Sprintf(`[ "set", [ "%s" ]]`, strings.Join(s, `","`)))
 }
+
+func TestNewOvsSetNilPointer(t *testing.T) {
+	var s *string
+	set, err := NewOvsSet(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set.GoSet) != 0 {
+		t.Fatalf("expected an empty set, got %v", set.GoSet)
+	}
+	b, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `["set",[]]` {
+		t.Fatalf("expected the empty set, got %s", b)
+	}
+}
+
+func TestOvsSetUUIDRoundTrip(t *testing.T) {
+	var column ColumnSchema
+	err := json.Unmarshal([]byte(`{"type": {"key": {"type": "uuid", "refTable": "Logical_Switch_Port"}, "min": 0, "max": "unlimited"}}`), &column)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		json   string
+		native []string
+	}{
+		{
+			name:   "empty",
+			json:   `{"ports":["set",[]]}`,
+			native: []string{},
+		},
+		{
+			name:   "one",
+			json:   `{"ports":["uuid","` + testUUIDs[0] + `"]}`,
+			native: []string{testUUIDs[0]},
+		},
+		{
+			name:   "many",
+			json:   `{"ports":["set",[["uuid","` + testUUIDs[0] + `"],["uuid","` + testUUIDs[1] + `"],["uuid","` + testUUIDs[2] + `"]]]}`,
+			native: testUUIDs[0:3],
+		},
+		{
+			name:   "named",
+			json:   `{"ports":["set",[["named-uuid","lsp0"],["uuid","` + testUUIDs[0] + `"]]]}`,
+			native: []string{"lsp0", testUUIDs[0]},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var row Row
+			err := json.Unmarshal([]byte(tt.json), &row)
+			require.NoError(t, err)
+			native, err := OvsToNative(&column, row["ports"])
+			require.NoError(t, err)
+			assert.Equal(t, tt.native, native)
+
+			ovs, err := NativeToOvs(&column, native)
+			require.NoError(t, err)
+			b, err := json.Marshal(Row{"ports": ovs})
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.json, string(b))
+		})
+	}
+
+	t.Run("single element set", func(t *testing.T) {
+		// a set holding one uuid is encoded as that uuid
+		var row Row
+		err := json.Unmarshal([]byte(`{"ports":["set",[["uuid","`+testUUIDs[0]+`"]]]}`), &row)
+		require.NoError(t, err)
+		assert.Equal(t, OvsSet{GoSet: []interface{}{UUID{GoUUID: testUUIDs[0]}}}, row["ports"])
+		b, err := json.Marshal(row)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"ports":["uuid","`+testUUIDs[0]+`"]}`, string(b))
+	})
+
+	t.Run("set of strings", func(t *testing.T) {
+		// strings looking like uuids are not uuids
+		var set OvsSet
+		err := json.Unmarshal(setify(testUUIDs[0:2]), &set)
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{testUUIDs[0], testUUIDs[1]}, set.GoSet)
+		b, err := json.Marshal(set)
+		require.NoError(t, err)
+		assert.JSONEq(t, `["set",["`+testUUIDs[0]+`","`+testUUIDs[1]+`"]]`, string(b))
+	})
+}
+
+func TestOvsSetUnmarshalJSONInvalid(t *testing.T) {
+	for _, in := range []string{`[]`, `["uuid"]`, `["uuid",3]`, `["set"]`, `["set",3]`, `["map",[]]`} {
+		t.Run(in, func(t *testing.T) {
+			var set OvsSet
+			err := json.Unmarshal([]byte(in), &set)
+			assert.Error(t, err)
+		})
+	}
+}
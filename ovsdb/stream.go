@@ -0,0 +1,126 @@
+package ovsdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// RowHandler handles a row of the result of a select operation, op being the
+// index of the operation in the transaction
+type RowHandler func(op int, row Row) error
+
+// DecodeOperationResults decodes the results of a transaction from r, like
+// unmarshaling them into a []OperationResult does, except that the rows of the
+// select results are passed to fn one at a time and in order, as soon as they
+// are decoded, instead of being accumulated in the Rows of the results. As a
+// single row is held at a time, the rows of huge selects can be processed in
+// bounded memory. An error returned by fn stops the decoding and is returned
+// as is
+func DecodeOperationResults(r io.Reader, fn RowHandler) ([]OperationResult, error) {
+	decoder := json.NewDecoder(r)
+	if err := expectDelim(decoder, '['); err != nil {
+		return nil, err
+	}
+	results := []OperationResult{}
+	for op := 0; decoder.More(); op++ {
+		result, err := decodeOperationResult(decoder, op, fn)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	if err := expectDelim(decoder, ']'); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// decodeOperationResult decodes the result of an operation, passing its rows
+// to fn
+func decodeOperationResult(decoder *json.Decoder, op int, fn RowHandler) (OperationResult, error) {
+	var result OperationResult
+	token, err := decoder.Token()
+	if err != nil {
+		return result, err
+	}
+	if token == nil {
+		// the operations following a failed one have a null result
+		return result, nil
+	}
+	if token != json.Delim('{') {
+		return result, fmt.Errorf("operation %d: expected an object, got %v", op, token)
+	}
+	members := map[string]json.RawMessage{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return result, err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return result, fmt.Errorf("operation %d: expected a member name, got %v", op, token)
+		}
+		if key != "rows" {
+			var member json.RawMessage
+			if err := decoder.Decode(&member); err != nil {
+				return result, err
+			}
+			members[key] = member
+			continue
+		}
+		if err := decodeRows(decoder, op, fn); err != nil {
+			return result, err
+		}
+	}
+	if err := expectDelim(decoder, '}'); err != nil {
+		return result, err
+	}
+	// decode the other members as usual
+	if len(members) > 0 {
+		b, err := json.Marshal(members)
+		if err != nil {
+			return result, err
+		}
+		if err := json.Unmarshal(b, &result); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// decodeRows decodes the rows of the result of an operation, passing them to fn
+func decodeRows(decoder *json.Decoder, op int, fn RowHandler) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return nil
+	}
+	if token != json.Delim('[') {
+		return fmt.Errorf("operation %d: expected an array of rows, got %v", op, token)
+	}
+	for decoder.More() {
+		var row Row
+		if err := decoder.Decode(&row); err != nil {
+			return err
+		}
+		if err := fn(op, row); err != nil {
+			return err
+		}
+	}
+	return expectDelim(decoder, ']')
+}
+
+// expectDelim reads the next token, failing if it is not the given delimiter
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected %v, got %v", delim, token)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package ovsdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// selectReply returns the reply of a transaction made of an insert and a
+// select returning the given number of rows
+func selectReply(rows int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`[{"uuid":["uuid","2f77b348-9768-4866-b761-89d5177ecdab"]},{"rows":[`)
+	for i := 0; i < rows; i++ {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		fmt.Fprintf(&buf, `{"name":"row%d","ports":["set",["p%d","q%d"]],"external_ids":["map",[["index","%d"]]]}`, i, i, i, i)
+	}
+	buf.WriteString(`]}]`)
+	return buf.Bytes()
+}
+
+func TestDecodeOperationResults(t *testing.T) {
+	data := selectReply(100)
+	var expected []OperationResult
+	err := json.Unmarshal(data, &expected)
+	require.NoError(t, err)
+
+	var ops []int
+	var rows []Row
+	results, err := DecodeOperationResults(bytes.NewReader(data), func(op int, row Row) error {
+		ops = append(ops, op)
+		rows = append(rows, row)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 100)
+	for i, row := range rows {
+		assert.Equal(t, 1, ops[i])
+		assert.Equal(t, fmt.Sprintf("row%d", i), row["name"])
+	}
+	assert.Equal(t, expected[1].Rows, rows)
+
+	// the rows are not accumulated in the results
+	expected[1].Rows = nil
+	assert.Equal(t, expected, results)
+}
+
+func TestDecodeOperationResultsFailures(t *testing.T) {
+	results, err := DecodeOperationResults(bytes.NewReader([]byte(`[{"count":1},{"error":"constraint violation","details":"foo"},null]`)),
+		func(int, Row) error {
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, []OperationResult{{Count: 1}, {Error: "constraint violation", Details: "foo", StructuredDetails: "foo"}, {}}, results)
+
+	stop := errors.New("stop")
+	var rows int
+	_, err = DecodeOperationResults(bytes.NewReader(selectReply(10)), func(int, Row) error {
+		rows++
+		if rows == 3 {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 3, rows)
+
+	_, err = DecodeOperationResults(bytes.NewReader([]byte(`{"rows":[]}`)), func(int, Row) error {
+		return nil
+	})
+	assert.Error(t, err)
+}
+
+// liveBytes returns the bytes of heap allocated and still in use after
+// running f, which keeps its result alive until then
+func liveBytes(f func() interface{}) uint64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	result := f()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	runtime.KeepAlive(result)
+	if after.HeapAlloc < before.HeapAlloc {
+		return 0
+	}
+	return after.HeapAlloc - before.HeapAlloc
+}
+
+func BenchmarkUnmarshalOperationResults(b *testing.B) {
+	data := selectReply(10000)
+	b.ReportAllocs()
+	var live uint64
+	for i := 0; i < b.N; i++ {
+		live += liveBytes(func() interface{} {
+			var results []OperationResult
+			if err := json.Unmarshal(data, &results); err != nil {
+				b.Fatal(err)
+			}
+			return results
+		})
+	}
+	b.ReportMetric(float64(live)/float64(b.N), "live-B/op")
+}
+
+func BenchmarkDecodeOperationResults(b *testing.B) {
+	data := selectReply(10000)
+	b.ReportAllocs()
+	var live uint64
+	for i := 0; i < b.N; i++ {
+		live += liveBytes(func() interface{} {
+			var count int
+			results, err := DecodeOperationResults(bytes.NewReader(data), func(int, Row) error {
+				count++
+				return nil
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+			return results
+		})
+	}
+	b.ReportMetric(float64(live)/float64(b.N), "live-B/op")
+}
@@ -17,7 +17,7 @@ func (t TableUpdates) AddTableUpdate(table string, update TableUpdate) {
 
 func (t TableUpdates) FromTableUpdates2(tu TableUpdates2) {
 	for k, v := range tu {
-		var u TableUpdate
+		u := make(TableUpdate)
 		u.FromTableUpdate2(v)
 		t[k] = u
 	}
@@ -29,10 +29,14 @@ func (u UUID) MarshalJSON() ([]byte, error) {
 // UnmarshalJSON will unmarshal a JSON encoded byte array to a OVSDB style UUID
 func (u *UUID) UnmarshalJSON(b []byte) (err error) {
 	var ovsUUID []string
-	if err := json.Unmarshal(b, &ovsUUID); err == nil {
-		u.GoUUID = ovsUUID[1]
+	if err := json.Unmarshal(b, &ovsUUID); err != nil {
+		return err
 	}
-	return err
+	if len(ovsUUID) != 2 || (ovsUUID[0] != "uuid" && ovsUUID[0] != "named-uuid") {
+		return fmt.Errorf("expected a uuid or named-uuid pair, got %s", b)
+	}
+	u.GoUUID = ovsUUID[1]
+	return nil
 }
 
 func (u UUID) validateUUID() error {
@@ -50,3 +54,9 @@ func (u UUID) validateUUID() error {
 func isNamed(uuid string) bool {
 	return len(uuid) > 0 && !validUUID.MatchString(uuid)
 }
+
+// IsNamedUUID returns whether uuid is a named UUID, which a transaction uses to
+// reference the rows it inserts, rather than the UUID of a row
+func IsNamedUUID(uuid string) bool {
+	return isNamed(uuid)
+}
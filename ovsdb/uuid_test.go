@@ -1,6 +1,9 @@
 package ovsdb
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+)
 
 func TestUUIDIsNamed(t *testing.T) {
 	tests := []struct {
@@ -29,6 +32,61 @@ func TestUUIDIsNamed(t *testing.T) {
 			if got := isNamed(tt.uuid); got != tt.want {
 				t.Errorf("UUID.Named() = %v, want %v", got, tt.want)
 			}
+			if got := IsNamedUUID(tt.uuid); got != tt.want {
+				t.Errorf("IsNamedUUID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUUIDUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    string
+		wantErr bool
+	}{
+		{
+			"uuid",
+			`["uuid","` + aUUID0 + `"]`,
+			aUUID0,
+			false,
+		},
+		{
+			"named-uuid",
+			`["named-uuid","foo"]`,
+			"foo",
+			false,
+		},
+		{
+			"too short",
+			`["uuid"]`,
+			"",
+			true,
+		},
+		{
+			"wrong tag",
+			`["set","foo"]`,
+			"",
+			true,
+		},
+		{
+			"not a pair",
+			`"foo"`,
+			"",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var u UUID
+			err := json.Unmarshal([]byte(tt.json), &u)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UUID.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if u.GoUUID != tt.want {
+				t.Errorf("UUID.UnmarshalJSON() = %v, want %v", u.GoUUID, tt.want)
+			}
 		})
 	}
 }
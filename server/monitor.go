@@ -7,6 +7,7 @@ import (
 
 	"github.com/cenkalti/rpc2"
 	"github.com/google/uuid"
+	"github.com/ovn-org/libovsdb/model"
 	"github.com/ovn-org/libovsdb/ovsdb"
 )
 
@@ -30,6 +31,9 @@ type monitor struct {
 	kind    monitorKind
 	request map[string]*ovsdb.MonitorRequest
 	client  *rpc2.Client
+	// dbModel is the model of the monitored database, used to evaluate the
+	// conditions of the request
+	dbModel model.DatabaseModel
 }
 
 type monitorKind int
@@ -40,36 +44,74 @@ const (
 	monitorKindConditionalSince
 )
 
-func newMonitor(id string, request map[string]*ovsdb.MonitorRequest, client *rpc2.Client) *monitor {
+func newMonitor(id string, request map[string]*ovsdb.MonitorRequest, client *rpc2.Client, dbModel model.DatabaseModel) *monitor {
 	m := &monitor{
 		id:      id,
 		kind:    monitorKindOriginal,
 		request: request,
 		client:  client,
+		dbModel: dbModel,
 	}
 	return m
 }
 
-func newConditionalMonitor(id string, request map[string]*ovsdb.MonitorRequest, client *rpc2.Client) *monitor {
+func newConditionalMonitor(id string, request map[string]*ovsdb.MonitorRequest, client *rpc2.Client, dbModel model.DatabaseModel) *monitor {
 	m := &monitor{
 		id:      id,
 		kind:    monitorKindConditional,
 		request: request,
 		client:  client,
+		dbModel: dbModel,
 	}
 	return m
 }
 
-func newConditionalSinceMonitor(id string, request map[string]*ovsdb.MonitorRequest, client *rpc2.Client) *monitor {
+func newConditionalSinceMonitor(id string, request map[string]*ovsdb.MonitorRequest, client *rpc2.Client, dbModel model.DatabaseModel) *monitor {
 	m := &monitor{
 		id:      id,
 		kind:    monitorKindConditional,
 		request: request,
 		client:  client,
+		dbModel: dbModel,
 	}
 	return m
 }
 
+// monitorSelect returns the select of a monitor request, which selects
+// everything when it is omitted
+func monitorSelect(request *ovsdb.MonitorRequest) ovsdb.MonitorSelect {
+	if request.Select == nil {
+		return ovsdb.MonitorSelect{}
+	}
+	return *request.Select
+}
+
+// initialRows returns the rows of a table populating a new monitor, keyed by
+// their UUID, unless the request does not select them. Only the rows matching
+// the conditions of the request are returned, restricted to the requested
+// columns and _uuid
+func initialRows(transaction *Transaction, table string, request *ovsdb.MonitorRequest) map[string]ovsdb.Row {
+	result := make(map[string]ovsdb.Row)
+	if !monitorSelect(request).Initial() {
+		return result
+	}
+	rows := transaction.Select(table, request.Where, nil)
+	for _, row := range rows.Rows {
+		uuid := row["_uuid"].(ovsdb.UUID).GoUUID
+		if len(request.Columns) > 0 {
+			selected := ovsdb.Row{"_uuid": row["_uuid"]}
+			for _, column := range request.Columns {
+				if value, ok := row[column]; ok {
+					selected[column] = value
+				}
+			}
+			row = selected
+		}
+		result[uuid] = row
+	}
+	return result
+}
+
 // Send will send an update if it matches the tables and monitor select arguments
 // we take the update by value (not reference) so we can mutate it in place before
 // queuing it for dispatch
@@ -127,6 +169,55 @@ func (m *monitor) Send3(id uuid.UUID, update ovsdb.TableUpdates2) {
 	}
 }
 
+// matches returns whether a row matches all the conditions of the request of
+// a table. A row that cannot be evaluated does not match
+func (m *monitor) matches(table string, row *ovsdb.Row) bool {
+	if row == nil {
+		return false
+	}
+	where := m.request[table].Where
+	if len(where) == 0 {
+		return true
+	}
+	tableSchema := m.dbModel.Schema.Table(table)
+	if tableSchema == nil {
+		return false
+	}
+	obj, err := m.dbModel.NewModel(table)
+	if err != nil {
+		return false
+	}
+	info, err := m.dbModel.NewModelInfo(obj)
+	if err != nil {
+		return false
+	}
+	if err := m.dbModel.Mapper.GetRowData(row, info); err != nil {
+		return false
+	}
+	for _, condition := range where {
+		column := tableSchema.Column(condition.Column)
+		if column == nil {
+			return false
+		}
+		nativeValue, err := ovsdb.OvsToNative(column, condition.Value)
+		if err != nil {
+			return false
+		}
+		value, err := info.FieldByColumn(condition.Column)
+		if err != nil {
+			return false
+		}
+		ok, err := condition.Function.Evaluate(value, nativeValue)
+		if err != nil || !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// filter removes the updates the monitor does not watch. A row whose update
+// makes it match the conditions of the request is sent as an insert, and one
+// whose update makes it no longer match them as a delete
 func (m *monitor) filter(update ovsdb.TableUpdates) {
 	// remove updates for tables that we aren't watching
 	if len(m.request) != 0 {
@@ -136,12 +227,23 @@ func (m *monitor) filter(update ovsdb.TableUpdates) {
 				continue
 			}
 			for uuid, row := range u {
+				oldMatch := m.matches(table, row.Old)
+				newMatch := m.matches(table, row.New)
+				switch {
+				case !oldMatch && !newMatch:
+					delete(u, uuid)
+					continue
+				case !oldMatch:
+					row.Old = nil
+				case !newMatch:
+					row.New = nil
+				}
 				switch {
-				case row.Insert() && m.request[table].Select.Insert():
+				case row.Insert() && monitorSelect(m.request[table]).Insert():
 					fallthrough
-				case row.Modify() && m.request[table].Select.Modify():
+				case row.Modify() && monitorSelect(m.request[table]).Modify():
 					fallthrough
-				case row.Delete() && m.request[table].Select.Delete():
+				case row.Delete() && monitorSelect(m.request[table]).Delete():
 					if len(m.request[table].Columns) > 0 {
 						cols := make(map[string]bool)
 						for _, c := range m.request[table].Columns {
@@ -176,6 +278,8 @@ func (m *monitor) filter(update ovsdb.TableUpdates) {
 	}
 }
 
+// filter2 is like filter, for the updates of the conditional monitors. The
+// conditions are evaluated against the Old and New rows of the updates
 func (m *monitor) filter2(update ovsdb.TableUpdates2) {
 	// remove updates for tables that we aren't watching
 	if len(m.request) != 0 {
@@ -185,12 +289,31 @@ func (m *monitor) filter2(update ovsdb.TableUpdates2) {
 				continue
 			}
 			for uuid, row := range u {
+				if len(m.request[table].Where) > 0 {
+					oldMatch := m.matches(table, row.Old)
+					newMatch := m.matches(table, row.New)
+					switch {
+					case !oldMatch && !newMatch:
+						delete(u, uuid)
+						continue
+					case !oldMatch && row.Insert == nil:
+						new := make(ovsdb.Row, len(*row.New))
+						for k, v := range *row.New {
+							new[k] = v
+						}
+						row = &ovsdb.RowUpdate2{Insert: &new, New: row.New}
+						update[table][uuid] = row
+					case !newMatch && row.Delete == nil:
+						row = &ovsdb.RowUpdate2{Delete: &ovsdb.Row{}, Old: row.Old}
+						update[table][uuid] = row
+					}
+				}
 				switch {
-				case row.Insert != nil && m.request[table].Select.Insert():
+				case row.Insert != nil && monitorSelect(m.request[table]).Insert():
 					fallthrough
-				case row.Modify != nil && m.request[table].Select.Modify():
+				case row.Modify != nil && monitorSelect(m.request[table]).Modify():
 					fallthrough
-				case row.Delete != nil && m.request[table].Select.Delete():
+				case row.Delete != nil && monitorSelect(m.request[table]).Delete():
 					if len(m.request[table].Columns) > 0 {
 						cols := make(map[string]bool)
 						for _, c := range m.request[table].Columns {
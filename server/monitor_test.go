@@ -3,8 +3,10 @@ package server
 import (
 	"testing"
 
+	"github.com/ovn-org/libovsdb/model"
 	"github.com/ovn-org/libovsdb/ovsdb"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMonitorFilter(t *testing.T) {
@@ -158,3 +160,83 @@ func TestMonitorFilter2(t *testing.T) {
 		})
 	}
 }
+
+func TestMonitorFilterConditions(t *testing.T) {
+	defDB, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{
+		"Open_vSwitch": &ovsType{},
+		"Bridge":       &bridgeType{}})
+	require.NoError(t, err)
+	schema, err := getSchema()
+	require.NoError(t, err)
+	dbModel, errs := model.NewDatabaseModel(schema, defDB)
+	require.Empty(t, errs)
+
+	request := map[string]*ovsdb.MonitorRequest{
+		"Bridge": {
+			Where:  []ovsdb.Condition{ovsdb.NewCondition("name", ovsdb.ConditionEqual, "foo")},
+			Select: ovsdb.NewDefaultMonitorSelect(),
+		},
+	}
+	foo := ovsdb.Row{"_uuid": ovsdb.UUID{GoUUID: "foo"}, "name": "foo"}
+	bar := ovsdb.Row{"_uuid": ovsdb.UUID{GoUUID: "foo"}, "name": "bar"}
+	tests := []struct {
+		name     string
+		update   *ovsdb.RowUpdate2
+		expected *ovsdb.RowUpdate2
+	}{
+		{
+			"insert not matching",
+			&ovsdb.RowUpdate2{Insert: &bar, New: &bar},
+			nil,
+		},
+		{
+			"insert matching",
+			&ovsdb.RowUpdate2{Insert: &foo, New: &foo},
+			&ovsdb.RowUpdate2{Insert: &foo},
+		},
+		{
+			"modify into the conditions",
+			&ovsdb.RowUpdate2{Modify: &ovsdb.Row{"name": "foo"}, Old: &bar, New: &foo},
+			&ovsdb.RowUpdate2{Insert: &foo},
+		},
+		{
+			"modify out of the conditions",
+			&ovsdb.RowUpdate2{Modify: &ovsdb.Row{"name": "bar"}, Old: &foo, New: &bar},
+			&ovsdb.RowUpdate2{Delete: &ovsdb.Row{}},
+		},
+		{
+			"delete not matching",
+			&ovsdb.RowUpdate2{Delete: &ovsdb.Row{}, Old: &bar},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monitor := newConditionalMonitor("test", request, nil, dbModel)
+			update := ovsdb.TableUpdates2{"Bridge": ovsdb.TableUpdate2{"foo": tt.update}}
+			update, err := deepCopy2(update)
+			require.NoError(t, err)
+			monitor.filter2(update)
+			if tt.expected == nil {
+				assert.NotContains(t, update["Bridge"], "foo")
+				return
+			}
+			require.Contains(t, update["Bridge"], "foo")
+			row := update["Bridge"]["foo"]
+			assert.Equal(t, tt.expected.Insert, row.Insert)
+			assert.Equal(t, tt.expected.Modify, row.Modify)
+			assert.Equal(t, tt.expected.Delete, row.Delete)
+
+			// the monitors of the original kind get the same updates
+			monitor = newMonitor("test", request, nil, dbModel)
+			update1 := make(ovsdb.TableUpdates)
+			update1.FromTableUpdates2(ovsdb.TableUpdates2{"Bridge": ovsdb.TableUpdate2{"foo": tt.update}})
+			update1, err = deepCopy(update1)
+			require.NoError(t, err)
+			monitor.filter(update1)
+			require.Contains(t, update1["Bridge"], "foo")
+			assert.Equal(t, tt.expected.Insert != nil, update1["Bridge"]["foo"].Insert())
+			assert.Equal(t, tt.expected.Delete != nil, update1["Bridge"]["foo"].Delete())
+		})
+	}
+}
@@ -30,6 +30,8 @@ type OvsdbServer struct {
 	modelsMutex  sync.RWMutex
 	monitors     map[*rpc2.Client]*connectionMonitors
 	monitorMutex sync.RWMutex
+	locks        map[string]*rpc2.Client
+	locksMutex   sync.Mutex
 	logger       logr.Logger
 }
 
@@ -44,6 +46,7 @@ func NewOvsdbServer(db Database, models ...model.DatabaseModel) (*OvsdbServer, e
 		modelsMutex:  sync.RWMutex{},
 		monitors:     make(map[*rpc2.Client]*connectionMonitors),
 		monitorMutex: sync.RWMutex{},
+		locks:        make(map[string]*rpc2.Client),
 		logger:       l,
 	}
 	o.modelsMutex.Lock()
@@ -65,9 +68,11 @@ func NewOvsdbServer(db Database, models ...model.DatabaseModel) (*OvsdbServer, e
 	o.srv.Handle("monitor_cond", o.MonitorCond)
 	o.srv.Handle("monitor_cond_since", o.MonitorCondSince)
 	o.srv.Handle("monitor_cancel", o.MonitorCancel)
+	o.srv.Handle("lock", o.Lock)
 	o.srv.Handle("steal", o.Steal)
 	o.srv.Handle("unlock", o.Unlock)
 	o.srv.Handle("echo", o.Echo)
+	o.srv.OnDisconnect(o.releaseLocks)
 	return o, nil
 }
 
@@ -154,6 +159,8 @@ type Transaction struct {
 	Model       model.DatabaseModel
 	DbName      string
 	Database    Database
+	// locks are the locks held by the client sending the transaction
+	locks map[string]struct{}
 }
 
 func (o *OvsdbServer) NewTransaction(model model.DatabaseModel, dbName string, database Database) Transaction {
@@ -213,7 +220,7 @@ func (o *OvsdbServer) Transact(client *rpc2.Client, args []json.RawMessage, repl
 		}
 		ops = append(ops, op)
 	}
-	response, updates := o.transact(db, ops)
+	response, updates := o.transactWithLocks(db, ops, o.heldLocks(client))
 	*reply = response
 	transactionID := uuid.New()
 	o.processMonitors(transactionID, updates)
@@ -230,6 +237,8 @@ func deepCopy(a ovsdb.TableUpdates) (ovsdb.TableUpdates, error) {
 	return b, err
 }
 
+// deepCopy2 copies the updates, including the Old and New rows the monitors
+// evaluate their conditions against, which are not marshaled
 func deepCopy2(a ovsdb.TableUpdates2) (ovsdb.TableUpdates2, error) {
 	var b ovsdb.TableUpdates2
 	raw, err := json.Marshal(a)
@@ -237,7 +246,33 @@ func deepCopy2(a ovsdb.TableUpdates2) (ovsdb.TableUpdates2, error) {
 		return b, err
 	}
 	err = json.Unmarshal(raw, &b)
-	return b, err
+	if err != nil {
+		return b, err
+	}
+	for table, rows := range a {
+		for uuid, row := range rows {
+			if b[table][uuid].Old, err = copyRow(row.Old); err != nil {
+				return b, err
+			}
+			if b[table][uuid].New, err = copyRow(row.New); err != nil {
+				return b, err
+			}
+		}
+	}
+	return b, nil
+}
+
+func copyRow(a *ovsdb.Row) (*ovsdb.Row, error) {
+	if a == nil {
+		return nil, nil
+	}
+	var b ovsdb.Row
+	raw, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(raw, &b)
+	return &b, err
 }
 
 // Cancel cancels the last transaction
@@ -277,18 +312,17 @@ func (o *OvsdbServer) Monitor(client *rpc2.Client, args []json.RawMessage, reply
 
 	tableUpdates := make(ovsdb.TableUpdates)
 	for t, request := range request {
-		rows := transaction.Select(t, nil, request.Columns)
-		for i := range rows.Rows {
+		for uuid, row := range initialRows(&transaction, t, request) {
+			row := row
 			tu := make(ovsdb.TableUpdate)
-			uuid := rows.Rows[i]["_uuid"].(ovsdb.UUID).GoUUID
 			tu[uuid] = &ovsdb.RowUpdate{
-				New: &rows.Rows[i],
+				New: &row,
 			}
 			tableUpdates.AddTableUpdate(t, tu)
 		}
 	}
 	*reply = tableUpdates
-	o.monitors[client].monitors[value] = newMonitor(value, request, client)
+	o.monitors[client].monitors[value] = newMonitor(value, request, client, dbModel)
 	return nil
 }
 
@@ -324,16 +358,15 @@ func (o *OvsdbServer) MonitorCond(client *rpc2.Client, args []json.RawMessage, r
 
 	tableUpdates := make(ovsdb.TableUpdates2)
 	for t, request := range request {
-		rows := transaction.Select(t, nil, request.Columns)
-		for i := range rows.Rows {
+		for uuid, row := range initialRows(&transaction, t, request) {
+			row := row
 			tu := make(ovsdb.TableUpdate2)
-			uuid := rows.Rows[i]["_uuid"].(ovsdb.UUID).GoUUID
-			tu[uuid] = &ovsdb.RowUpdate2{Initial: &rows.Rows[i]}
+			tu[uuid] = &ovsdb.RowUpdate2{Initial: &row}
 			tableUpdates.AddTableUpdate(t, tu)
 		}
 	}
 	*reply = tableUpdates
-	o.monitors[client].monitors[value] = newConditionalMonitor(value, request, client)
+	o.monitors[client].monitors[value] = newConditionalMonitor(value, request, client, dbModel)
 	return nil
 }
 
@@ -369,37 +402,127 @@ func (o *OvsdbServer) MonitorCondSince(client *rpc2.Client, args []json.RawMessa
 
 	tableUpdates := make(ovsdb.TableUpdates2)
 	for t, request := range request {
-		rows := transaction.Select(t, nil, request.Columns)
-		for i := range rows.Rows {
+		for uuid, row := range initialRows(&transaction, t, request) {
+			row := row
 			tu := make(ovsdb.TableUpdate2)
-			uuid := rows.Rows[i]["_uuid"].(ovsdb.UUID).GoUUID
-			tu[uuid] = &ovsdb.RowUpdate2{Initial: &rows.Rows[i]}
+			tu[uuid] = &ovsdb.RowUpdate2{Initial: &row}
 			tableUpdates.AddTableUpdate(t, tu)
 		}
 	}
 	*reply = ovsdb.MonitorCondSinceReply{Found: false, LastTransactionID: "00000000-0000-0000-000000000000", Updates: tableUpdates}
-	o.monitors[client].monitors[value] = newConditionalSinceMonitor(value, request, client)
+	o.monitors[client].monitors[value] = newConditionalSinceMonitor(value, request, client, dbModel)
 	return nil
 }
 
-// MonitorCancel cancels a monitor on a given table
-func (o *OvsdbServer) MonitorCancel(client *rpc2.Client, args []interface{}, reply *[]interface{}) error {
-	return fmt.Errorf("not implemented")
+// MonitorCancel cancels a monitor previously created by the client with the
+// same json-value, after which no update is sent for it
+func (o *OvsdbServer) MonitorCancel(client *rpc2.Client, args []json.RawMessage, reply *map[string]interface{}) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected a single monitor id, got %d arguments", len(args))
+	}
+	value := string(args[0])
+	o.monitorMutex.Lock()
+	defer o.monitorMutex.Unlock()
+	clientMonitors, ok := o.monitors[client]
+	if !ok {
+		return fmt.Errorf("unknown monitor")
+	}
+	if _, ok := clientMonitors.monitors[value]; !ok {
+		return fmt.Errorf("unknown monitor")
+	}
+	delete(clientMonitors.monitors, value)
+	*reply = map[string]interface{}{}
+	return nil
 }
 
-// Lock acquires a lock on a table for a the client
-func (o *OvsdbServer) Lock(client *rpc2.Client, args []interface{}, reply *[]interface{}) error {
-	return fmt.Errorf("not implemented")
+// Lock acquires a lock for the client, if no other client holds it. Lock
+// requests are not queued: a client that is not granted a lock must retry
+// later, as no locked notification is sent
+func (o *OvsdbServer) Lock(client *rpc2.Client, args []interface{}, reply *ovsdb.LockResult) error {
+	id, err := lockID(args)
+	if err != nil {
+		return err
+	}
+	o.locksMutex.Lock()
+	defer o.locksMutex.Unlock()
+	owner, ok := o.locks[id]
+	if !ok {
+		o.locks[id] = client
+		owner = client
+	}
+	*reply = ovsdb.LockResult{Locked: owner == client}
+	return nil
 }
 
-// Steal steals a lock for a client
-func (o *OvsdbServer) Steal(client *rpc2.Client, args []interface{}, reply *[]interface{}) error {
-	return fmt.Errorf("not implemented")
+// Steal acquires a lock for the client, taking it away from the client
+// holding it, if any, which is sent a stolen notification
+func (o *OvsdbServer) Steal(client *rpc2.Client, args []interface{}, reply *ovsdb.LockResult) error {
+	id, err := lockID(args)
+	if err != nil {
+		return err
+	}
+	o.locksMutex.Lock()
+	owner := o.locks[id]
+	o.locks[id] = client
+	o.locksMutex.Unlock()
+	if owner != nil && owner != client {
+		if err := owner.Notify("stolen", ovsdb.NewLockArgs(id)); err != nil {
+			log.Printf("error sending stolen notification for lock %s: %v", id, err)
+		}
+	}
+	*reply = ovsdb.LockResult{Locked: true}
+	return nil
 }
 
 // Unlock releases a lock for a client
-func (o *OvsdbServer) Unlock(client *rpc2.Client, args []interface{}, reply *[]interface{}) error {
-	return fmt.Errorf("not implemented")
+func (o *OvsdbServer) Unlock(client *rpc2.Client, args []interface{}, reply *map[string]interface{}) error {
+	id, err := lockID(args)
+	if err != nil {
+		return err
+	}
+	o.locksMutex.Lock()
+	defer o.locksMutex.Unlock()
+	if o.locks[id] == client {
+		delete(o.locks, id)
+	}
+	*reply = map[string]interface{}{}
+	return nil
+}
+
+// lockID returns the lock id of the arguments of a lock, steal or unlock RPC
+func lockID(args []interface{}) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("expected a single lock id, got %d arguments", len(args))
+	}
+	id, ok := args[0].(string)
+	if !ok {
+		return "", fmt.Errorf("lock id %v is not a string", args[0])
+	}
+	return id, nil
+}
+
+// heldLocks returns the locks held by a client
+func (o *OvsdbServer) heldLocks(client *rpc2.Client) map[string]struct{} {
+	o.locksMutex.Lock()
+	defer o.locksMutex.Unlock()
+	locks := make(map[string]struct{})
+	for id, owner := range o.locks {
+		if owner == client {
+			locks[id] = struct{}{}
+		}
+	}
+	return locks
+}
+
+// releaseLocks releases the locks held by a client that disconnected
+func (o *OvsdbServer) releaseLocks(client *rpc2.Client) {
+	o.locksMutex.Lock()
+	defer o.locksMutex.Unlock()
+	for id, owner := range o.locks {
+		if owner == client {
+			delete(o.locks, id)
+		}
+	}
 }
 
 // Echo tests the liveness of the connection
@@ -416,7 +539,7 @@ func (o *OvsdbServer) processMonitors(id uuid.UUID, update ovsdb.TableUpdates2)
 		for _, m := range c.monitors {
 			switch m.kind {
 			case monitorKindOriginal:
-				var updates ovsdb.TableUpdates
+				updates := make(ovsdb.TableUpdates)
 				updates.FromTableUpdates2(update)
 				// Deep copy for every monitor since each one filters
 				// the update for relevant tables and removes items
@@ -150,3 +150,38 @@ func TestOvsdbServerMonitor(t *testing.T) {
 	}
 	assert.Equal(t, expected, reply)
 }
+
+func TestOvsdbServerMonitorCancel(t *testing.T) {
+	defDB, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{
+		"Open_vSwitch": &ovsType{},
+		"Bridge":       &bridgeType{}})
+	require.NoError(t, err)
+	schema, err := getSchema()
+	require.NoError(t, err)
+	ovsDB := NewInMemoryDatabase(map[string]model.ClientDBModel{"Open_vSwitch": defDB})
+	dbModel, errs := model.NewDatabaseModel(schema, defDB)
+	require.Empty(t, errs)
+	o, err := NewOvsdbServer(ovsDB, dbModel)
+	require.NoError(t, err)
+
+	db, err := json.Marshal("Open_vSwitch")
+	require.NoError(t, err)
+	value, err := json.Marshal("foo")
+	require.NoError(t, err)
+	rJSON, err := json.Marshal(map[string]ovsdb.MonitorRequest{"Bridge": {}})
+	require.NoError(t, err)
+	err = o.Monitor(nil, []json.RawMessage{db, value, rJSON}, &ovsdb.TableUpdates{})
+	require.NoError(t, err)
+	require.Len(t, o.monitors[nil].monitors, 1)
+
+	var reply map[string]interface{}
+	err = o.MonitorCancel(nil, []json.RawMessage{value}, &reply)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{}, reply)
+	assert.Empty(t, o.monitors[nil].monitors)
+
+	err = o.MonitorCancel(nil, []json.RawMessage{value}, &reply)
+	assert.EqualError(t, err, "unknown monitor")
+	err = o.MonitorCancel(nil, nil, &reply)
+	assert.Error(t, err)
+}
@@ -7,15 +7,23 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/ovn-org/libovsdb/cache"
+	"github.com/ovn-org/libovsdb/mapper"
 	"github.com/ovn-org/libovsdb/model"
 	"github.com/ovn-org/libovsdb/ovsdb"
 )
 
 func (o *OvsdbServer) transact(name string, operations []ovsdb.Operation) ([]ovsdb.OperationResult, ovsdb.TableUpdates2) {
+	return o.transactWithLocks(name, operations, nil)
+}
+
+// transactWithLocks runs a transaction on behalf of a client holding the given
+// locks, which assert operations check
+func (o *OvsdbServer) transactWithLocks(name string, operations []ovsdb.Operation, locks map[string]struct{}) ([]ovsdb.OperationResult, ovsdb.TableUpdates2) {
 	o.modelsMutex.Lock()
 	dbModel := o.models[name]
 	o.modelsMutex.Unlock()
 	transaction := o.NewTransaction(dbModel, name, o.db)
+	transaction.locks = locks
 
 	results := []ovsdb.OperationResult{}
 	updates := make(ovsdb.TableUpdates2)
@@ -91,6 +99,14 @@ func (o *OvsdbServer) transact(name string, operations []ovsdb.Operation) ([]ovs
 		default:
 			return nil, updates
 		}
+		if results[len(results)-1].Error != "" {
+			// the transaction is aborted: nothing is committed and the
+			// remaining operations are not executed
+			for len(results) < len(operations) {
+				results = append(results, ovsdb.OperationResult{})
+			}
+			return results, make(ovsdb.TableUpdates2)
+		}
 	}
 	return results, updates
 }
@@ -171,6 +187,7 @@ func (t *Transaction) Insert(table string, rowUUID string, row ovsdb.Row) (ovsdb
 			}, nil
 		}
 	}
+	newVersion(mapperInfo)
 
 	resultRow, err := m.NewRow(mapperInfo)
 	if err != nil {
@@ -321,6 +338,11 @@ func (t *Transaction) Update(database, table string, where []ovsdb.Condition, ro
 				rowDelta[column] = diff
 			}
 		}
+		if len(rowDelta) > 0 {
+			if version, ok := newVersion(newInfo); ok {
+				rowDelta["_version"] = version
+			}
+		}
 
 		newRow, err := m.NewRow(newInfo)
 		if err != nil {
@@ -451,6 +473,11 @@ func (t *Transaction) Mutate(database, table string, where []ovsdb.Condition, mu
 				rowDelta[changed] = delta
 			}
 		}
+		if len(rowDelta) > 0 {
+			if version, ok := newVersion(newInfo); ok {
+				rowDelta["_version"] = version
+			}
+		}
 
 		// check indexes
 		if err := t.checkIndexes(table, new); err != nil {
@@ -625,13 +652,30 @@ func (t *Transaction) Abort(database, table string) ovsdb.OperationResult {
 }
 
 func (t *Transaction) Comment(database, table string, comment string) ovsdb.OperationResult {
-	e := ovsdb.NotSupported{}
-	return ovsdb.OperationResult{Error: e.Error()}
+	// comments are only meant to be logged, they always succeed rather than
+	// aborting the transaction
+	return ovsdb.OperationResult{}
 }
 
 func (t *Transaction) Assert(database, table, lock string) ovsdb.OperationResult {
-	e := ovsdb.NotSupported{}
-	return ovsdb.OperationResult{Error: e.Error()}
+	if _, ok := t.locks[lock]; !ok {
+		e := ovsdb.NotOwner{}
+		return ovsdb.OperationResult{Error: e.Error(), Details: fmt.Sprintf("lock %s is not held", lock)}
+	}
+	return ovsdb.OperationResult{}
+}
+
+// newVersion sets a new version in the _version field of the model, if it has
+// one, and returns it
+func newVersion(info *mapper.Info) (ovsdb.UUID, bool) {
+	if _, err := info.FieldByColumn("_version"); err != nil {
+		return ovsdb.UUID{}, false
+	}
+	version := uuid.NewString()
+	if err := info.SetField("_version", version); err != nil {
+		return ovsdb.UUID{}, false
+	}
+	return ovsdb.UUID{GoUUID: version}, true
 }
 
 func diff(a interface{}, b interface{}) interface{} {
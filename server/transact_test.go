@@ -717,3 +717,83 @@ func TestMultipleOps(t *testing.T) {
 	}, updates)
 
 }
+
+func TestTransactAbortsOnError(t *testing.T) {
+	defDB, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{
+		"Open_vSwitch": &ovsType{},
+		"Bridge":       &bridgeType{}})
+	require.NoError(t, err)
+	schema, err := getSchema()
+	require.NoError(t, err)
+	ovsDB := NewInMemoryDatabase(map[string]model.ClientDBModel{"Open_vSwitch": defDB})
+	dbModel, errs := model.NewDatabaseModel(schema, defDB)
+	require.Empty(t, errs)
+	o, err := NewOvsdbServer(ovsDB, dbModel)
+	require.NoError(t, err)
+
+	lock := "lock"
+	ops := []ovsdb.Operation{
+		{
+			Op:    ovsdb.OperationInsert,
+			Table: "Bridge",
+			Row:   ovsdb.Row{"name": "br0"},
+		},
+		{
+			Op:   ovsdb.OperationAssert,
+			Lock: &lock,
+		},
+		{
+			Op:    ovsdb.OperationInsert,
+			Table: "Bridge",
+			Row:   ovsdb.Row{"name": "br1"},
+		},
+	}
+	results, updates := o.transact("Open_vSwitch", ops)
+	require.Len(t, results, len(ops))
+	assert.Empty(t, results[0].Error)
+	assert.Equal(t, "not owner", results[1].Error)
+	// the following operations are not executed
+	assert.Equal(t, ovsdb.OperationResult{}, results[2])
+	// and nothing is committed
+	assert.Empty(t, updates)
+
+	results, updates = o.transactWithLocks("Open_vSwitch", ops, map[string]struct{}{lock: {}})
+	require.Len(t, results, len(ops))
+	for _, result := range results {
+		assert.Empty(t, result.Error)
+	}
+	assert.Len(t, updates["Bridge"], 2)
+}
+
+func TestTransactComment(t *testing.T) {
+	defDB, err := model.NewClientDBModel("Open_vSwitch", map[string]model.Model{
+		"Open_vSwitch": &ovsType{},
+		"Bridge":       &bridgeType{}})
+	require.NoError(t, err)
+	schema, err := getSchema()
+	require.NoError(t, err)
+	ovsDB := NewInMemoryDatabase(map[string]model.ClientDBModel{"Open_vSwitch": defDB})
+	dbModel, errs := model.NewDatabaseModel(schema, defDB)
+	require.Empty(t, errs)
+	o, err := NewOvsdbServer(ovsDB, dbModel)
+	require.NoError(t, err)
+
+	comment := "adding br0"
+	ops := []ovsdb.Operation{
+		{
+			Op:      ovsdb.OperationComment,
+			Comment: &comment,
+		},
+		{
+			Op:    ovsdb.OperationInsert,
+			Table: "Bridge",
+			Row:   ovsdb.Row{"name": "br0"},
+		},
+	}
+	results, updates := o.transact("Open_vSwitch", ops)
+	require.Len(t, results, len(ops))
+	// the comment succeeds, and does not abort the transaction
+	assert.Equal(t, ovsdb.OperationResult{}, results[0])
+	assert.Empty(t, results[1].Error)
+	assert.Len(t, updates["Bridge"], 1)
+}